@@ -1,60 +1,129 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"os"
+	"runtime/debug"
+	"strconv"
 
 	"github.com/corel-frim/item-packer-inc/api/handlers"
+	"github.com/corel-frim/item-packer-inc/internal/models"
 	"github.com/corel-frim/item-packer-inc/internal/storage"
 	"github.com/gofiber/contrib/swagger"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/log"
+	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/filesystem"
 	"github.com/gofiber/fiber/v2/middleware/healthcheck"
+	"github.com/gofiber/fiber/v2/middleware/pprof"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 )
 
 type API struct {
 	orders *handlers.Orders
 	packs  *handlers.Packs
+	admin  *handlers.Admin
+	calc   *handlers.Calculator
+	stats  *handlers.Stats
+	app    *fiber.App
+	// network and address are the LISTEN_NETWORK and LISTEN_ADDRESS values, empty for the defaults
+	network string
+	address string
 }
 
-func NewAPI(storage *storage.PackStorage) *API {
-	return &API{
+func NewAPI(storage storage.Store) *API {
+	api := &API{
 		orders: handlers.NewOrders(storage),
 		packs:  handlers.NewPacks(storage),
+		// Admin endpoints are disabled unless ADMIN_API_KEY is set
+		admin: handlers.NewAdmin(storage, os.Getenv("ADMIN_API_KEY")),
+		calc:  handlers.NewCalculator(),
+		stats: handlers.NewStats(storage),
+		// The server listens on TCP port 8080 unless LISTEN_NETWORK and LISTEN_ADDRESS say otherwise
+		network: os.Getenv("LISTEN_NETWORK"),
+		address: os.Getenv("LISTEN_ADDRESS"),
 	}
+	api.app = api.newApp()
+
+	return api
+}
+
+// Start starts listening for requests, it blocks until the server is shut down.
+// It fails right away if the network or the address is invalid.
+func (api *API) Start() error {
+	network, address, err := listenConfig(api.network, api.address)
+	if err != nil {
+		return err
+	}
+	listener, err := listen(network, address)
+	if err != nil {
+		return err
+	}
+	return api.app.Listener(listener)
 }
 
-func (api *API) Start() {
-	app := fiber.New()
-	app.Use(recover.New())
+// Shutdown gracefully shuts down the server waiting for active requests to finish.
+// The socket file of a Unix socket is removed afterwards.
+func (api *API) Shutdown() error {
+	return errors.Join(api.app.Shutdown(), removeSocket(api.network, api.address))
+}
+
+func (api *API) newApp() *fiber.App {
+	limit := bodyLimit(os.Getenv("BODY_LIMIT"))
+	app := fiber.New(fiber.Config{
+		BodyLimit:    limit,
+		ErrorHandler: errorHandler(limit),
+	})
+	// Every response gets an X-Request-ID header to match the client errors with the logs
+	app.Use(requestid.New())
+	app.Use(recover.New(recover.Config{
+		EnableStackTrace:  true,
+		StackTraceHandler: logPanic,
+	}))
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
 		AllowMethods: "*",
 		AllowHeaders: "*",
 	}))
+	// Responses are only compressed for clients sending Accept-Encoding
+	app.Use(compress.New(compress.Config{
+		Next: func(c *fiber.Ctx) bool {
+			// Streamed responses must reach the client as they're written
+			return c.Path() == "/orders/stream"
+		},
+		Level: compressionLevel(os.Getenv("COMPRESSION_LEVEL")),
+	}))
 	app.Use(healthcheck.New(healthcheck.Config{
 		LivenessEndpoint:  "/live",
 		ReadinessEndpoint: "/ready",
 	}))
 
-	swaggerPath := "./docs/swagger.json"
+	swaggerPath := "./docs/swagger/swagger.json"
 	if envPath := os.Getenv("SWAGGER_PATH"); envPath != "" {
 		swaggerPath = envPath
 	}
 	app.Use(swagger.New(swagger.Config{
 		Next: func(_ *fiber.Ctx) bool {
-			return os.Getenv("APP_ENV") == "production"
+			return !swaggerEnabled()
 		},
 		Path:     "/swagger",
 		FilePath: swaggerPath,
 		Title:    "Item Packer API",
 	}))
 
+	// The profiles of net/http/pprof are served under /debug/pprof only with ENABLE_PPROF=true
+	if pprofEnabled() {
+		app.Use(pprof.New())
+	}
+
 	// Register API routes before serving static files
 	api.RegisterRoutes(app)
+	// Wrong methods on the API paths get 405 rather than the frontend
+	app.Use(allowedMethods(app.GetRoutes(true)))
 
 	// Serve static files from the frontend directory
 	app.Use("/", filesystem.New(filesystem.Config{
@@ -64,10 +133,105 @@ func (api *API) Start() {
 		NotFoundFile: "index.html",
 	}))
 
-	log.Fatal(app.Listen(":8080"))
+	return app
 }
 
 func (api *API) RegisterRoutes(app *fiber.App) {
 	api.orders.RegisterRoutes(app)
 	api.packs.RegisterRoutes(app)
+	api.admin.RegisterRoutes(app)
+	api.calc.RegisterRoutes(app)
+	api.stats.RegisterRoutes(app)
+}
+
+// compressionLevel maps the COMPRESSION_LEVEL value to a compression level, unknown values fall back to the default
+func compressionLevel(value string) compress.Level {
+	switch value {
+	case "", "default":
+		return compress.LevelDefault
+	case "disabled":
+		return compress.LevelDisabled
+	case "best-speed":
+		return compress.LevelBestSpeed
+	case "best-compression":
+		return compress.LevelBestCompression
+	default:
+		log.Warnf("Unknown COMPRESSION_LEVEL %q, using the default level", value)
+		return compress.LevelDefault
+	}
+}
+
+// bodyLimit maps the BODY_LIMIT value to the max request body size in bytes, invalid values fall back to the default
+func bodyLimit(value string) int {
+	if value == "" {
+		return fiber.DefaultBodyLimit
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		log.Warnf("Invalid BODY_LIMIT %q, using the default limit of %d bytes", value, fiber.DefaultBodyLimit)
+		return fiber.DefaultBodyLimit
+	}
+	return limit
+}
+
+// errorHandler responds to the errors returned by the handlers and the middlewares, including recovered panics,
+// with an APIError. Unexpected errors get a generic 500 which doesn't leak their details.
+func errorHandler(limit int) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		response := models.APIError{Error: "Internal server error", RequestID: requestID(c)}
+		status := fiber.StatusInternalServerError
+
+		var fiberErr *fiber.Error
+		switch {
+		case errors.As(err, &fiberErr) && fiberErr.Code == fiber.StatusRequestEntityTooLarge:
+			status = fiberErr.Code
+			response.Error = fmt.Sprintf("Request body too large, the limit is %d bytes", limit)
+		case errors.As(err, &fiberErr):
+			status = fiberErr.Code
+			response.Error = fiberErr.Message
+		default:
+			log.Errorf("Request %s failed: %v", response.RequestID, err)
+		}
+
+		return c.Status(status).JSON(response)
+	}
+}
+
+// logPanic logs a panic recovered while handling a request with its stack and the request ID
+func logPanic(c *fiber.Ctx, e any) {
+	log.Errorf("Panic handling %s %s, request %s: %v\n%s", c.Method(), c.Path(), requestID(c), e, debug.Stack())
+}
+
+// requestID returns the ID assigned to the request by the requestid middleware, empty if there is none
+func requestID(c *fiber.Ctx) string {
+	id, _ := c.Locals(requestid.ConfigDefault.ContextKey).(string)
+	return id
+}
+
+// swaggerEnabled tells whether the swagger UI is served. ENABLE_SWAGGER takes precedence,
+// otherwise it's disabled in production only.
+func swaggerEnabled() bool {
+	if value := os.Getenv("ENABLE_SWAGGER"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err == nil {
+			return enabled
+		}
+		log.Warnf("Invalid ENABLE_SWAGGER %q, falling back to APP_ENV", value)
+	}
+	return os.Getenv("APP_ENV") != "production"
+}
+
+// pprofEnabled tells whether the profiling endpoints are served. They expose the internals of the server,
+// so they're disabled unless ENABLE_PPROF is true, in production too.
+func pprofEnabled() bool {
+	value := os.Getenv("ENABLE_PPROF")
+	if value == "" {
+		return false
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Warnf("Invalid ENABLE_PPROF %q, profiling stays disabled", value)
+		return false
+	}
+	return enabled
 }
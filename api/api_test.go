@@ -0,0 +1,298 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/internal/storage"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressionLevel(t *testing.T) {
+	tests := map[string]compress.Level{
+		"":                 compress.LevelDefault,
+		"default":          compress.LevelDefault,
+		"disabled":         compress.LevelDisabled,
+		"best-speed":       compress.LevelBestSpeed,
+		"best-compression": compress.LevelBestCompression,
+		"fastest":          compress.LevelDefault,
+	}
+
+	for value, level := range tests {
+		assert.Equal(t, level, compressionLevel(value), value)
+	}
+}
+
+func TestSwaggerEnabled(t *testing.T) {
+	tests := []struct {
+		name          string
+		appEnv        string
+		enableSwagger string
+		enabled       bool
+	}{
+		{"default", "", "", true},
+		{"production", "production", "", false},
+		{"enabled in production", "production", "true", true},
+		{"disabled outside production", "staging", "false", false},
+		{"invalid falls back to APP_ENV", "production", "sometimes", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("APP_ENV", tt.appEnv)
+			t.Setenv("ENABLE_SWAGGER", tt.enableSwagger)
+			assert.Equal(t, tt.enabled, swaggerEnabled())
+		})
+	}
+}
+
+func TestPprofEnabled(t *testing.T) {
+	tests := map[string]bool{
+		"":      false,
+		"true":  true,
+		"1":     true,
+		"false": false,
+		"yes":   false,
+	}
+
+	for value, enabled := range tests {
+		t.Setenv("ENABLE_PPROF", value)
+		assert.Equal(t, enabled, pprofEnabled(), value)
+	}
+}
+
+func TestPprof(t *testing.T) {
+	t.Setenv("SWAGGER_PATH", "../docs/swagger/swagger.json")
+	for _, enabled := range []bool{true, false} {
+		t.Setenv("ENABLE_PPROF", strconv.FormatBool(enabled))
+		api := NewAPI(storage.NewPackStorage())
+
+		resp, err := api.app.Test(httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+		assert.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, enabled, strings.Contains(string(body), "Types of profiles available"), "enabled %t", enabled)
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	tests := map[string]int{
+		"":      fiber.DefaultBodyLimit,
+		"1024":  1024,
+		"0":     fiber.DefaultBodyLimit,
+		"-1":    fiber.DefaultBodyLimit,
+		"large": fiber.DefaultBodyLimit,
+	}
+
+	for value, limit := range tests {
+		assert.Equal(t, limit, bodyLimit(value), value)
+	}
+}
+
+func TestBodyLimitExceeded(t *testing.T) {
+	t.Setenv("BODY_LIMIT", "16")
+	t.Setenv("SWAGGER_PATH", "../docs/swagger/swagger.json")
+	api := NewAPI(storage.NewPackStorage())
+
+	// The limit is enforced while reading the request, before app.Test can see the response, so serve for real
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() { _ = api.app.Listener(listener) }()
+	t.Cleanup(func() { _ = api.Shutdown() })
+	url := "http://" + listener.Addr().String()
+
+	resp, err := http.Post(url+"/orders", fiber.MIMEApplicationJSON, strings.NewReader(`[{"items": 1}, {"items": 2}, {"items": 3}]`))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, fiber.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	var body map[string]string
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "Request body too large, the limit is 16 bytes", body["error"])
+
+	// Bodies under the limit are accepted
+	resp, err = http.Post(url+"/packs/validate", fiber.MIMEApplicationJSON, strings.NewReader(`[250]`))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestListenConfig(t *testing.T) {
+	tests := []struct {
+		network, address         string
+		wantNetwork, wantAddress string
+		wantErr                  bool
+	}{
+		{"", "", "tcp", ":8080", false},
+		{"tcp", "127.0.0.1:9090", "tcp", "127.0.0.1:9090", false},
+		{"tcp6", "[::1]:8080", "tcp6", "[::1]:8080", false},
+		{"tcp4", "", "tcp4", ":8080", false},
+		{"unix", "/run/packer.sock", "unix", "/run/packer.sock", false},
+		{"unix", "", "", "", true},
+		{"udp", ":8080", "", "", true},
+	}
+
+	for _, tt := range tests {
+		network, address, err := listenConfig(tt.network, tt.address)
+		if tt.wantErr {
+			assert.Error(t, err, "%s %s", tt.network, tt.address)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tt.wantNetwork, network)
+		assert.Equal(t, tt.wantAddress, address)
+	}
+}
+
+func TestStartUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "api.sock")
+	t.Setenv("LISTEN_NETWORK", "unix")
+	t.Setenv("LISTEN_ADDRESS", socket)
+	t.Setenv("SWAGGER_PATH", "../docs/swagger/swagger.json")
+
+	// A socket left behind by a crashed server doesn't block the start
+	stale, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	assert.NoError(t, stale.Close())
+
+	api := NewAPI(storage.NewPackStorage())
+	started := make(chan error, 1)
+	go func() { started <- api.Start() }()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	assert.Eventually(t, func() bool {
+		resp, err := client.Get("http://packer/live")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+
+	// The socket file is removed on shutdown
+	assert.NoError(t, api.Shutdown())
+	assert.NoError(t, <-started)
+	_, err = os.Stat(socket)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestStartInvalidNetwork(t *testing.T) {
+	t.Setenv("LISTEN_NETWORK", "udp")
+	t.Setenv("SWAGGER_PATH", "../docs/swagger/swagger.json")
+	assert.ErrorIs(t, NewAPI(storage.NewPackStorage()).Start(), errUnknownNetwork)
+}
+
+func TestRecoveredPanic(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: errorHandler(fiber.DefaultBodyLimit)})
+	app.Use(requestid.New())
+	app.Use(recover.New(recover.Config{EnableStackTrace: true, StackTraceHandler: logPanic}))
+	app.Get("/panic", func(_ *fiber.Ctx) error {
+		panic("secret details")
+	})
+	app.Get("/teapot", func(_ *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusTeapot, "No coffee")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/panic", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+	var body models.APIError
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "Internal server error", body.Error)
+	assert.NotEmpty(t, body.RequestID)
+	assert.Equal(t, resp.Header.Get(fiber.HeaderXRequestID), body.RequestID)
+
+	// Errors of known statuses keep their message
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/teapot", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusTeapot, resp.StatusCode)
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "No coffee", body.Error)
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	t.Setenv("SWAGGER_PATH", "../docs/swagger/swagger.json")
+	t.Setenv("ADMIN_API_KEY", "secret")
+	api := NewAPI(storage.NewPackStorage())
+
+	tests := []struct {
+		method string
+		path   string
+		allow  string
+	}{
+		{http.MethodGet, "/orders/items/5", "POST, OPTIONS"},
+		{http.MethodDelete, "/orders", "GET, HEAD, POST, OPTIONS"},
+		{http.MethodPut, "/packs/recommend/", "DELETE, GET, HEAD, POST, OPTIONS"},
+		{http.MethodGet, "/PACKS/5/pin", "DELETE, POST, PUT, OPTIONS"},
+		{http.MethodPut, "/calculate", "POST, OPTIONS"},
+		{http.MethodDelete, "/admin/snapshot", "GET, HEAD, OPTIONS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("X-Admin-Key", "secret")
+			resp, err := api.app.Test(req)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+			assert.Equal(t, tt.allow, resp.Header.Get(fiber.HeaderAllow))
+
+			var body models.APIError
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, "Method Not Allowed", body.Error)
+		})
+	}
+
+	// No route handles PATCH
+	for _, route := range api.app.GetRoutes(true) {
+		path := route.Path
+		for _, param := range route.Params {
+			path = strings.Replace(path, ":"+param, "1", 1)
+		}
+		req := httptest.NewRequest(http.MethodPatch, path, nil)
+		req.Header.Set("X-Admin-Key", "secret")
+		resp, err := api.app.Test(req)
+		assert.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode, route.Path)
+		assert.Contains(t, resp.Header.Get(fiber.HeaderAllow), route.Method, route.Path)
+	}
+
+	resp, err := api.app.Test(httptest.NewRequest(http.MethodOptions, "/packs", nil))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "GET, HEAD, OPTIONS", resp.Header.Get(fiber.HeaderAllow))
+
+	// The supported methods and other paths aren't affected
+	resp, err = api.app.Test(httptest.NewRequest(http.MethodPost, "/orders/items/5", nil))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp, err = api.app.Test(httptest.NewRequest(http.MethodPost, "/unknown", nil))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Empty(t, resp.Header.Get(fiber.HeaderAllow))
+}
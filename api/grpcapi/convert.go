@@ -0,0 +1,46 @@
+package grpcapi
+
+import (
+	"github.com/corel-frim/item-packer-inc/api/grpcapi/packerpb"
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func packToProto(pack *models.Pack) *packerpb.Pack {
+	return &packerpb.Pack{
+		Id:     int64(pack.ID),
+		Amount: int64(pack.Amount),
+		Pinned: pack.Pinned,
+		Label:  pack.Label,
+	}
+}
+
+func orderToProto(order models.Order) *packerpb.Order {
+	packs := make([]*packerpb.OrderPack, len(order.Packs))
+	for i, op := range order.Packs {
+		packs[i] = &packerpb.OrderPack{
+			Quantity: int64(op.Quantity),
+			Pack:     packToProto(op.Pack),
+			Subtotal: int64(op.Subtotal),
+		}
+	}
+	available := make([]int64, len(order.AvailablePacks))
+	for i, amount := range order.AvailablePacks {
+		available[i] = int64(amount)
+	}
+
+	return &packerpb.Order{
+		Id:               int64(order.ID),
+		RequestedItems:   int64(order.RequestedItems),
+		OverpackedItems:  int64(order.OverpackedItems),
+		OverpackPercent:  order.OverpackPercent,
+		TotalItems:       int64(order.TotalItems),
+		UnfulfilledItems: int64(order.UnfulfilledItems),
+		Packs:            packs,
+		CreatedAt:        timestamppb.New(order.CreatedAt),
+		Reference:        order.Reference,
+		Metadata:         order.Metadata,
+		Warnings:         order.Warnings,
+		AvailablePacks:   available,
+	}
+}
@@ -0,0 +1,889 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.32.1
+// source: packer.proto
+
+package packerpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Pack struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Amount        int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Pinned        bool                   `protobuf:"varint,3,opt,name=pinned,proto3" json:"pinned,omitempty"`
+	Label         string                 `protobuf:"bytes,4,opt,name=label,proto3" json:"label,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Pack) Reset() {
+	*x = Pack{}
+	mi := &file_packer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pack) ProtoMessage() {}
+
+func (x *Pack) ProtoReflect() protoreflect.Message {
+	mi := &file_packer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pack.ProtoReflect.Descriptor instead.
+func (*Pack) Descriptor() ([]byte, []int) {
+	return file_packer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Pack) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Pack) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Pack) GetPinned() bool {
+	if x != nil {
+		return x.Pinned
+	}
+	return false
+}
+
+func (x *Pack) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+type OrderPack struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Quantity      int64                  `protobuf:"varint,1,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Pack          *Pack                  `protobuf:"bytes,2,opt,name=pack,proto3" json:"pack,omitempty"`
+	Subtotal      int64                  `protobuf:"varint,3,opt,name=subtotal,proto3" json:"subtotal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderPack) Reset() {
+	*x = OrderPack{}
+	mi := &file_packer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderPack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderPack) ProtoMessage() {}
+
+func (x *OrderPack) ProtoReflect() protoreflect.Message {
+	mi := &file_packer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderPack.ProtoReflect.Descriptor instead.
+func (*OrderPack) Descriptor() ([]byte, []int) {
+	return file_packer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OrderPack) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderPack) GetPack() *Pack {
+	if x != nil {
+		return x.Pack
+	}
+	return nil
+}
+
+func (x *OrderPack) GetSubtotal() int64 {
+	if x != nil {
+		return x.Subtotal
+	}
+	return 0
+}
+
+type Order struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id is only set on recorded orders
+	Id              int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	RequestedItems  int64                  `protobuf:"varint,2,opt,name=requested_items,json=requestedItems,proto3" json:"requested_items,omitempty"`
+	OverpackedItems int64                  `protobuf:"varint,3,opt,name=overpacked_items,json=overpackedItems,proto3" json:"overpacked_items,omitempty"`
+	OverpackPercent float64                `protobuf:"fixed64,4,opt,name=overpack_percent,json=overpackPercent,proto3" json:"overpack_percent,omitempty"`
+	TotalItems      int64                  `protobuf:"varint,5,opt,name=total_items,json=totalItems,proto3" json:"total_items,omitempty"`
+	Packs           []*OrderPack           `protobuf:"bytes,6,rep,name=packs,proto3" json:"packs,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Reference       string                 `protobuf:"bytes,8,opt,name=reference,proto3" json:"reference,omitempty"`
+	Metadata        map[string]string      `protobuf:"bytes,9,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Warnings        []string               `protobuf:"bytes,10,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	AvailablePacks  []int64                `protobuf:"varint,11,rep,packed,name=available_packs,json=availablePacks,proto3" json:"available_packs,omitempty"`
+	// unfulfilled_items is the shortfall of the max-under strategy
+	UnfulfilledItems int64 `protobuf:"varint,12,opt,name=unfulfilled_items,json=unfulfilledItems,proto3" json:"unfulfilled_items,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_packer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_packer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_packer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Order) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Order) GetRequestedItems() int64 {
+	if x != nil {
+		return x.RequestedItems
+	}
+	return 0
+}
+
+func (x *Order) GetOverpackedItems() int64 {
+	if x != nil {
+		return x.OverpackedItems
+	}
+	return 0
+}
+
+func (x *Order) GetOverpackPercent() float64 {
+	if x != nil {
+		return x.OverpackPercent
+	}
+	return 0
+}
+
+func (x *Order) GetTotalItems() int64 {
+	if x != nil {
+		return x.TotalItems
+	}
+	return 0
+}
+
+func (x *Order) GetPacks() []*OrderPack {
+	if x != nil {
+		return x.Packs
+	}
+	return nil
+}
+
+func (x *Order) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Order) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *Order) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Order) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *Order) GetAvailablePacks() []int64 {
+	if x != nil {
+		return x.AvailablePacks
+	}
+	return nil
+}
+
+func (x *Order) GetUnfulfilledItems() int64 {
+	if x != nil {
+		return x.UnfulfilledItems
+	}
+	return 0
+}
+
+type ListPacksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPacksRequest) Reset() {
+	*x = ListPacksRequest{}
+	mi := &file_packer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPacksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPacksRequest) ProtoMessage() {}
+
+func (x *ListPacksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPacksRequest.ProtoReflect.Descriptor instead.
+func (*ListPacksRequest) Descriptor() ([]byte, []int) {
+	return file_packer_proto_rawDescGZIP(), []int{3}
+}
+
+type ListPacksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Packs         []*Pack                `protobuf:"bytes,1,rep,name=packs,proto3" json:"packs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPacksResponse) Reset() {
+	*x = ListPacksResponse{}
+	mi := &file_packer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPacksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPacksResponse) ProtoMessage() {}
+
+func (x *ListPacksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_packer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPacksResponse.ProtoReflect.Descriptor instead.
+func (*ListPacksResponse) Descriptor() ([]byte, []int) {
+	return file_packer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListPacksResponse) GetPacks() []*Pack {
+	if x != nil {
+		return x.Packs
+	}
+	return nil
+}
+
+type GetPackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Amount        int64                  `protobuf:"varint,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPackRequest) Reset() {
+	*x = GetPackRequest{}
+	mi := &file_packer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPackRequest) ProtoMessage() {}
+
+func (x *GetPackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPackRequest.ProtoReflect.Descriptor instead.
+func (*GetPackRequest) Descriptor() ([]byte, []int) {
+	return file_packer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetPackRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type AddPackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Amount        int64                  `protobuf:"varint,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddPackRequest) Reset() {
+	*x = AddPackRequest{}
+	mi := &file_packer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddPackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPackRequest) ProtoMessage() {}
+
+func (x *AddPackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPackRequest.ProtoReflect.Descriptor instead.
+func (*AddPackRequest) Descriptor() ([]byte, []int) {
+	return file_packer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AddPackRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type UpdatePackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OldAmount     int64                  `protobuf:"varint,1,opt,name=old_amount,json=oldAmount,proto3" json:"old_amount,omitempty"`
+	NewAmount     int64                  `protobuf:"varint,2,opt,name=new_amount,json=newAmount,proto3" json:"new_amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePackRequest) Reset() {
+	*x = UpdatePackRequest{}
+	mi := &file_packer_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePackRequest) ProtoMessage() {}
+
+func (x *UpdatePackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packer_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePackRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePackRequest) Descriptor() ([]byte, []int) {
+	return file_packer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdatePackRequest) GetOldAmount() int64 {
+	if x != nil {
+		return x.OldAmount
+	}
+	return 0
+}
+
+func (x *UpdatePackRequest) GetNewAmount() int64 {
+	if x != nil {
+		return x.NewAmount
+	}
+	return 0
+}
+
+type DeletePackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Amount        int64                  `protobuf:"varint,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePackRequest) Reset() {
+	*x = DeletePackRequest{}
+	mi := &file_packer_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePackRequest) ProtoMessage() {}
+
+func (x *DeletePackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packer_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePackRequest.ProtoReflect.Descriptor instead.
+func (*DeletePackRequest) Descriptor() ([]byte, []int) {
+	return file_packer_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeletePackRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type DeletePackResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePackResponse) Reset() {
+	*x = DeletePackResponse{}
+	mi := &file_packer_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePackResponse) ProtoMessage() {}
+
+func (x *DeletePackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_packer_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePackResponse.ProtoReflect.Descriptor instead.
+func (*DeletePackResponse) Descriptor() ([]byte, []int) {
+	return file_packer_proto_rawDescGZIP(), []int{9}
+}
+
+type CalculateOrderRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Items int64                  `protobuf:"varint,1,opt,name=items,proto3" json:"items,omitempty"`
+	// strategy is one of greedy, min-overpack, fewest-sizes, distinct-packs or max-under, the default strategy if empty
+	Strategy      string            `protobuf:"bytes,2,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	Reference     string            `protobuf:"bytes,3,opt,name=reference,proto3" json:"reference,omitempty"`
+	Metadata      map[string]string `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalculateOrderRequest) Reset() {
+	*x = CalculateOrderRequest{}
+	mi := &file_packer_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculateOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateOrderRequest) ProtoMessage() {}
+
+func (x *CalculateOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packer_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateOrderRequest.ProtoReflect.Descriptor instead.
+func (*CalculateOrderRequest) Descriptor() ([]byte, []int) {
+	return file_packer_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CalculateOrderRequest) GetItems() int64 {
+	if x != nil {
+		return x.Items
+	}
+	return 0
+}
+
+func (x *CalculateOrderRequest) GetStrategy() string {
+	if x != nil {
+		return x.Strategy
+	}
+	return ""
+}
+
+func (x *CalculateOrderRequest) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *CalculateOrderRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type ListOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersRequest) Reset() {
+	*x = ListOrdersRequest{}
+	mi := &file_packer_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersRequest) ProtoMessage() {}
+
+func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_packer_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
+func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_packer_proto_rawDescGZIP(), []int{11}
+}
+
+type ListOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersResponse) Reset() {
+	*x = ListOrdersResponse{}
+	mi := &file_packer_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersResponse) ProtoMessage() {}
+
+func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_packer_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
+func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_packer_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListOrdersResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+var File_packer_proto protoreflect.FileDescriptor
+
+const file_packer_proto_rawDesc = "" +
+	"\n" +
+	"\fpacker.proto\x12\tpacker.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\\\n" +
+	"\x04Pack\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12\x16\n" +
+	"\x06pinned\x18\x03 \x01(\bR\x06pinned\x12\x14\n" +
+	"\x05label\x18\x04 \x01(\tR\x05label\"h\n" +
+	"\tOrderPack\x12\x1a\n" +
+	"\bquantity\x18\x01 \x01(\x03R\bquantity\x12#\n" +
+	"\x04pack\x18\x02 \x01(\v2\x0f.packer.v1.PackR\x04pack\x12\x1a\n" +
+	"\bsubtotal\x18\x03 \x01(\x03R\bsubtotal\"\xa7\x04\n" +
+	"\x05Order\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12'\n" +
+	"\x0frequested_items\x18\x02 \x01(\x03R\x0erequestedItems\x12)\n" +
+	"\x10overpacked_items\x18\x03 \x01(\x03R\x0foverpackedItems\x12)\n" +
+	"\x10overpack_percent\x18\x04 \x01(\x01R\x0foverpackPercent\x12\x1f\n" +
+	"\vtotal_items\x18\x05 \x01(\x03R\n" +
+	"totalItems\x12*\n" +
+	"\x05packs\x18\x06 \x03(\v2\x14.packer.v1.OrderPackR\x05packs\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1c\n" +
+	"\treference\x18\b \x01(\tR\treference\x12:\n" +
+	"\bmetadata\x18\t \x03(\v2\x1e.packer.v1.Order.MetadataEntryR\bmetadata\x12\x1a\n" +
+	"\bwarnings\x18\n" +
+	" \x03(\tR\bwarnings\x12'\n" +
+	"\x0favailable_packs\x18\v \x03(\x03R\x0eavailablePacks\x12+\n" +
+	"\x11unfulfilled_items\x18\f \x01(\x03R\x10unfulfilledItems\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x12\n" +
+	"\x10ListPacksRequest\":\n" +
+	"\x11ListPacksResponse\x12%\n" +
+	"\x05packs\x18\x01 \x03(\v2\x0f.packer.v1.PackR\x05packs\"(\n" +
+	"\x0eGetPackRequest\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x03R\x06amount\"(\n" +
+	"\x0eAddPackRequest\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x03R\x06amount\"Q\n" +
+	"\x11UpdatePackRequest\x12\x1d\n" +
+	"\n" +
+	"old_amount\x18\x01 \x01(\x03R\toldAmount\x12\x1d\n" +
+	"\n" +
+	"new_amount\x18\x02 \x01(\x03R\tnewAmount\"+\n" +
+	"\x11DeletePackRequest\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x03R\x06amount\"\x14\n" +
+	"\x12DeletePackResponse\"\xf0\x01\n" +
+	"\x15CalculateOrderRequest\x12\x14\n" +
+	"\x05items\x18\x01 \x01(\x03R\x05items\x12\x1a\n" +
+	"\bstrategy\x18\x02 \x01(\tR\bstrategy\x12\x1c\n" +
+	"\treference\x18\x03 \x01(\tR\treference\x12J\n" +
+	"\bmetadata\x18\x04 \x03(\v2..packer.v1.CalculateOrderRequest.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x13\n" +
+	"\x11ListOrdersRequest\">\n" +
+	"\x12ListOrdersResponse\x12(\n" +
+	"\x06orders\x18\x01 \x03(\v2\x10.packer.v1.OrderR\x06orders2\xde\x03\n" +
+	"\rPackerService\x12F\n" +
+	"\tListPacks\x12\x1b.packer.v1.ListPacksRequest\x1a\x1c.packer.v1.ListPacksResponse\x125\n" +
+	"\aGetPack\x12\x19.packer.v1.GetPackRequest\x1a\x0f.packer.v1.Pack\x125\n" +
+	"\aAddPack\x12\x19.packer.v1.AddPackRequest\x1a\x0f.packer.v1.Pack\x12;\n" +
+	"\n" +
+	"UpdatePack\x12\x1c.packer.v1.UpdatePackRequest\x1a\x0f.packer.v1.Pack\x12I\n" +
+	"\n" +
+	"DeletePack\x12\x1c.packer.v1.DeletePackRequest\x1a\x1d.packer.v1.DeletePackResponse\x12D\n" +
+	"\x0eCalculateOrder\x12 .packer.v1.CalculateOrderRequest\x1a\x10.packer.v1.Order\x12I\n" +
+	"\n" +
+	"ListOrders\x12\x1c.packer.v1.ListOrdersRequest\x1a\x1d.packer.v1.ListOrdersResponseB<Z:github.com/corel-frim/item-packer-inc/api/grpcapi/packerpbb\x06proto3"
+
+var (
+	file_packer_proto_rawDescOnce sync.Once
+	file_packer_proto_rawDescData []byte
+)
+
+func file_packer_proto_rawDescGZIP() []byte {
+	file_packer_proto_rawDescOnce.Do(func() {
+		file_packer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_packer_proto_rawDesc), len(file_packer_proto_rawDesc)))
+	})
+	return file_packer_proto_rawDescData
+}
+
+var file_packer_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_packer_proto_goTypes = []any{
+	(*Pack)(nil),                  // 0: packer.v1.Pack
+	(*OrderPack)(nil),             // 1: packer.v1.OrderPack
+	(*Order)(nil),                 // 2: packer.v1.Order
+	(*ListPacksRequest)(nil),      // 3: packer.v1.ListPacksRequest
+	(*ListPacksResponse)(nil),     // 4: packer.v1.ListPacksResponse
+	(*GetPackRequest)(nil),        // 5: packer.v1.GetPackRequest
+	(*AddPackRequest)(nil),        // 6: packer.v1.AddPackRequest
+	(*UpdatePackRequest)(nil),     // 7: packer.v1.UpdatePackRequest
+	(*DeletePackRequest)(nil),     // 8: packer.v1.DeletePackRequest
+	(*DeletePackResponse)(nil),    // 9: packer.v1.DeletePackResponse
+	(*CalculateOrderRequest)(nil), // 10: packer.v1.CalculateOrderRequest
+	(*ListOrdersRequest)(nil),     // 11: packer.v1.ListOrdersRequest
+	(*ListOrdersResponse)(nil),    // 12: packer.v1.ListOrdersResponse
+	nil,                           // 13: packer.v1.Order.MetadataEntry
+	nil,                           // 14: packer.v1.CalculateOrderRequest.MetadataEntry
+	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
+}
+var file_packer_proto_depIdxs = []int32{
+	0,  // 0: packer.v1.OrderPack.pack:type_name -> packer.v1.Pack
+	1,  // 1: packer.v1.Order.packs:type_name -> packer.v1.OrderPack
+	15, // 2: packer.v1.Order.created_at:type_name -> google.protobuf.Timestamp
+	13, // 3: packer.v1.Order.metadata:type_name -> packer.v1.Order.MetadataEntry
+	0,  // 4: packer.v1.ListPacksResponse.packs:type_name -> packer.v1.Pack
+	14, // 5: packer.v1.CalculateOrderRequest.metadata:type_name -> packer.v1.CalculateOrderRequest.MetadataEntry
+	2,  // 6: packer.v1.ListOrdersResponse.orders:type_name -> packer.v1.Order
+	3,  // 7: packer.v1.PackerService.ListPacks:input_type -> packer.v1.ListPacksRequest
+	5,  // 8: packer.v1.PackerService.GetPack:input_type -> packer.v1.GetPackRequest
+	6,  // 9: packer.v1.PackerService.AddPack:input_type -> packer.v1.AddPackRequest
+	7,  // 10: packer.v1.PackerService.UpdatePack:input_type -> packer.v1.UpdatePackRequest
+	8,  // 11: packer.v1.PackerService.DeletePack:input_type -> packer.v1.DeletePackRequest
+	10, // 12: packer.v1.PackerService.CalculateOrder:input_type -> packer.v1.CalculateOrderRequest
+	11, // 13: packer.v1.PackerService.ListOrders:input_type -> packer.v1.ListOrdersRequest
+	4,  // 14: packer.v1.PackerService.ListPacks:output_type -> packer.v1.ListPacksResponse
+	0,  // 15: packer.v1.PackerService.GetPack:output_type -> packer.v1.Pack
+	0,  // 16: packer.v1.PackerService.AddPack:output_type -> packer.v1.Pack
+	0,  // 17: packer.v1.PackerService.UpdatePack:output_type -> packer.v1.Pack
+	9,  // 18: packer.v1.PackerService.DeletePack:output_type -> packer.v1.DeletePackResponse
+	2,  // 19: packer.v1.PackerService.CalculateOrder:output_type -> packer.v1.Order
+	12, // 20: packer.v1.PackerService.ListOrders:output_type -> packer.v1.ListOrdersResponse
+	14, // [14:21] is the sub-list for method output_type
+	7,  // [7:14] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_packer_proto_init() }
+func file_packer_proto_init() {
+	if File_packer_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_packer_proto_rawDesc), len(file_packer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_packer_proto_goTypes,
+		DependencyIndexes: file_packer_proto_depIdxs,
+		MessageInfos:      file_packer_proto_msgTypes,
+	}.Build()
+	File_packer_proto = out.File
+	file_packer_proto_goTypes = nil
+	file_packer_proto_depIdxs = nil
+}
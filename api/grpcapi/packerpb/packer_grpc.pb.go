@@ -0,0 +1,369 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.1
+// source: packer.proto
+
+package packerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PackerService_ListPacks_FullMethodName      = "/packer.v1.PackerService/ListPacks"
+	PackerService_GetPack_FullMethodName        = "/packer.v1.PackerService/GetPack"
+	PackerService_AddPack_FullMethodName        = "/packer.v1.PackerService/AddPack"
+	PackerService_UpdatePack_FullMethodName     = "/packer.v1.PackerService/UpdatePack"
+	PackerService_DeletePack_FullMethodName     = "/packer.v1.PackerService/DeletePack"
+	PackerService_CalculateOrder_FullMethodName = "/packer.v1.PackerService/CalculateOrder"
+	PackerService_ListOrders_FullMethodName     = "/packer.v1.PackerService/ListOrders"
+)
+
+// PackerServiceClient is the client API for PackerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PackerService exposes the pack management and the order calculation of the REST API over gRPC.
+// Errors are reported with the status codes matching the REST statuses, e.g. NOT_FOUND for a missing pack.
+type PackerServiceClient interface {
+	// ListPacks returns all available packs, largest first
+	ListPacks(ctx context.Context, in *ListPacksRequest, opts ...grpc.CallOption) (*ListPacksResponse, error)
+	// GetPack returns the pack with the amount
+	GetPack(ctx context.Context, in *GetPackRequest, opts ...grpc.CallOption) (*Pack, error)
+	// AddPack adds a pack with the amount, an existing pack is returned as it is
+	AddPack(ctx context.Context, in *AddPackRequest, opts ...grpc.CallOption) (*Pack, error)
+	// UpdatePack changes the amount of the pack with the old amount
+	UpdatePack(ctx context.Context, in *UpdatePackRequest, opts ...grpc.CallOption) (*Pack, error)
+	// DeletePack removes the pack with the amount
+	DeletePack(ctx context.Context, in *DeletePackRequest, opts ...grpc.CallOption) (*DeletePackResponse, error)
+	// CalculateOrder packs the requested items and records the order
+	CalculateOrder(ctx context.Context, in *CalculateOrderRequest, opts ...grpc.CallOption) (*Order, error)
+	// ListOrders returns the recorded orders, oldest first
+	ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error)
+}
+
+type packerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPackerServiceClient(cc grpc.ClientConnInterface) PackerServiceClient {
+	return &packerServiceClient{cc}
+}
+
+func (c *packerServiceClient) ListPacks(ctx context.Context, in *ListPacksRequest, opts ...grpc.CallOption) (*ListPacksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPacksResponse)
+	err := c.cc.Invoke(ctx, PackerService_ListPacks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packerServiceClient) GetPack(ctx context.Context, in *GetPackRequest, opts ...grpc.CallOption) (*Pack, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pack)
+	err := c.cc.Invoke(ctx, PackerService_GetPack_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packerServiceClient) AddPack(ctx context.Context, in *AddPackRequest, opts ...grpc.CallOption) (*Pack, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pack)
+	err := c.cc.Invoke(ctx, PackerService_AddPack_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packerServiceClient) UpdatePack(ctx context.Context, in *UpdatePackRequest, opts ...grpc.CallOption) (*Pack, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pack)
+	err := c.cc.Invoke(ctx, PackerService_UpdatePack_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packerServiceClient) DeletePack(ctx context.Context, in *DeletePackRequest, opts ...grpc.CallOption) (*DeletePackResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeletePackResponse)
+	err := c.cc.Invoke(ctx, PackerService_DeletePack_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packerServiceClient) CalculateOrder(ctx context.Context, in *CalculateOrderRequest, opts ...grpc.CallOption) (*Order, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Order)
+	err := c.cc.Invoke(ctx, PackerService_CalculateOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packerServiceClient) ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOrdersResponse)
+	err := c.cc.Invoke(ctx, PackerService_ListOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PackerServiceServer is the server API for PackerService service.
+// All implementations must embed UnimplementedPackerServiceServer
+// for forward compatibility.
+//
+// PackerService exposes the pack management and the order calculation of the REST API over gRPC.
+// Errors are reported with the status codes matching the REST statuses, e.g. NOT_FOUND for a missing pack.
+type PackerServiceServer interface {
+	// ListPacks returns all available packs, largest first
+	ListPacks(context.Context, *ListPacksRequest) (*ListPacksResponse, error)
+	// GetPack returns the pack with the amount
+	GetPack(context.Context, *GetPackRequest) (*Pack, error)
+	// AddPack adds a pack with the amount, an existing pack is returned as it is
+	AddPack(context.Context, *AddPackRequest) (*Pack, error)
+	// UpdatePack changes the amount of the pack with the old amount
+	UpdatePack(context.Context, *UpdatePackRequest) (*Pack, error)
+	// DeletePack removes the pack with the amount
+	DeletePack(context.Context, *DeletePackRequest) (*DeletePackResponse, error)
+	// CalculateOrder packs the requested items and records the order
+	CalculateOrder(context.Context, *CalculateOrderRequest) (*Order, error)
+	// ListOrders returns the recorded orders, oldest first
+	ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error)
+	mustEmbedUnimplementedPackerServiceServer()
+}
+
+// UnimplementedPackerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPackerServiceServer struct{}
+
+func (UnimplementedPackerServiceServer) ListPacks(context.Context, *ListPacksRequest) (*ListPacksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPacks not implemented")
+}
+func (UnimplementedPackerServiceServer) GetPack(context.Context, *GetPackRequest) (*Pack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPack not implemented")
+}
+func (UnimplementedPackerServiceServer) AddPack(context.Context, *AddPackRequest) (*Pack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPack not implemented")
+}
+func (UnimplementedPackerServiceServer) UpdatePack(context.Context, *UpdatePackRequest) (*Pack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePack not implemented")
+}
+func (UnimplementedPackerServiceServer) DeletePack(context.Context, *DeletePackRequest) (*DeletePackResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePack not implemented")
+}
+func (UnimplementedPackerServiceServer) CalculateOrder(context.Context, *CalculateOrderRequest) (*Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CalculateOrder not implemented")
+}
+func (UnimplementedPackerServiceServer) ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrders not implemented")
+}
+func (UnimplementedPackerServiceServer) mustEmbedUnimplementedPackerServiceServer() {}
+func (UnimplementedPackerServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafePackerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PackerServiceServer will
+// result in compilation errors.
+type UnsafePackerServiceServer interface {
+	mustEmbedUnimplementedPackerServiceServer()
+}
+
+func RegisterPackerServiceServer(s grpc.ServiceRegistrar, srv PackerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPackerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PackerService_ServiceDesc, srv)
+}
+
+func _PackerService_ListPacks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPacksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackerServiceServer).ListPacks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackerService_ListPacks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackerServiceServer).ListPacks(ctx, req.(*ListPacksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackerService_GetPack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackerServiceServer).GetPack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackerService_GetPack_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackerServiceServer).GetPack(ctx, req.(*GetPackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackerService_AddPack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackerServiceServer).AddPack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackerService_AddPack_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackerServiceServer).AddPack(ctx, req.(*AddPackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackerService_UpdatePack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackerServiceServer).UpdatePack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackerService_UpdatePack_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackerServiceServer).UpdatePack(ctx, req.(*UpdatePackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackerService_DeletePack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackerServiceServer).DeletePack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackerService_DeletePack_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackerServiceServer).DeletePack(ctx, req.(*DeletePackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackerService_CalculateOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalculateOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackerServiceServer).CalculateOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackerService_CalculateOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackerServiceServer).CalculateOrder(ctx, req.(*CalculateOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PackerService_ListOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackerServiceServer).ListOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PackerService_ListOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackerServiceServer).ListOrders(ctx, req.(*ListOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PackerService_ServiceDesc is the grpc.ServiceDesc for PackerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PackerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "packer.v1.PackerService",
+	HandlerType: (*PackerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListPacks",
+			Handler:    _PackerService_ListPacks_Handler,
+		},
+		{
+			MethodName: "GetPack",
+			Handler:    _PackerService_GetPack_Handler,
+		},
+		{
+			MethodName: "AddPack",
+			Handler:    _PackerService_AddPack_Handler,
+		},
+		{
+			MethodName: "UpdatePack",
+			Handler:    _PackerService_UpdatePack_Handler,
+		},
+		{
+			MethodName: "DeletePack",
+			Handler:    _PackerService_DeletePack_Handler,
+		},
+		{
+			MethodName: "CalculateOrder",
+			Handler:    _PackerService_CalculateOrder_Handler,
+		},
+		{
+			MethodName: "ListOrders",
+			Handler:    _PackerService_ListOrders_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "packer.proto",
+}
@@ -0,0 +1,195 @@
+// Package grpcapi serves the pack management and the order calculation over gRPC, next to the REST API.
+// The service is defined in packerpb/packer.proto, run `make proto` to regenerate the code after changing it.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/corel-frim/item-packer-inc/api/grpcapi/packerpb"
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/internal/storage"
+	"github.com/corel-frim/item-packer-inc/packing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Server struct {
+	packerpb.UnimplementedPackerServiceServer
+	storage storage.Store
+	server  *grpc.Server
+}
+
+func NewServer(storage storage.Store) *Server {
+	s := &Server{
+		storage: storage,
+		server:  grpc.NewServer(),
+	}
+	packerpb.RegisterPackerServiceServer(s.server, s)
+
+	return s
+}
+
+// Serve accepts connections on the listener, it blocks until the server is stopped
+func (s *Server) Serve(listener net.Listener) error {
+	return s.server.Serve(listener)
+}
+
+// Stop gracefully stops the server waiting for active calls to finish
+func (s *Server) Stop() {
+	s.server.GracefulStop()
+}
+
+func (s *Server) ListPacks(ctx context.Context, _ *packerpb.ListPacksRequest) (*packerpb.ListPacksResponse, error) {
+	packs := s.storage.GetPacks(ctx)
+	response := &packerpb.ListPacksResponse{Packs: make([]*packerpb.Pack, len(packs))}
+	for i, pack := range packs {
+		response.Packs[i] = packToProto(pack)
+	}
+
+	return response, nil
+}
+
+func (s *Server) GetPack(ctx context.Context, req *packerpb.GetPackRequest) (*packerpb.Pack, error) {
+	amount, err := positive(req.GetAmount(), "amount")
+	if err != nil {
+		return nil, err
+	}
+
+	pack, err := s.storage.GetPack(ctx, amount)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return packToProto(pack), nil
+}
+
+func (s *Server) AddPack(ctx context.Context, req *packerpb.AddPackRequest) (*packerpb.Pack, error) {
+	amount, err := positive(req.GetAmount(), "amount")
+	if err != nil {
+		return nil, err
+	}
+
+	pack, err := s.storage.AddLabeledPack(ctx, amount, "")
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return packToProto(pack), nil
+}
+
+func (s *Server) UpdatePack(ctx context.Context, req *packerpb.UpdatePackRequest) (*packerpb.Pack, error) {
+	oldAmount, err := positive(req.GetOldAmount(), "old amount")
+	if err != nil {
+		return nil, err
+	}
+	newAmount, err := positive(req.GetNewAmount(), "new amount")
+	if err != nil {
+		return nil, err
+	}
+
+	pack, err := s.storage.UpdatePack(ctx, oldAmount, newAmount)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return packToProto(pack), nil
+}
+
+func (s *Server) DeletePack(ctx context.Context, req *packerpb.DeletePackRequest) (*packerpb.DeletePackResponse, error) {
+	amount, err := positive(req.GetAmount(), "amount")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.DeletePack(ctx, amount); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &packerpb.DeletePackResponse{}, nil
+}
+
+func (s *Server) CalculateOrder(ctx context.Context, req *packerpb.CalculateOrderRequest) (*packerpb.Order, error) {
+	items, err := positive(req.GetItems(), "items")
+	if err != nil {
+		return nil, err
+	}
+	var opts []packing.Option
+	if name := req.GetStrategy(); name != "" {
+		strategy, err := packing.ParseStrategy(name)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		opts = append(opts, packing.WithStrategy(strategy))
+	}
+	tags := models.OrderTags{Reference: req.GetReference(), Metadata: req.GetMetadata()}
+	if errs := tags.FieldErrors(); errs != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order tags: "+joinFieldErrors(errs))
+	}
+
+	order, err := s.storage.CalculateTaggedOrder(ctx, items, tags, opts...)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return orderToProto(order), nil
+}
+
+func (s *Server) ListOrders(ctx context.Context, _ *packerpb.ListOrdersRequest) (*packerpb.ListOrdersResponse, error) {
+	orders := s.storage.GetOrders(ctx)
+	response := &packerpb.ListOrdersResponse{Orders: make([]*packerpb.Order, len(orders))}
+	for i, order := range orders {
+		response.Orders[i] = orderToProto(order)
+	}
+
+	return response, nil
+}
+
+// positive converts a request value to an int, failing with InvalidArgument unless it's positive
+func positive(value int64, name string) (int, error) {
+	if value <= 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid %s: must be positive", name)
+	}
+	return int(value), nil
+}
+
+// joinFieldErrors lists the reasons the fields are invalid sorted by the name of the field
+func joinFieldErrors(errs map[string]string) string {
+	fields := make([]string, 0, len(errs))
+	for field, reason := range errs {
+		fields = append(fields, field+": "+reason)
+	}
+	slices.Sort(fields)
+	return strings.Join(fields, ", ")
+}
+
+// statusError maps the storage and packing errors to the gRPC status codes matching the statuses of the REST API.
+// Unexpected errors get Internal without their details.
+func statusError(err error) error {
+	var limitErr *storage.LimitError
+	var packsErr *storage.OrderPacksError
+	switch {
+	case errors.Is(err, storage.ErrPackNotFound), errors.Is(err, storage.ErrNoPacksAvailable):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, storage.ErrPackExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.As(err, &limitErr):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, storage.ErrPackPinned):
+		return status.Error(codes.FailedPrecondition, "pack is pinned, unpin it first")
+	case errors.Is(err, storage.ErrInvalidAmount), errors.Is(err, storage.ErrInvalidPackMultiple),
+		errors.Is(err, storage.ErrAmountTooSmall), errors.Is(err, storage.ErrItemsOutOfRange):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, packing.ErrOverpackExceeded), errors.Is(err, storage.ErrUnsatisfiable),
+		errors.Is(err, packing.ErrComputationComplexity), errors.As(err, &packsErr):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, storage.ErrComputationTimeout):
+		return status.Error(codes.DeadlineExceeded, "order computation timed out")
+	default:
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
@@ -0,0 +1,144 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/api/grpcapi/packerpb"
+	"github.com/corel-frim/item-packer-inc/internal/storage"
+	"github.com/corel-frim/item-packer-inc/internal/storage/storagetest"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient serves the storage over an in-memory connection and returns a client calling it
+func newTestClient(t *testing.T, packStorage *storage.PackStorage) packerpb.PackerServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(packStorage)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return packerpb.NewPackerServiceClient(conn)
+}
+
+func TestPacks(t *testing.T) {
+	packStorage := storage.NewPackStorage(storage.WithMaxPacks(2))
+	client := newTestClient(t, packStorage)
+
+	pack, err := client.AddPack(t.Context(), &packerpb.AddPackRequest{Amount: 250})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), pack.GetId())
+	assert.Equal(t, int64(250), pack.GetAmount())
+	_, err = client.AddPack(t.Context(), &packerpb.AddPackRequest{Amount: 500})
+	assert.NoError(t, err)
+
+	packs, err := client.ListPacks(t.Context(), &packerpb.ListPacksRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, packs.GetPacks(), 2)
+	assert.Equal(t, int64(500), packs.GetPacks()[0].GetAmount())
+
+	pack, err = client.UpdatePack(t.Context(), &packerpb.UpdatePackRequest{OldAmount: 250, NewAmount: 300})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), pack.GetId())
+	assert.Equal(t, int64(300), pack.GetAmount())
+
+	pack, err = client.GetPack(t.Context(), &packerpb.GetPackRequest{Amount: 300})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), pack.GetId())
+
+	_, err = client.DeletePack(t.Context(), &packerpb.DeletePackRequest{Amount: 300})
+	assert.NoError(t, err)
+	assert.Len(t, packStorage.GetPacks(t.Context()), 1)
+}
+
+func TestPackErrors(t *testing.T) {
+	packStorage := storage.NewPackStorage(storage.WithMaxPacks(2))
+	storagetest.AddPacks(t, packStorage, 250, 500)
+	_, _ = packStorage.SetPackPinned(t.Context(), 500, true)
+	client := newTestClient(t, packStorage)
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"not found", func() error {
+			_, err := client.GetPack(t.Context(), &packerpb.GetPackRequest{Amount: 1000})
+			return err
+		}, codes.NotFound},
+		{"not positive", func() error {
+			_, err := client.AddPack(t.Context(), &packerpb.AddPackRequest{Amount: 0})
+			return err
+		}, codes.InvalidArgument},
+		{"limit", func() error {
+			_, err := client.AddPack(t.Context(), &packerpb.AddPackRequest{Amount: 1000})
+			return err
+		}, codes.ResourceExhausted},
+		{"exists", func() error {
+			_, err := client.UpdatePack(t.Context(), &packerpb.UpdatePackRequest{OldAmount: 250, NewAmount: 500})
+			return err
+		}, codes.AlreadyExists},
+		{"pinned", func() error {
+			_, err := client.DeletePack(t.Context(), &packerpb.DeletePackRequest{Amount: 500})
+			return err
+		}, codes.FailedPrecondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.code, status.Code(tt.call()))
+		})
+	}
+}
+
+func TestOrders(t *testing.T) {
+	packStorage := storagetest.NewStorage(t)
+	client := newTestClient(t, packStorage)
+
+	order, err := client.CalculateOrder(t.Context(), &packerpb.CalculateOrderRequest{
+		Items:     251,
+		Reference: "ABC123",
+		Metadata:  map[string]string{"customer": "ACME"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), order.GetId())
+	assert.Equal(t, int64(500), order.GetTotalItems())
+	assert.Equal(t, int64(249), order.GetOverpackedItems())
+	assert.Len(t, order.GetPacks(), 1)
+	assert.Equal(t, int64(500), order.GetPacks()[0].GetPack().GetAmount())
+	assert.Equal(t, "ABC123", order.GetReference())
+	assert.Equal(t, map[string]string{"customer": "ACME"}, order.GetMetadata())
+	assert.Equal(t, []int64{5000, 2000, 1000, 500, 250}, order.GetAvailablePacks())
+	assert.False(t, order.GetCreatedAt().AsTime().IsZero())
+
+	_, err = client.CalculateOrder(t.Context(), &packerpb.CalculateOrderRequest{Items: 12001, Strategy: "min-overpack"})
+	assert.NoError(t, err)
+
+	orders, err := client.ListOrders(t.Context(), &packerpb.ListOrdersRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, orders.GetOrders(), 2)
+	assert.Equal(t, int64(12001), orders.GetOrders()[1].GetRequestedItems())
+
+	_, err = client.CalculateOrder(t.Context(), &packerpb.CalculateOrderRequest{Items: 251, Strategy: "best"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.CalculateOrder(t.Context(), &packerpb.CalculateOrderRequest{Items: 251, Metadata: map[string]string{"": "x"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "invalid order tags: metadata: keys must not be empty", status.Convert(err).Message())
+
+	// No packs to calculate with
+	_, err = newTestClient(t, storage.NewPackStorage()).CalculateOrder(t.Context(), &packerpb.CalculateOrderRequest{Items: 1})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
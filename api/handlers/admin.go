@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/internal/storage"
+	"github.com/gofiber/fiber/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// AdminKeyHeader is the header used to pass the admin auth key
+const AdminKeyHeader = "X-Admin-Key"
+
+// yamlContentType is the content type of the YAML configuration
+const yamlContentType = "application/yaml"
+
+type Admin struct {
+	storage storage.Store
+	key     string
+}
+
+func NewAdmin(storage storage.Store, key string) *Admin {
+	return &Admin{
+		storage: storage,
+		key:     key,
+	}
+}
+
+func (a *Admin) RegisterRoutes(app *fiber.App) {
+	group := app.Group("/admin", a.authorize)
+	group.Get("/snapshot", a.GetSnapshot)
+	group.Post("/restore", a.Restore)
+	group.Get("/stats", a.GetStats)
+	group.Get("/config", a.GetConfig)
+	group.Get("/config.yaml", a.GetConfigYAML)
+	group.Post("/config", a.ImportConfig)
+
+	// Batch operations over the orders live with the orders, but need the admin key as well
+	app.Post("/orders/recompute", a.authorize, a.RecomputeOrders)
+}
+
+// authorize rejects requests without a valid admin key.
+// Admin endpoints are disabled entirely if no key is configured.
+func (a *Admin) authorize(c *fiber.Ctx) error {
+	if a.key == "" {
+		return c.Status(http.StatusForbidden).JSON(map[string]string{"error": "Admin API is disabled"})
+	}
+	if subtle.ConstantTimeCompare([]byte(c.Get(AdminKeyHeader)), []byte(a.key)) != 1 {
+		return c.Status(http.StatusUnauthorized).JSON(map[string]string{"error": "Invalid admin key"})
+	}
+
+	return c.Next()
+}
+
+// GetSnapshot handles GET /admin/snapshot
+// @Summary Get storage snapshot
+// @Description Dump the full storage state (packs, orders and config) as JSON
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin auth key"
+// @Success 200 {object} models.Snapshot
+// @Failure 401 {object} map[string]string "Invalid admin key"
+// @Failure 403 {object} map[string]string "Admin API is disabled"
+// @Router /admin/snapshot [get]
+func (a *Admin) GetSnapshot(c *fiber.Ctx) error {
+	return c.Status(http.StatusOK).JSON(a.storage.Snapshot(c.UserContext()))
+}
+
+// Restore handles POST /admin/restore
+// @Summary Restore storage snapshot
+// @Description Atomically replace the full storage state with the given snapshot
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin auth key"
+// @Param snapshot body models.Snapshot true "Snapshot to restore"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]string "Invalid snapshot"
+// @Failure 401 {object} map[string]string "Invalid admin key"
+// @Failure 403 {object} map[string]string "Admin API is disabled"
+// @Router /admin/restore [post]
+func (a *Admin) Restore(c *fiber.Ctx) error {
+	var snapshot models.Snapshot
+	if err := c.BodyParser(&snapshot); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(map[string]string{"error": "Invalid snapshot"})
+	}
+
+	err := a.storage.Restore(c.UserContext(), snapshot)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidSnapshot) {
+			return c.Status(http.StatusBadRequest).JSON(map[string]string{"error": err.Error()})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(map[string]string{"error": "Failed to restore snapshot"})
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// GetConfig handles GET /admin/config
+// @Summary Get the pack configuration
+// @Description Export the packs with their labels and pins, the pack limit and the default strategy as JSON.
+// @Description The configuration can be imported again with POST /admin/config.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin auth key"
+// @Success 200 {object} models.PackConfig
+// @Failure 401 {object} map[string]string "Invalid admin key"
+// @Failure 403 {object} map[string]string "Admin API is disabled"
+// @Router /admin/config [get]
+func (a *Admin) GetConfig(c *fiber.Ctx) error {
+	return c.Status(http.StatusOK).JSON(a.storage.ExportConfig(c.UserContext()))
+}
+
+// GetConfigYAML handles GET /admin/config.yaml
+// @Summary Get the pack configuration as YAML
+// @Description Export the same configuration as GET /admin/config as YAML, to keep it in a repository
+// @Tags admin
+// @Produce application/yaml
+// @Param X-Admin-Key header string true "Admin auth key"
+// @Success 200 {object} models.PackConfig
+// @Failure 401 {object} map[string]string "Invalid admin key"
+// @Failure 403 {object} map[string]string "Admin API is disabled"
+// @Router /admin/config.yaml [get]
+func (a *Admin) GetConfigYAML(c *fiber.Ctx) error {
+	body, err := yaml.Marshal(a.storage.ExportConfig(c.UserContext()))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(map[string]string{"error": "Failed to export config"})
+	}
+
+	c.Set(fiber.HeaderContentType, yamlContentType)
+	return c.Status(http.StatusOK).Send(body)
+}
+
+// ImportConfig handles POST /admin/config
+// @Summary Import the pack configuration
+// @Description Atomically replace the packs, the pack limit and the default strategy with the configuration, keeping the recorded orders.
+// @Description The body is JSON unless the Content-Type is YAML (application/yaml, application/x-yaml or text/yaml), unknown YAML fields are rejected.
+// @Description A zero softLimit or an empty defaultStrategy keeps the current one. The configuration is checked like a restored snapshot.
+// @Tags admin
+// @Accept json
+// @Accept application/yaml
+// @Produce json
+// @Param X-Admin-Key header string true "Admin auth key"
+// @Param config body models.PackConfig true "Configuration to import"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]string "Invalid config"
+// @Failure 401 {object} map[string]string "Invalid admin key"
+// @Failure 403 {object} map[string]string "Admin API is disabled"
+// @Router /admin/config [post]
+func (a *Admin) ImportConfig(c *fiber.Ctx) error {
+	var config models.PackConfig
+	if isYAML(c.Get(fiber.HeaderContentType)) {
+		decoder := yaml.NewDecoder(bytes.NewReader(c.Body()))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&config); err != nil {
+			return c.Status(http.StatusBadRequest).JSON(map[string]string{"error": "Invalid config"})
+		}
+	} else if err := c.BodyParser(&config); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(map[string]string{"error": "Invalid config"})
+	}
+
+	err := a.storage.ImportConfig(c.UserContext(), config)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidConfig) {
+			return c.Status(http.StatusBadRequest).JSON(map[string]string{"error": err.Error()})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(map[string]string{"error": "Failed to import config"})
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// isYAML tells whether the content type is one of the YAML media types, ignoring its parameters
+func isYAML(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	switch strings.ToLower(strings.TrimSpace(mediaType)) {
+	case yamlContentType, "application/x-yaml", "text/yaml":
+		return true
+	default:
+		return false
+	}
+}
+
+// GetStats handles GET /admin/stats
+// @Summary Get storage stats
+// @Description Report the current pack and order counts, the configured limits, a rough memory estimate
+// @Description and the hit rate of the cache of computed orders. It doesn't change anything.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin auth key"
+// @Success 200 {object} models.StorageStats
+// @Failure 401 {object} map[string]string "Invalid admin key"
+// @Failure 403 {object} map[string]string "Admin API is disabled"
+// @Router /admin/stats [get]
+func (a *Admin) GetStats(c *fiber.Ctx) error {
+	return c.Status(http.StatusOK).JSON(a.storage.Stats(c.UserContext()))
+}
+
+// RecomputeOrders handles POST /orders/recompute
+// @Summary Recompute the recorded orders
+// @Description Pack the requested items of every recorded order again with the current packs and the default strategy,
+// @Description keeping their timestamps, references and metadata. Orders that can't be packed anymore are left unchanged.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin auth key"
+// @Success 200 {object} models.RecomputeSummary
+// @Failure 401 {object} map[string]string "Invalid admin key"
+// @Failure 403 {object} map[string]string "Admin API is disabled"
+// @Failure 404 {object} map[string]string "No packs available"
+// @Failure 409 {object} map[string]string "Packs changed during the recomputation"
+// @Failure 503 {object} map[string]string "Recomputation timed out"
+// @Router /orders/recompute [post]
+func (a *Admin) RecomputeOrders(c *fiber.Ctx) error {
+	summary, err := a.storage.RecomputeOrders(c.UserContext())
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrNoPacksAvailable):
+			return c.Status(http.StatusNotFound).JSON(map[string]string{"error": "No packs available"})
+		case errors.Is(err, storage.ErrPackSetChanged):
+			return c.Status(http.StatusConflict).JSON(map[string]string{"error": "Packs changed during the recomputation, try again"})
+		case errors.Is(err, storage.ErrComputationTimeout):
+			return c.Status(http.StatusServiceUnavailable).JSON(map[string]string{"error": "Recomputation timed out"})
+		default:
+			return c.Status(http.StatusInternalServerError).JSON(map[string]string{"error": "Failed to recompute orders"})
+		}
+	}
+
+	return c.Status(http.StatusOK).JSON(summary)
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/internal/storage"
+	"github.com/corel-frim/item-packer-inc/packing"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecomputeOrders(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 250)
+	_, _ = packStorage.CalculateOrder(t.Context(), 100)
+	_ = packStorage.AddPack(t.Context(), 100)
+
+	app := fiber.New()
+	NewOrders(packStorage).RegisterRoutes(app)
+	NewAdmin(packStorage, "secret").RegisterRoutes(app)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/orders/recompute", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, 250, packStorage.GetOrders(t.Context())[0].TotalItems)
+
+	req := httptest.NewRequest(http.MethodPost, "/orders/recompute", nil)
+	req.Header.Set(AdminKeyHeader, "secret")
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var summary models.RecomputeSummary
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&summary))
+	assert.Equal(t, models.RecomputeSummary{Orders: 1, Changed: 1, OverpackDelta: -150}, summary)
+	assert.Equal(t, 100, packStorage.GetOrders(t.Context())[0].TotalItems)
+
+	// Disabled without a key
+	app = fiber.New()
+	NewAdmin(packStorage, "").RegisterRoutes(app)
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/orders/recompute", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestGetStats(t *testing.T) {
+	packStorage := storage.NewPackStorage(storage.WithMaxOrderPacks(100))
+	_ = packStorage.AddPack(t.Context(), 250)
+	_, _ = packStorage.CalculateOrder(t.Context(), 100)
+
+	app := fiber.New()
+	NewAdmin(packStorage, "secret").RegisterRoutes(app)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/admin/stats", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/stats", nil)
+	req.Header.Set(AdminKeyHeader, "secret")
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var stats models.StorageStats
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&stats))
+	assert.Equal(t, 1, stats.Packs)
+	assert.Equal(t, 1, stats.Orders)
+	assert.Equal(t, 100, stats.MaxOrderPacks)
+	assert.True(t, stats.OrderHistory)
+	assert.Positive(t, stats.MemoryBytes)
+	assert.Equal(t, 1, stats.Cache.Entries)
+}
+
+func TestConfigYAML(t *testing.T) {
+	packStorage := storage.NewPackStorage(storage.WithMaxPacks(5))
+	_, _ = packStorage.AddLabeledPack(t.Context(), 500, "box")
+	_ = packStorage.AddPack(t.Context(), 250)
+	_, _ = packStorage.SetPackPinned(t.Context(), 250, true)
+
+	app := fiber.New()
+	NewAdmin(packStorage, "secret").RegisterRoutes(app)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/config.yaml", nil)
+	req.Header.Set(AdminKeyHeader, "secret")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/yaml", resp.Header.Get("Content-Type"))
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, `softLimit: 5
+defaultStrategy: greedy
+packs:
+    - amount: 500
+      label: box
+    - amount: 250
+      pinned: true
+`, string(body))
+
+	// The exported configuration imports into another storage
+	imported := storage.NewPackStorage()
+	app = fiber.New()
+	NewAdmin(imported, "secret").RegisterRoutes(app)
+	req = httptest.NewRequest(http.MethodPost, "/admin/config", bytes.NewReader(body))
+	req.Header.Set(AdminKeyHeader, "secret")
+	req.Header.Set("Content-Type", "application/yaml")
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, packStorage.ExportConfig(t.Context()), imported.ExportConfig(t.Context()))
+}
+
+func TestImportConfig(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 250)
+
+	app := fiber.New()
+	NewAdmin(packStorage, "secret").RegisterRoutes(app)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		error       string
+	}{
+		{"json", "application/json", `{"defaultStrategy": "min-overpack", "packs": [{"amount": 500}]}`, http.StatusNoContent, ""},
+		{"yaml", "text/yaml; charset=utf-8", "packs:\n  - amount: 1000\n    label: crate\n", http.StatusNoContent, ""},
+		{"unknown yaml field", "application/x-yaml", "packs:\n  - size: 1000\n", http.StatusBadRequest, "Invalid config"},
+		{"malformed json", "application/json", `{"packs": `, http.StatusBadRequest, "Invalid config"},
+		{"duplicate amount", "application/yaml", "packs: [{amount: 250}, {amount: 250}]", http.StatusBadRequest, "invalid config: duplicate pack amount 250"},
+		{"unknown strategy", "application/yaml", "defaultStrategy: best", http.StatusBadRequest, "invalid config: " + packing.ErrUnknownStrategy.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/admin/config", strings.NewReader(tt.body))
+			req.Header.Set(AdminKeyHeader, "secret")
+			req.Header.Set("Content-Type", tt.contentType)
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if tt.error != "" {
+				assert.Equal(t, tt.error, errorMessage(t, resp))
+			}
+		})
+	}
+
+	config := packStorage.ExportConfig(t.Context())
+	assert.Equal(t, "min-overpack", config.DefaultStrategy)
+	assert.Equal(t, []models.ConfigPack{{Amount: 1000, Label: "crate"}}, config.Packs)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// bodyField is the field of the errors which are about the request body as a whole
+const bodyField = "body"
+
+var (
+	errBodyNotJSON  = errors.New("must be valid JSON")
+	errBodyNotArray = errors.New("must be an array")
+	errBodyNotMap   = errors.New("must be an object")
+	errBodyNotText  = errors.New("must be a string")
+	errBodyNotBool  = errors.New("must be true or false")
+)
+
+// fieldErrors collects the validation errors of a JSON request body by the name of the field
+type fieldErrors map[string]string
+
+// add records the reason the field is invalid, keeping the first reason of every field
+func (e fieldErrors) add(field string, reason error) {
+	if _, ok := e[field]; !ok {
+		e[field] = reason.Error()
+	}
+}
+
+// parseBody parses the JSON request body into out, reporting values of the wrong type by their field
+func parseBody(c *fiber.Ctx, out any) fieldErrors {
+	err := c.BodyParser(out)
+	if err == nil {
+		return nil
+	}
+
+	errs := make(fieldErrors)
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		errs.add(bodyField, errBodyNotJSON)
+		return errs
+	}
+
+	errs.add(fieldPath(typeErr.Field), typeReason(typeErr.Type))
+	return errs
+}
+
+// fieldPath turns the dotted path of a JSON value like "packs.2" into "packs[2]", indexes of the body as a whole
+// become "body[2]"
+func fieldPath(path string) string {
+	field := ""
+	for _, segment := range strings.Split(path, ".") {
+		if _, err := strconv.Atoi(segment); err == nil {
+			if field == "" {
+				field = bodyField
+			}
+			field += "[" + segment + "]"
+			continue
+		}
+		if field != "" {
+			field += "."
+		}
+		field += segment
+	}
+	if field == "" {
+		return bodyField
+	}
+	return field
+}
+
+// typeReason describes the JSON type expected for a Go type
+func typeReason(typ reflect.Type) error {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return errParamNotInteger
+	case reflect.Slice, reflect.Array:
+		return errBodyNotArray
+	case reflect.Map, reflect.Struct:
+		return errBodyNotMap
+	case reflect.String:
+		return errBodyNotText
+	case reflect.Bool:
+		return errBodyNotBool
+	default:
+		return errBodyNotJSON
+	}
+}
+
+// invalidBody responds with a uniform 400 listing the validation errors of the request body by field
+func invalidBody(c *fiber.Ctx, errs fieldErrors) error {
+	return c.Status(http.StatusBadRequest).JSON(models.ValidationErrorResponse{
+		Error:  "Invalid request body",
+		Errors: errs,
+	})
+}
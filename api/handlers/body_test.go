@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// bodyErrors decodes a 400 response for an invalid request body and returns its errors by field
+func bodyErrors(t *testing.T, resp *http.Response) map[string]string {
+	t.Helper()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	var body models.ValidationErrorResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "Invalid request body", body.Error)
+	return body.Errors
+}
+
+func TestParseBody(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 250)
+	app := newTestApp(packStorage)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		errors map[string]string
+	}{
+		{"malformed", "/orders/items/100", `{"reference":`, map[string]string{"body": "must be valid JSON"}},
+		{"wrong field type", "/orders/items/100", `{"reference": 1}`, map[string]string{"reference": "must be a string"}},
+		{"wrong body type", "/orders/items/100", `[1]`, map[string]string{"body": "must be an object"}},
+		{"wrong element type", "/packs/import", `[250, "500"]`, map[string]string{"body[1]": "must be an integer"}},
+		{"object instead of array", "/packs/validate", `{"amounts": [500]}`, map[string]string{"body": "must be an array"}},
+		{"wrong nested type", "/orders/items/100", `{"metadata": {"note": 1}}`, map[string]string{"metadata.note": "must be a string"}},
+		{"batch too small", "/orders", `[]`, map[string]string{"body": "must hold 1 to 100 numbers of items"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.errors, bodyErrors(t, resp))
+		})
+	}
+	assert.Empty(t, packStorage.GetOrders(t.Context()))
+}
+
+func TestFieldPath(t *testing.T) {
+	assert.Equal(t, "body", fieldPath(""))
+	assert.Equal(t, "body[1]", fieldPath("1"))
+	assert.Equal(t, "packs[2]", fieldPath("packs.2"))
+	assert.Equal(t, "metadata.note", fieldPath("metadata.note"))
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/internal/storage"
+	"github.com/corel-frim/item-packer-inc/packing"
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	errPacksEmpty       = errors.New("must not be empty")
+	errPacksNotPositive = errors.New("amounts must be positive")
+)
+
+// Calculator handles stateless order calculations that don't touch the storage
+type Calculator struct{}
+
+func NewCalculator() *Calculator {
+	return &Calculator{}
+}
+
+func (calc *Calculator) RegisterRoutes(app *fiber.App) {
+	app.Post("/calculate", calc.Calculate)
+}
+
+// Calculate handles POST /calculate
+// @Summary Calculate an order against the given packs
+// @Description Calculate the optimal packing for the requested items using the pack amounts from the request. Nothing is stored.
+// @Tags calculate
+// @Accept json
+// @Produce json
+// @Param request body models.CalculateRequest true "Pack amounts and number of items"
+// @Success 200 {object} models.Order
+// @Failure 400 {object} models.ValidationErrorResponse "Invalid request body, with the reason of every invalid field"
+// @Router /calculate [post]
+func (calc *Calculator) Calculate(c *fiber.Ctx) error {
+	var request models.CalculateRequest
+	if errs := parseBody(c, &request); errs != nil {
+		return invalidBody(c, errs)
+	}
+	if errs := validateCalculateRequest(request); len(errs) > 0 {
+		return invalidBody(c, errs)
+	}
+
+	order, err := packing.Pack(request.Packs, request.Items)
+	if err != nil {
+		return orderError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(order)
+}
+
+// validateCalculateRequest checks the items and the pack amounts of a calculation request
+func validateCalculateRequest(request models.CalculateRequest) fieldErrors {
+	errs := make(fieldErrors)
+	if request.Items <= 0 {
+		errs.add("items", errParamNotPositive)
+	}
+
+	// The same limit as for stored packs applies to the distinct amounts like in AddPacks, Pack ignores the duplicates
+	distinct := make(map[int]struct{}, len(request.Packs))
+	for _, amount := range request.Packs {
+		distinct[amount] = struct{}{}
+	}
+	switch {
+	case len(request.Packs) == 0:
+		errs.add("packs", errPacksEmpty)
+	case len(distinct) > storage.SoftLimit:
+		errs.add("packs", fmt.Errorf("must hold at most %d pack sizes", storage.SoftLimit))
+	}
+	largest := 0
+	for _, amount := range request.Packs {
+		if amount <= 0 {
+			errs.add("packs", errPacksNotPositive)
+		}
+		largest = max(largest, amount)
+	}
+	// The totals of the order must fit into an int
+	if request.Items > math.MaxInt-largest {
+		errs.add("items", errParamOutOfRange)
+	}
+
+	return errs
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/internal/storage"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculate(t *testing.T) {
+	app := fiber.New()
+	NewCalculator().RegisterRoutes(app)
+
+	req := httptest.NewRequest(http.MethodPost, "/calculate", strings.NewReader(`{"packs":[250,500,1000],"items":1001}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var order models.Order
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&order))
+	assert.Equal(t, 1250, order.TotalItems)
+
+	// Only the distinct amounts count against the limit
+	req = httptest.NewRequest(http.MethodPost, "/calculate",
+		strings.NewReader(`{"packs":[`+strings.Repeat("250,", storage.SoftLimit)+`500],"items":1001}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	distinct := make([]string, storage.SoftLimit+1)
+	for i := range distinct {
+		distinct[i] = strconv.Itoa(i + 1)
+	}
+	tooMany := `{"packs":[` + strings.Join(distinct, ",") + `],"items":10}`
+
+	tests := []struct {
+		body   string
+		errors map[string]string
+	}{
+		{tooMany, map[string]string{"packs": "must hold at most 20 pack sizes"}},
+		{`{"packs":[250,500,1000],"items":9223372036854775807}`, map[string]string{"items": "is out of range"}},
+		{`{"packs":[],"items":10}`, map[string]string{"packs": "must not be empty"}},
+		{`{"packs":[250],"items":0}`, map[string]string{"items": "must be positive"}},
+		{`{"packs":[-5],"items":-1}`, map[string]string{"packs": "amounts must be positive", "items": "must be positive"}},
+		{`{"packs":[250],"items":"10"}`, map[string]string{"items": "must be an integer"}},
+		{`{"packs":[250,"500"],"items":10}`, map[string]string{"packs[1]": "must be an integer"}},
+		{`not json`, map[string]string{"body": "must be valid JSON"}},
+	}
+	for _, tt := range tests {
+		req = httptest.NewRequest(http.MethodPost, "/calculate", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err = app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.errors, bodyErrors(t, resp), tt.body)
+	}
+}
@@ -1,19 +1,24 @@
 package handlers
 
 import (
+	"bufio"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
-	"strconv"
+	"time"
 
+	"github.com/corel-frim/item-packer-inc/internal/models"
 	"github.com/corel-frim/item-packer-inc/internal/storage"
+	"github.com/corel-frim/item-packer-inc/packing"
 	"github.com/gofiber/fiber/v2"
 )
 
 type Orders struct {
-	storage *storage.PackStorage
+	storage storage.Store
 }
 
-func NewOrders(storage *storage.PackStorage) *Orders {
+func NewOrders(storage storage.Store) *Orders {
 	return &Orders{
 		storage: storage,
 	}
@@ -22,47 +27,597 @@ func NewOrders(storage *storage.PackStorage) *Orders {
 func (o *Orders) RegisterRoutes(app *fiber.App) {
 	group := app.Group("/orders")
 	group.Post("/items/:amount", o.CreateOrder)
+	group.Get("/quote/:amount", o.QuoteOrder)
+	group.Get("/compare/:amount", o.CompareStrategies)
+	group.Get("/candidates/:amount", o.OrderCandidates)
+	group.Get("/minpacks/:amount", o.MinPackCount)
+	group.Post("/return/:amount", o.ReturnItems)
+	group.Get("/stream", o.StreamOrders)
+	group.Get("/:id/receipt", o.GetReceipt)
 	group.Get("", o.GetOrders)
+	group.Post("", o.CreateOrders)
 }
 
+// maxBatchOrders is the max number of orders created by a single batch request
+const maxBatchOrders = 100
+
+// defaultCandidates is the number of candidate orders of requests without a count
+const defaultCandidates = 3
+
+// maxCartons is the max number of packs of an order listed as cartons
+const maxCartons = 1000
+
 // CreateOrder handles POST /order/items/{amount}
 // @Summary Create an order
-// @Description Create an order with the specified number of items
+// @Description Create an order with the specified number of items.
+// @Description The optional body tags the order with a reference and metadata which are recorded and echoed back.
 // @Tags orders
+// @Accept json
 // @Produce json
 // @Param amount path int true "Number of items"
-// @Success 200 {object} models.Order
-// @Failure 400 {object} map[string]string "Invalid amount"
+// @Param tags body models.OrderTags false "Reference of at most 64 characters and at most 10 metadata entries of at most 256 characters"
+// @Param strategy query string false "Packing algorithm" Enums(greedy, min-overpack, fewest-sizes, distinct-packs, max-under) default(greedy)
+// @Param fillerPack query int false "Pack amount used to fill the remaining items instead of the smallest pack, greedy strategy only, rejected with any other strategy"
+// @Param maxOverpack query string false "Max overpacked items, either a number of items (100) or a percentage of the requested items (10%)"
+// @Param maxPerSize query int false "Max number of packs of a single size"
+// @Param requirePack query int false "Pack amount the order must include at least once"
+// @Param belowSmallestPack query bool false "Guarantee an overpack below the smallest pack amount, orders that can't meet it are rejected with 422"
+// @Param includeUnused query bool false "Include the unused packs with zero quantity"
+// @Param trace query bool false "Respond with the order and the steps of its computation"
+// @Param flat query bool false "Respond with flat packs {amount, quantity, subtotal} instead of nested packs"
+// @Param cartons query bool false "Also list every single pack as a carton, at most 1000"
+// @Success 200 {object} models.Order "The order, models.TracedOrder with trace=true, models.FlatOrder or models.FlatTracedOrder with flat=true"
+// @Failure 400 {object} map[string]string "Invalid amount, strategy, filler pack, max overpack, max per size, required pack, include unused, trace, flat or cartons, or request body with the reason of every invalid field under errors"
 // @Failure 404 {object} map[string]string "No packs available"
+// @Failure 422 {object} map[string]string "Overpack exceeds the allowed maximum, items can't be packed within the constraints, the order is too complex or needs too many packs (in total or for cartons)"
+// @Failure 503 {object} map[string]string "Order computation timed out"
 // @Router /order/items/{amount} [post]
 func (o *Orders) CreateOrder(c *fiber.Ctx) error {
-	path := c.Params("amount")
-	amount, err := strconv.Atoi(path)
-	if err != nil || amount <= 0 {
-		return c.Status(http.StatusBadRequest).JSON(map[string]string{"error": "Invalid amount"})
+	amount, err := positiveParam(c, "amount", "amount")
+	if err != nil {
+		return invalidParam(c, err)
 	}
-
-	order, err := o.storage.CalculateOrder(amount)
+	opts, err := o.orderOptions(c)
+	if err != nil {
+		return invalidParam(c, err)
+	}
+	traced, err := optionalBoolQuery(c, "trace", "trace")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+	flat, err := optionalBoolQuery(c, "flat", "flat")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+	cartons, err := optionalBoolQuery(c, "cartons", "cartons")
 	if err != nil {
-		if errors.Is(err, storage.ErrNoPacksAvailable) {
-			return c.Status(http.StatusNotFound).JSON(map[string]string{"error": "No packs available"})
+		return invalidParam(c, err)
+	}
+	var tags models.OrderTags
+	if len(c.Body()) > 0 {
+		if errs := parseBody(c, &tags); errs != nil {
+			return invalidBody(c, errs)
+		}
+	}
+	if errs := tags.FieldErrors(); len(errs) > 0 {
+		return invalidBody(c, errs)
+	}
+	if cartons {
+		// Check the packs before recording the order, the computation is cached for the order itself
+		quote, err := o.storage.QuoteOrder(c.UserContext(), amount, opts...)
+		if err != nil {
+			return orderError(c, err)
+		}
+		if quote.PackCount > maxCartons {
+			return c.Status(http.StatusUnprocessableEntity).JSON(map[string]string{
+				"error": fmt.Sprintf("Order has too many packs to list as cartons, at most %d", maxCartons),
+			})
 		}
-		return c.Status(http.StatusInternalServerError).JSON(map[string]string{"error": "Internal server error"})
+	}
+	var trace packing.Trace
+	if traced {
+		opts = append(opts, packing.WithTrace(&trace))
+	}
+
+	order, err := o.storage.CalculateTaggedOrder(c.UserContext(), amount, tags, opts...)
+	if err != nil {
+		return orderError(c, err)
+	}
+	if cartons {
+		order = order.WithCartons()
 	}
 	c.Set("Content-Type", "application/json")
+	switch {
+	case traced && flat:
+		return c.Status(http.StatusOK).JSON(models.FlatTracedOrder{Order: order.Flat(), Trace: trace.Steps})
+	case traced:
+		return c.Status(http.StatusOK).JSON(models.TracedOrder{Order: order, Trace: trace.Steps})
+	case flat:
+		return c.Status(http.StatusOK).JSON(order.Flat())
+	}
 	return c.Status(http.StatusOK).JSON(order)
 }
 
+// CreateOrders handles POST /orders
+// @Summary Create a batch of orders
+// @Description Create an order for every number of items in the request body, e.g. [250, 1001], with the same options.
+// @Description Every entry is processed even if others fail. The response lists the outcome of every entry in the same order
+// @Description with the status a single order would get, the order on success and the error otherwise.
+// @Description It's 200 if all entries succeed and 207 Multi-Status otherwise.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param items body []int true "Numbers of items, at most 100"
+// @Param strategy query string false "Packing algorithm" Enums(greedy, min-overpack, fewest-sizes, distinct-packs, max-under) default(greedy)
+// @Param fillerPack query int false "Pack amount used to fill the remaining items instead of the smallest pack, greedy strategy only, rejected with any other strategy"
+// @Param maxOverpack query string false "Max overpacked items, either a number of items (100) or a percentage of the requested items (10%)"
+// @Param maxPerSize query int false "Max number of packs of a single size"
+// @Param requirePack query int false "Pack amount the order must include at least once"
+// @Param belowSmallestPack query bool false "Guarantee an overpack below the smallest pack amount, orders that can't meet it are rejected with 422"
+// @Success 200 {array} models.BatchOrderResult "All orders were created"
+// @Success 207 {array} models.BatchOrderResult "Some orders failed"
+// @Failure 400 {object} map[string]string "Invalid strategy, filler pack, max overpack, max per size or required pack, or request body with the reason of every invalid field under errors"
+// @Router /orders [post]
+func (o *Orders) CreateOrders(c *fiber.Ctx) error {
+	var items []int
+	if errs := parseBody(c, &items); errs != nil {
+		return invalidBody(c, errs)
+	}
+	if len(items) == 0 || len(items) > maxBatchOrders {
+		return invalidBody(c, fieldErrors{bodyField: fmt.Sprintf("must hold 1 to %d numbers of items", maxBatchOrders)})
+	}
+	opts, err := o.orderOptions(c)
+	if err != nil {
+		return invalidParam(c, err)
+	}
+
+	status := http.StatusOK
+	results := make([]models.BatchOrderResult, len(items))
+	for i, amount := range items {
+		results[i] = models.BatchOrderResult{Items: amount, Status: http.StatusOK}
+		if amount <= 0 {
+			results[i].Status, results[i].Error = http.StatusBadRequest, "Invalid items: must be positive"
+			status = http.StatusMultiStatus
+			continue
+		}
+
+		order, err := o.storage.CalculateOrder(c.UserContext(), amount, opts...)
+		if err != nil {
+			results[i].Status, results[i].Error = orderErrorStatus(err)
+			status = http.StatusMultiStatus
+			continue
+		}
+		results[i].Order = &order
+	}
+
+	return c.Status(status).JSON(results)
+}
+
+// QuoteOrder handles GET /orders/quote/{amount}
+// @Summary Quote an order
+// @Description Get the total and overpacked items and the number of packs for the specified number of items without the pack breakdown. The order is not recorded.
+// @Tags orders
+// @Produce json
+// @Param amount path int true "Number of items"
+// @Param strategy query string false "Packing algorithm" Enums(greedy, min-overpack, fewest-sizes, distinct-packs, max-under) default(greedy)
+// @Param fillerPack query int false "Pack amount used to fill the remaining items instead of the smallest pack, greedy strategy only, rejected with any other strategy"
+// @Param maxOverpack query string false "Max overpacked items, either a number of items (100) or a percentage of the requested items (10%)"
+// @Param maxPerSize query int false "Max number of packs of a single size"
+// @Param requirePack query int false "Pack amount the order must include at least once"
+// @Param belowSmallestPack query bool false "Guarantee an overpack below the smallest pack amount, orders that can't meet it are rejected with 422"
+// @Param version query int false "Version of the pack set to quote against instead of the current packs, see GET /packs/at"
+// @Success 200 {object} models.Quote
+// @Failure 400 {object} map[string]string "Invalid amount, strategy, filler pack, max overpack, max per size, required pack or version"
+// @Failure 404 {object} map[string]string "No packs available or pack set version not found"
+// @Failure 422 {object} map[string]string "Overpack exceeds the allowed maximum, items can't be packed within the constraints, the order is too complex or needs too many packs"
+// @Failure 503 {object} map[string]string "Order computation timed out"
+// @Router /orders/quote/{amount} [get]
+func (o *Orders) QuoteOrder(c *fiber.Ctx) error {
+	amount, err := positiveParam(c, "amount", "amount")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+	opts, err := o.orderOptions(c)
+	if err != nil {
+		return invalidParam(c, err)
+	}
+
+	version, atVersion, err := optionalNonNegativeQuery(c, "version", "version")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+
+	var quote models.Quote
+	if atVersion {
+		quote, err = o.storage.QuoteOrderAtVersion(c.UserContext(), version, amount, opts...)
+	} else {
+		quote, err = o.storage.QuoteOrder(c.UserContext(), amount, opts...)
+	}
+	if err != nil {
+		return orderError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(quote)
+}
+
+// MinPackCount handles GET /orders/minpacks/{amount}
+// @Summary Get the minimum number of packs for an order
+// @Description Get the fewest packs holding the specified number of items with the least overpack against the current packs,
+// @Description the pack count of the min-overpack strategy, as a plain number. The order is not recorded.
+// @Tags orders
+// @Produce json
+// @Param amount path int true "Number of items"
+// @Success 200 {integer} int
+// @Failure 400 {object} map[string]string "Invalid amount"
+// @Failure 404 {object} map[string]string "No packs available"
+// @Failure 422 {object} map[string]string "The order is too complex or needs too many packs"
+// @Failure 503 {object} map[string]string "Order computation timed out"
+// @Router /orders/minpacks/{amount} [get]
+func (o *Orders) MinPackCount(c *fiber.Ctx) error {
+	amount, err := positiveParam(c, "amount", "amount")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+
+	count, err := o.storage.MinPackCount(c.UserContext(), amount)
+	if err != nil {
+		return orderError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(count)
+}
+
+// ReturnItems handles POST /orders/return/{amount}
+// @Summary Process a return
+// @Description Get the packs to disassemble to take the returned items out of packed stock. The amount is the returned quantity,
+// @Description either negative like a ledger entry (-750) or positive (750), zero is rejected. The packs are chosen like the packs
+// @Description of an order of the returned items with the same query parameters, looseItems are the items left over in the opened packs.
+// @Description The max-under strategy only succeeds if it packs the items exactly. No order is recorded.
+// @Tags orders
+// @Produce json
+// @Param amount path int true "Returned quantity, negative or positive"
+// @Param strategy query string false "Packing algorithm" Enums(greedy, min-overpack, fewest-sizes, distinct-packs, max-under) default(greedy)
+// @Param fillerPack query int false "Pack amount used to fill the remaining items instead of the smallest pack, greedy strategy only, rejected with any other strategy"
+// @Param maxOverpack query string false "Max loose items, either a number of items (100) or a percentage of the returned items (10%)"
+// @Param maxPerSize query int false "Max number of packs of a single size"
+// @Param requirePack query int false "Pack amount to disassemble at least once"
+// @Param belowSmallestPack query bool false "Guarantee fewer loose items than the smallest pack amount, returns that can't meet it are rejected with 422"
+// @Success 200 {object} models.Return
+// @Failure 400 {object} map[string]string "Invalid amount, strategy, filler pack, max overpack, max per size or required pack"
+// @Failure 404 {object} map[string]string "No packs available"
+// @Failure 422 {object} map[string]string "Loose items exceed the allowed maximum, the items can't be taken out within the constraints, the return is too complex or needs too many packs"
+// @Failure 503 {object} map[string]string "Order computation timed out"
+// @Router /orders/return/{amount} [post]
+func (o *Orders) ReturnItems(c *fiber.Ctx) error {
+	amount, err := returnParam(c, "amount", "amount")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+	opts, err := o.orderOptions(c)
+	if err != nil {
+		return invalidParam(c, err)
+	}
+
+	result, err := o.storage.ReturnItems(c.UserContext(), amount, opts...)
+	if err != nil {
+		return orderError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(result)
+}
+
+// CompareStrategies handles GET /orders/compare/{amount}
+// @Summary Compare the greedy and the min-overpack strategies
+// @Description Pack the specified number of items with both the greedy and the min-overpack strategies against the current packs
+// @Description and report both results side by side with their overpack and pack counts. No orders are recorded.
+// @Tags orders
+// @Produce json
+// @Param amount path int true "Number of items"
+// @Param fillerPack query int false "Pack amount used to fill the remaining items instead of the smallest pack, greedy strategy only, rejected with any other strategy"
+// @Param maxOverpack query string false "Max overpacked items, either a number of items (100) or a percentage of the requested items (10%)"
+// @Param maxPerSize query int false "Max number of packs of a single size"
+// @Param requirePack query int false "Pack amount the order must include at least once"
+// @Param belowSmallestPack query bool false "Guarantee an overpack below the smallest pack amount, orders that can't meet it are rejected with 422"
+// @Success 200 {object} models.StrategyComparison
+// @Failure 400 {object} map[string]string "Invalid amount, filler pack, max overpack, max per size or required pack"
+// @Failure 404 {object} map[string]string "No packs available"
+// @Failure 422 {object} map[string]string "Either strategy exceeds the allowed overpack, can't pack the items within the constraints, the order is too complex or needs too many packs"
+// @Failure 503 {object} map[string]string "Order computation timed out"
+// @Router /orders/compare/{amount} [get]
+func (o *Orders) CompareStrategies(c *fiber.Ctx) error {
+	amount, err := positiveParam(c, "amount", "amount")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+	opts, err := o.orderOptions(c)
+	if err != nil {
+		return invalidParam(c, err)
+	}
+
+	comparison, err := o.storage.CompareStrategies(c.UserContext(), amount, opts...)
+	if err != nil {
+		return orderError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(comparison)
+}
+
+// OrderCandidates handles GET /orders/candidates/{amount}
+// @Summary Get candidate orders
+// @Description Pack the specified number of items into up to count distinct orders ranked by the strategy, best first, to pick among
+// @Description the trade-offs. Every candidate holds more items than the one before it, so the overpack grows. No orders are recorded.
+// @Tags orders
+// @Produce json
+// @Param amount path int true "Number of items"
+// @Param count query int false "Number of candidates, 3 by default, at most 10"
+// @Param strategy query string false "Packing algorithm" Enums(greedy, min-overpack, fewest-sizes, distinct-packs, max-under) default(greedy)
+// @Param fillerPack query int false "Pack amount used to fill the remaining items instead of the smallest pack, greedy strategy only, rejected with any other strategy"
+// @Param maxOverpack query string false "Max overpacked items, either a number of items (100) or a percentage of the requested items (10%)"
+// @Param maxPerSize query int false "Max number of packs of a single size"
+// @Param requirePack query int false "Pack amount the order must include at least once"
+// @Param belowSmallestPack query bool false "Guarantee an overpack below the smallest pack amount, orders that can't meet it are rejected with 422"
+// @Success 200 {array} models.Order
+// @Failure 400 {object} map[string]string "Invalid amount, count, strategy, filler pack, max overpack, max per size or required pack"
+// @Failure 404 {object} map[string]string "No packs available"
+// @Failure 422 {object} map[string]string "Overpack exceeds the allowed maximum, items can't be packed within the constraints, the order is too complex or needs too many packs"
+// @Failure 503 {object} map[string]string "Order computation timed out"
+// @Router /orders/candidates/{amount} [get]
+func (o *Orders) OrderCandidates(c *fiber.Ctx) error {
+	amount, err := positiveParam(c, "amount", "amount")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+	count, err := optionalPositiveQuery(c, "count", "count")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+	if count == 0 {
+		count = defaultCandidates
+	}
+	opts, err := o.orderOptions(c)
+	if err != nil {
+		return invalidParam(c, err)
+	}
+
+	candidates, err := o.storage.OrderCandidates(c.UserContext(), amount, count, opts...)
+	if err != nil {
+		return orderError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(candidates)
+}
+
 // GetOrders handles GET /orders
 // @Summary Get all orders
-// @Description Retrieve a list of all orders
+// @Description Retrieve a list of all orders, oldest first, optionally filtered by the range of requested items and the reference.
+// @Description The list is empty if no orders match.
 // @Tags orders
 // @Produce json
-// @Success 200 {array} models.Order
+// @Param minItems query int false "Min requested items, inclusive"
+// @Param maxItems query int false "Max requested items, inclusive"
+// @Param reference query string false "Reference the orders were tagged with"
+// @Param flat query bool false "Respond with flat packs {amount, quantity, subtotal} instead of nested packs"
+// @Success 200 {array} models.Order "The orders, or models.FlatOrder with flat=true"
+// @Failure 400 {object} map[string]string "Invalid items range or flat"
 // @Router /orders [get]
 func (o *Orders) GetOrders(c *fiber.Ctx) error {
-	orders := o.storage.GetOrders()
+	minItems, err := optionalPositiveQuery(c, "minItems", "min items")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+	maxItems, err := optionalPositiveQuery(c, "maxItems", "max items")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+	if minItems > 0 && maxItems > 0 && minItems > maxItems {
+		return invalidParam(c, &paramError{name: "items range", reason: errParamRange})
+	}
+	flat, err := optionalBoolQuery(c, "flat", "flat")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+
+	orders := o.storage.FindOrders(c.UserContext(), storage.OrderFilter{
+		MinItems:  minItems,
+		MaxItems:  maxItems,
+		Reference: c.Query("reference"),
+	})
 
 	c.Set("Content-Type", "application/json")
+	if flat {
+		flatOrders := make([]models.FlatOrder, len(orders))
+		for i, order := range orders {
+			flatOrders[i] = order.Flat()
+		}
+		return c.Status(http.StatusOK).JSON(flatOrders)
+	}
 	return c.Status(http.StatusOK).JSON(orders)
 }
+
+// streamHeartbeat is how often a comment is sent to idle order streams to detect disconnected clients
+const streamHeartbeat = 15 * time.Second
+
+// StreamOrders handles GET /orders/stream
+// @Summary Stream new orders
+// @Description Stream every new order as a server-sent event named "order" with the order as JSON data.
+// @Description Orders are dropped for clients that don't keep up.
+// @Tags orders
+// @Produce text/event-stream
+// @Success 200 {object} models.Order "Stream of orders"
+// @Router /orders/stream [get]
+func (o *Orders) StreamOrders(c *fiber.Ctx) error {
+	orders, unsubscribe := o.storage.SubscribeOrders(c.UserContext())
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		heartbeat := time.NewTicker(streamHeartbeat)
+		defer heartbeat.Stop()
+
+		// Send the headers right away so clients know the stream is open
+		if err := w.Flush(); err != nil {
+			return
+		}
+		for {
+			select {
+			case order, ok := <-orders:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(order)
+				if err != nil {
+					return
+				}
+				fmt.Fprintf(w, "event: order\ndata: %s\n\n", data)
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+			}
+
+			// Flushing fails once the client is gone
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+// GetReceipt handles GET /orders/{id}/receipt
+// @Summary Get the receipt of an order
+// @Description Render the recorded order with the specified ID as a receipt listing the packs, their quantities and subtotals,
+// @Description and the totals. The format is negotiated with the Accept header: HTML by default or plain text.
+// @Tags orders
+// @Produce html,plain
+// @Param id path int true "Order ID"
+// @Success 200 {string} string "Receipt"
+// @Failure 400 {object} map[string]string "Invalid ID"
+// @Failure 404 {object} map[string]string "Order not found"
+// @Failure 406 {object} map[string]string "Neither HTML nor plain text is acceptable"
+// @Router /orders/{id}/receipt [get]
+func (o *Orders) GetReceipt(c *fiber.Ctx) error {
+	id, err := positiveParam(c, "id", "ID")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+
+	order, err := o.storage.GetOrder(c.UserContext(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrOrderNotFound) {
+			return c.Status(http.StatusNotFound).JSON(map[string]string{"error": "Order not found"})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(map[string]string{"error": "Failed to get order"})
+	}
+
+	switch c.Accepts(fiber.MIMETextHTML, fiber.MIMETextPlain) {
+	case fiber.MIMETextHTML:
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return writeHTMLReceipt(c.Response().BodyWriter(), order)
+	case fiber.MIMETextPlain:
+		c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+		return writeTextReceipt(c.Response().BodyWriter(), order)
+	default:
+		return c.Status(http.StatusNotAcceptable).JSON(map[string]string{"error": "Receipts are available as text/html or text/plain"})
+	}
+}
+
+// orderOptions parses the optional order calculation parameters from the query.
+// The filler pack is only used by the greedy strategy, it's rejected with any other selected or default strategy.
+func (o *Orders) orderOptions(c *fiber.Ctx) ([]packing.Option, error) {
+	fillerPack, err := optionalPositiveQuery(c, "fillerPack", "filler pack")
+	if err != nil {
+		return nil, err
+	}
+	maxOverpack, err := optionalOverpackQuery(c, "maxOverpack", "max overpack")
+	if err != nil {
+		return nil, err
+	}
+	includeUnused, err := optionalBoolQuery(c, "includeUnused", "include unused")
+	if err != nil {
+		return nil, err
+	}
+	maxPerSize, err := optionalPositiveQuery(c, "maxPerSize", "max per size")
+	if err != nil {
+		return nil, err
+	}
+	requirePack, err := optionalPositiveQuery(c, "requirePack", "require pack")
+	if err != nil {
+		return nil, err
+	}
+	belowSmallestPack, err := optionalBoolQuery(c, "belowSmallestPack", "below smallest pack")
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []packing.Option{
+		packing.WithFillerPack(fillerPack),
+		packing.WithMaxQuantityPerSize(maxPerSize),
+		packing.WithRequiredPack(requirePack),
+	}
+	strategy := o.storage.DefaultStrategy(c.UserContext())
+	if raw := c.Query("strategy"); raw != "" {
+		strategy, err = packing.ParseStrategy(raw)
+		if err != nil {
+			return nil, &paramError{name: "strategy", reason: errParamNotStrategy}
+		}
+		opts = append(opts, packing.WithStrategy(strategy))
+	}
+	if fillerPack > 0 && strategy != packing.StrategyGreedy {
+		return nil, &paramError{name: "filler pack", reason: errParamGreedyOnly}
+	}
+	if includeUnused {
+		opts = append(opts, packing.WithUnusedPacks())
+	}
+	if belowSmallestPack {
+		opts = append(opts, packing.WithOverpackBelowSmallestPack())
+	}
+	switch {
+	case maxOverpack == nil:
+	case maxOverpack.isPercent:
+		opts = append(opts, packing.WithMaxOverpackPercent(maxOverpack.percent))
+	default:
+		opts = append(opts, packing.WithMaxOverpack(maxOverpack.items))
+	}
+
+	return opts, nil
+}
+
+// orderError maps order calculation errors to responses.
+// Requests that are valid but can't be satisfied within their constraints get 422, malformed requests get 400
+// before the calculation, and 404 means there are no packs to calculate with.
+func orderError(c *fiber.Ctx, err error) error {
+	status, message := orderErrorStatus(err)
+	return c.Status(status).JSON(map[string]string{"error": message})
+}
+
+// orderErrorStatus returns the status and the message of the response to an order calculation error
+func orderErrorStatus(err error) (int, string) {
+	var packsErr *storage.OrderPacksError
+	switch {
+	case errors.Is(err, storage.ErrNoPacksAvailable):
+		return http.StatusNotFound, "No packs available"
+	case errors.Is(err, storage.ErrVersionNotFound):
+		return http.StatusNotFound, "Pack set version not found"
+	case errors.Is(err, storage.ErrFillerNotFound):
+		return http.StatusBadRequest, "Filler pack not found"
+	case errors.Is(err, storage.ErrRequiredPackNotFound):
+		return http.StatusBadRequest, "Required pack not found"
+	case errors.Is(err, packing.ErrOverpackExceeded):
+		return http.StatusUnprocessableEntity, "Overpack exceeds the allowed maximum"
+	case errors.Is(err, storage.ErrUnsatisfiable):
+		return http.StatusUnprocessableEntity, "Items can't be packed within the constraints"
+	case errors.As(err, &packsErr):
+		return http.StatusUnprocessableEntity, fmt.Sprintf("Order needs too many packs, at most %d", packsErr.Limit)
+	case errors.Is(err, packing.ErrComputationComplexity):
+		return http.StatusUnprocessableEntity, "Order is too complex to compute with the current packs"
+	case errors.Is(err, storage.ErrItemsOutOfRange):
+		return http.StatusUnprocessableEntity, "Requested items are out of range for the packs"
+	case errors.Is(err, storage.ErrComputationTimeout):
+		return http.StatusServiceUnavailable, "Order computation timed out"
+	default:
+		return http.StatusInternalServerError, "Internal server error"
+	}
+}
@@ -0,0 +1,696 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/internal/storage"
+	"github.com/corel-frim/item-packer-inc/internal/storage/storagetest"
+	"github.com/corel-frim/item-packer-inc/packing"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateOrderMaxOverpack(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	storagetest.AddPacks(t, packStorage, 250, 500, 1000)
+	app := newTestApp(packStorage)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"absolute within limit", "/orders/items/1001?maxOverpack=249", http.StatusOK},
+		{"absolute exceeded", "/orders/items/1001?maxOverpack=100", http.StatusUnprocessableEntity},
+		{"percentage within limit", "/orders/items/1001?maxOverpack=25%25", http.StatusOK},
+		{"percentage exceeded", "/orders/items/1001?maxOverpack=10%25", http.StatusUnprocessableEntity},
+		{"negative", "/orders/items/1001?maxOverpack=-1", http.StatusBadRequest},
+		{"malformed", "/orders/items/1001?maxOverpack=abc", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(http.MethodPost, tt.path, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+
+	// Rejected orders aren't recorded
+	assert.Len(t, packStorage.GetOrders(t.Context()), 2)
+}
+
+func TestGetOrdersFilters(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 250)
+	_, _ = packStorage.CalculateOrder(t.Context(), 100)
+	for _, items := range []int{500, 1000} {
+		_, _ = packStorage.CalculateTaggedOrder(t.Context(), items, models.OrderTags{Reference: "ABC123"})
+	}
+	app := newTestApp(packStorage)
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+		items  []int
+	}{
+		{"no filter", "", http.StatusOK, []int{100, 500, 1000}},
+		{"min", "?minItems=500", http.StatusOK, []int{500, 1000}},
+		{"max", "?maxItems=500", http.StatusOK, []int{100, 500}},
+		{"range", "?minItems=200&maxItems=900", http.StatusOK, []int{500}},
+		{"empty range", "?minItems=600&maxItems=900", http.StatusOK, []int{}},
+		{"reference", "?reference=ABC123", http.StatusOK, []int{500, 1000}},
+		{"reference and range", "?reference=ABC123&maxItems=500", http.StatusOK, []int{500}},
+		{"unknown reference", "?reference=XYZ", http.StatusOK, []int{}},
+		{"min exceeds max", "?minItems=900&maxItems=200", http.StatusBadRequest, nil},
+		{"malformed", "?minItems=abc", http.StatusBadRequest, nil},
+		{"not positive", "?maxItems=0", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/orders"+tt.query, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var orders []models.Order
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&orders))
+			items := make([]int, 0, len(orders))
+			for _, order := range orders {
+				items = append(items, order.RequestedItems)
+			}
+			assert.Equal(t, tt.items, items)
+		})
+	}
+}
+
+func TestStreamOrdersClosedStorage(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	app := newTestApp(packStorage)
+
+	// The stream ends when the storage is closed
+	assert.NoError(t, packStorage.Close(t.Context()))
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/orders/stream", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+}
+
+func TestCreateOrderIncludeUnused(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	storagetest.AddPacks(t, packStorage, 250, 500, 1000)
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/orders/items/1250?includeUnused=true", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var order models.Order
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&order))
+	assert.Len(t, order.Packs, 3)
+	assert.Equal(t, 500, order.Packs[1].Pack.Amount)
+	assert.Equal(t, 0, order.Packs[1].Quantity)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/orders/items/1250?includeUnused=maybe", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid include unused: must be true or false", errorMessage(t, resp))
+}
+
+func TestCreateOrderStrategy(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	storagetest.AddPacks(t, packStorage, 4, 6, 9)
+	app := newTestApp(packStorage)
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+		total  int
+	}{
+		{"default", "", http.StatusOK, 13},
+		{"greedy", "?strategy=greedy", http.StatusOK, 13},
+		{"min overpack", "?strategy=min-overpack", http.StatusOK, 12},
+		{"max under", "?strategy=max-under", http.StatusOK, 10},
+		{"unknown", "?strategy=fastest", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/orders/items/11"+tt.query, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if tt.status != http.StatusOK {
+				assert.Equal(t, "Invalid strategy: must be greedy, min-overpack, fewest-sizes, distinct-packs or max-under", errorMessage(t, resp))
+				return
+			}
+
+			var order models.Order
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&order))
+			assert.Equal(t, tt.total, order.TotalItems)
+		})
+	}
+}
+
+func TestCreateOrderMaxUnder(t *testing.T) {
+	app := newTestApp(storagetest.NewStorage(t))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/orders/items/251?strategy=max-under", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var order models.Order
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&order))
+	assert.Equal(t, 250, order.TotalItems)
+	assert.Equal(t, 0, order.OverpackedItems)
+	assert.Equal(t, 1, order.UnfulfilledItems)
+
+	// Nothing fits below the smallest pack
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/orders/items/249?strategy=max-under", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestCreateOrderDefaultStrategy(t *testing.T) {
+	packStorage := storage.NewPackStorage(storage.WithDefaultStrategy(packing.StrategyMinOverpack))
+	storagetest.AddPacks(t, packStorage, 4, 6, 9)
+	app := newTestApp(packStorage)
+
+	tests := []struct {
+		name  string
+		path  string
+		total int
+	}{
+		{"server default", "/orders/items/11", 12},
+		{"request overrides", "/orders/items/11?strategy=greedy", 13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(http.MethodPost, tt.path, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var order models.Order
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&order))
+			assert.Equal(t, tt.total, order.TotalItems)
+		})
+	}
+}
+
+func TestCreateOrderMaxPerSize(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	storagetest.AddPacks(t, packStorage, 250, 500, 1000)
+	app := newTestApp(packStorage)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"within the cap", "/orders/items/3000?maxPerSize=2", http.StatusOK},
+		{"unsatisfiable", "/orders/items/5000?maxPerSize=2", http.StatusUnprocessableEntity},
+		{"unsatisfiable min overpack", "/orders/items/5000?maxPerSize=2&strategy=min-overpack", http.StatusUnprocessableEntity},
+		{"not positive", "/orders/items/3000?maxPerSize=0", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(http.MethodPost, tt.path, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+
+	// A single 1000 holds 751 items within the cap, the 500 and 250 of the fill are dropped
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/orders/quote/751?maxPerSize=1", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var quote models.Quote
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&quote))
+	assert.Equal(t, 1000, quote.TotalItems)
+	assert.Equal(t, 249, quote.OverpackedItems)
+}
+
+func TestCreateOrderRequirePack(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	storagetest.AddPacks(t, packStorage, 250, 500, 1000)
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/orders/items/250?requirePack=1000", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var order models.Order
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&order))
+	assert.Equal(t, 1000, order.TotalItems)
+	assert.Equal(t, 750, order.OverpackedItems)
+}
+
+func TestQuoteOrderAtVersion(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 250)
+	_, _ = packStorage.UpdatePack(t.Context(), 250, 500)
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/orders/quote/100?version=1", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var quote models.Quote
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&quote))
+	assert.Equal(t, 250, quote.TotalItems)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/orders/quote/100", nil))
+	assert.NoError(t, err)
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&quote))
+	assert.Equal(t, 500, quote.TotalItems)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/orders/quote/100?version=0", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "No packs available", errorMessage(t, resp))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/orders/quote/100?version=3", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "Pack set version not found", errorMessage(t, resp))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/orders/quote/100?version=x", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid version: must be an integer", errorMessage(t, resp))
+}
+
+func TestMinPackCount(t *testing.T) {
+	packStorage := storagetest.NewStorage(t)
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/orders/minpacks/12001", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "4", string(body))
+	assert.Empty(t, packStorage.GetOrders(t.Context()))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/orders/minpacks/0", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = newTestApp(storage.NewPackStorage()).Test(httptest.NewRequest(http.MethodGet, "/orders/minpacks/1", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestReturnItems(t *testing.T) {
+	packStorage := storagetest.NewStorage(t)
+	app := newTestApp(packStorage)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		error  string
+	}{
+		{"negative", "/orders/return/-751", http.StatusOK, ""},
+		{"positive", "/orders/return/751", http.StatusOK, ""},
+		{"zero", "/orders/return/0", http.StatusBadRequest, "Invalid amount: must not be zero"},
+		{"min int", "/orders/return/-9223372036854775808", http.StatusBadRequest, "Invalid amount: is out of range"},
+		{"not integer", "/orders/return/abc", http.StatusBadRequest, "Invalid amount: must be an integer"},
+		{"max overpack", "/orders/return/-751?maxOverpack=100", http.StatusUnprocessableEntity, "Overpack exceeds the allowed maximum"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(http.MethodPost, tt.path, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if tt.status != http.StatusOK {
+				assert.Equal(t, tt.error, errorMessage(t, resp))
+				return
+			}
+
+			var result models.Return
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			assert.Equal(t, 751, result.ReturnedItems)
+			assert.Equal(t, 1000, result.DisassembledItems)
+			assert.Equal(t, 249, result.LooseItems)
+			assert.Len(t, result.Packs, 1)
+		})
+	}
+	assert.Empty(t, packStorage.GetOrders(t.Context()))
+}
+
+func TestFlatOrders(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	storagetest.AddPacks(t, packStorage, 250, 500)
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/orders/items/501?flat=true", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var order models.FlatOrder
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&order))
+	assert.Equal(t, []models.FlatOrderPack{{Amount: 500, Quantity: 1, Subtotal: 500}, {Amount: 250, Quantity: 1, Subtotal: 250}}, order.Packs)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/orders/items/501?flat=true&trace=true", nil))
+	assert.NoError(t, err)
+	var traced models.FlatTracedOrder
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&traced))
+	assert.Equal(t, order.Packs, traced.Order.Packs)
+	assert.NotEmpty(t, traced.Trace)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/orders?flat=true", nil))
+	assert.NoError(t, err)
+	var raw []map[string]any
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&raw))
+	assert.Len(t, raw, 2)
+	assert.Equal(t, map[string]any{"amount": 500.0, "quantity": 1.0, "subtotal": 500.0}, raw[0]["packs"].([]any)[0])
+
+	// Nested packs stay the default
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/orders", nil))
+	assert.NoError(t, err)
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&raw))
+	assert.Contains(t, raw[0]["packs"].([]any)[0], "pack")
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/orders?flat=maybe", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid flat: must be true or false", errorMessage(t, resp))
+}
+
+func TestCreateOrderTags(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 250)
+	app := newTestApp(packStorage)
+
+	post := func(body string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, "/orders/items/100", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	resp := post(`{"reference": "ABC123", "metadata": {"customer": "ACME"}}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var order models.Order
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&order))
+	assert.Equal(t, "ABC123", order.Reference)
+	assert.Equal(t, map[string]string{"customer": "ACME"}, order.Metadata)
+	assert.Equal(t, "ABC123", packStorage.GetOrders(t.Context())[0].Reference)
+
+	// The body is optional
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/orders/items/100", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = post(`{"reference": "` + strings.Repeat("A", 65) + `"}`)
+	assert.Equal(t, map[string]string{"reference": "must be at most 64 characters"}, bodyErrors(t, resp))
+
+	metadata := make(map[string]string)
+	for i := range 11 {
+		metadata[strconv.Itoa(i)] = "value"
+	}
+	body, _ := json.Marshal(models.OrderTags{Metadata: metadata})
+	resp = post(string(body))
+	assert.Equal(t, map[string]string{
+		"metadata": "must have at most 10 entries with keys and values of at most 256 characters",
+	}, bodyErrors(t, resp))
+
+	// Every invalid field is reported
+	resp = post(`{"reference": "` + strings.Repeat("A", 65) + `", "metadata": {"note": "` + strings.Repeat("a", 257) + `"}}`)
+	assert.Equal(t, map[string]string{
+		"reference":     "must be at most 64 characters",
+		"metadata.note": "must have at most 10 entries with keys and values of at most 256 characters",
+	}, bodyErrors(t, resp))
+
+	resp = post(`{"metadata": {"": "value"}}`)
+	assert.Equal(t, map[string]string{"metadata": "keys must not be empty"}, bodyErrors(t, resp))
+
+	resp = post(`{"reference": 1}`)
+	assert.Equal(t, map[string]string{"reference": "must be a string"}, bodyErrors(t, resp))
+
+	assert.Len(t, packStorage.GetOrders(t.Context()), 2)
+}
+
+func TestCompareStrategies(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	storagetest.AddPacks(t, packStorage, 4, 6, 9)
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/orders/compare/11", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var comparison models.StrategyComparison
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&comparison))
+	assert.Equal(t, 2, comparison.Greedy.OverpackedItems)
+	assert.Equal(t, 1, comparison.Optimal.OverpackedItems)
+	assert.Equal(t, 1, comparison.OverpackReduction)
+	assert.Empty(t, packStorage.GetOrders(t.Context()))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/orders/compare/11?maxOverpack=0", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/orders/compare/0", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestOrderCandidates(t *testing.T) {
+	packStorage := storagetest.NewStorage(t)
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/orders/candidates/251", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var candidates []models.Order
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&candidates))
+	assert.Len(t, candidates, 3)
+	assert.Equal(t, 500, candidates[0].TotalItems)
+	assert.Equal(t, 750, candidates[1].TotalItems)
+	assert.Empty(t, packStorage.GetOrders(t.Context()))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/orders/candidates/251?count=1&strategy=min-overpack", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&candidates))
+	assert.Len(t, candidates, 1)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/orders/candidates/251?maxOverpack=100", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/orders/candidates/251?count=0", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestCreateOrderCartons(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	storagetest.AddPacks(t, packStorage, 250, 500)
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/orders/items/1250?cartons=true", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var order models.Order
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&order))
+	assert.Equal(t, []models.Carton{
+		{Number: 1, PackID: 2, Amount: 500},
+		{Number: 2, PackID: 2, Amount: 500},
+		{Number: 3, PackID: 1, Amount: 250},
+	}, order.Cartons)
+
+	// Cartons are a presentation of the response, the recorded order doesn't have them
+	assert.Nil(t, packStorage.GetOrders(t.Context())[0].Cartons)
+
+	// Too many packs are rejected before the order is recorded
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/orders/items/1000000?cartons=true", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, "Order has too many packs to list as cartons, at most 1000", errorMessage(t, resp))
+	assert.Len(t, packStorage.GetOrders(t.Context()), 1)
+}
+
+func TestGetReceipt(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_, _ = packStorage.AddLabeledPack(t.Context(), 500, "<b>box</b>")
+	_ = packStorage.AddPack(t.Context(), 250)
+	_, _ = packStorage.CalculateTaggedOrder(t.Context(), 700, models.OrderTags{Reference: "ABC123"})
+	app := newTestApp(packStorage)
+
+	get := func(path, accept string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	// HTML by default, with the labels escaped
+	resp := get("/orders/1/receipt", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, fiber.MIMETextHTMLCharsetUTF8, resp.Header.Get("Content-Type"))
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), "<h1>Receipt for order #1</h1>")
+	assert.Contains(t, string(body), "<p>Reference: ABC123</p>")
+	assert.Contains(t, string(body), "<tr><td>500 (&lt;b&gt;box&lt;/b&gt;)</td><td>1</td><td>500</td></tr>")
+	assert.Contains(t, string(body), "<p>Overpacked items: 50 (7.14%)</p>")
+
+	resp = get("/orders/1/receipt", "text/plain")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, fiber.MIMETextPlainCharsetUTF8, resp.Header.Get("Content-Type"))
+	body, _ = io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), "Pack              Quantity  Subtotal\n500 (<b>box</b>)  1         500\n250               1         250\n")
+	assert.Contains(t, string(body), "Total items: 750\n")
+
+	resp = get("/orders/1/receipt", "application/pdf")
+	assert.Equal(t, http.StatusNotAcceptable, resp.StatusCode)
+
+	resp = get("/orders/2/receipt", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "Order not found", errorMessage(t, resp))
+
+	resp = get("/orders/abc/receipt", "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid ID: must be an integer", errorMessage(t, resp))
+}
+
+func TestCreateOrders(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	storagetest.AddPacks(t, packStorage, 250, 500)
+	app := newTestApp(packStorage)
+
+	post := func(body, query string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, "/orders"+query, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	// All entries succeed
+	resp := post("[250, 750]", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var results []models.BatchOrderResult
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&results))
+	assert.Len(t, results, 2)
+	assert.Equal(t, 750, results[1].Order.TotalItems)
+	assert.Len(t, packStorage.GetOrders(t.Context()), 2)
+
+	// The failing entries don't stop the others
+	resp = post("[251, 500, 0]", "?maxOverpack=100")
+	assert.Equal(t, http.StatusMultiStatus, resp.StatusCode)
+	results = nil
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&results))
+	assert.Equal(t, []models.BatchOrderResult{
+		{Items: 251, Status: http.StatusUnprocessableEntity, Error: "Overpack exceeds the allowed maximum"},
+		{Items: 500, Status: http.StatusOK, Order: results[1].Order},
+		{Items: 0, Status: http.StatusBadRequest, Error: "Invalid items: must be positive"},
+	}, results)
+	assert.NotNil(t, results[1].Order)
+	assert.Len(t, packStorage.GetOrders(t.Context()), 3)
+
+	// Malformed requests fail as a whole
+	assert.Equal(t, http.StatusBadRequest, post("[]", "").StatusCode)
+	assert.Equal(t, http.StatusBadRequest, post("{", "").StatusCode)
+	assert.Equal(t, http.StatusBadRequest, post("[250]", "?maxPerSize=abc").StatusCode)
+}
+
+func TestCreateOrderTrace(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	storagetest.AddPacks(t, packStorage, 250, 500, 1000)
+	app := newTestApp(packStorage)
+
+	// The same request twice, the second one would be cached without tracing
+	for i := 0; i < 2; i++ {
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/orders/items/751?trace=true", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var traced models.TracedOrder
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&traced))
+		assert.Equal(t, 1000, traced.Order.TotalItems)
+		assert.Len(t, traced.Trace, 5)
+		assert.Equal(t, models.TraceMerge, traced.Trace[4].Action)
+	}
+
+	// Traced orders are recorded as usual
+	assert.Len(t, packStorage.GetOrders(t.Context()), 2)
+}
+
+func TestCreateOrderErrorStatuses(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	storagetest.AddPacks(t, packStorage, 250, 500, 1000)
+	app := newTestApp(packStorage)
+
+	// Large coprime packs for the complexity guard of the min-overpack strategy
+	complexStorage := storage.NewPackStorage()
+	_ = complexStorage.AddPack(t.Context(), 1_000_003)
+	_ = complexStorage.AddPack(t.Context(), 999_983)
+	complexApp := newTestApp(complexStorage)
+
+	// The filler pack is only used by the greedy strategy
+	minOverpackStorage := storage.NewPackStorage(storage.WithDefaultStrategy(packing.StrategyMinOverpack))
+	storagetest.AddPacks(t, minOverpackStorage, 250, 500)
+	minOverpackApp := newTestApp(minOverpackStorage)
+
+	// Packs so large that any amount overflows the totals
+	hugeStorage := storage.NewPackStorage()
+	_ = hugeStorage.AddPack(t.Context(), math.MaxInt-10)
+	hugeApp := newTestApp(hugeStorage)
+
+	cappedStorage := storage.NewPackStorage(storage.WithMaxOrderPacks(10))
+	_ = cappedStorage.AddPack(t.Context(), 250)
+	cappedApp := newTestApp(cappedStorage)
+
+	tests := []struct {
+		name    string
+		app     *fiber.App
+		path    string
+		status  int
+		message string
+	}{
+		{"malformed amount", app, "/orders/items/abc", http.StatusBadRequest, "Invalid amount: must be an integer"},
+		{"malformed max overpack", app, "/orders/items/1001?maxOverpack=abc", http.StatusBadRequest, "Invalid max overpack: must be a number of items or a percentage like 10%"},
+		{"unknown filler", app, "/orders/items/1001?fillerPack=300", http.StatusBadRequest, "Filler pack not found"},
+		{"filler with another strategy", app, "/orders/items/1001?fillerPack=250&strategy=min-overpack", http.StatusBadRequest, "Invalid filler pack: greedy strategy only"},
+		{"filler with another default strategy", minOverpackApp, "/orders/items/1001?fillerPack=250", http.StatusBadRequest, "Invalid filler pack: greedy strategy only"},
+		{"unknown required pack", app, "/orders/items/1001?requirePack=300", http.StatusBadRequest, "Required pack not found"},
+		{"malformed required pack", app, "/orders/items/1001?requirePack=abc", http.StatusBadRequest, "Invalid require pack: must be an integer"},
+		{"overpack exceeded", app, "/orders/items/1001?maxOverpack=100", http.StatusUnprocessableEntity, "Overpack exceeds the allowed maximum"},
+		{"overpack percent exceeded", app, "/orders/items/1001?maxOverpack=10%25", http.StatusUnprocessableEntity, "Overpack exceeds the allowed maximum"},
+		{"max per size", app, "/orders/items/5000?maxPerSize=2", http.StatusUnprocessableEntity, "Items can't be packed within the constraints"},
+		{"overpack not below the smallest pack", app, "/orders/items/250?requirePack=1000&belowSmallestPack=true", http.StatusUnprocessableEntity, "Items can't be packed within the constraints"},
+		{"malformed below smallest pack", app, "/orders/items/250?belowSmallestPack=maybe", http.StatusBadRequest, "Invalid below smallest pack: must be true or false"},
+		{"too complex", complexApp, "/orders/items/5000000?strategy=min-overpack", http.StatusUnprocessableEntity, "Order is too complex to compute with the current packs"},
+		{"items out of range", hugeApp, "/orders/items/100", http.StatusUnprocessableEntity, "Requested items are out of range for the packs"},
+		{"too many packs", cappedApp, "/orders/items/2501", http.StatusUnprocessableEntity, "Order needs too many packs, at most 10"},
+		{"no packs", newTestApp(storage.NewPackStorage()), "/orders/items/1001", http.StatusNotFound, "No packs available"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.app.Test(httptest.NewRequest(http.MethodPost, tt.path, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, tt.message, errorMessage(t, resp))
+		})
+	}
+}
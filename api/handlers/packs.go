@@ -2,17 +2,32 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/corel-frim/item-packer-inc/internal/models"
 	"github.com/corel-frim/item-packer-inc/internal/storage"
+	"github.com/corel-frim/item-packer-inc/packing"
 	"github.com/gofiber/fiber/v2"
 )
 
+// PackSetVersionHeader is the response header holding the version of the pack set
+const PackSetVersionHeader = "X-Pack-Set-Version"
+
+// maxLabelLength is the max length of a pack label
+const maxLabelLength = 64
+
+var errLabelTooLong = fmt.Errorf("must be at most %d characters", maxLabelLength)
+
 type Packs struct {
-	storage *storage.PackStorage
+	storage storage.Store
 }
 
-func NewPacks(storage *storage.PackStorage) *Packs {
+func NewPacks(storage storage.Store) *Packs {
 	return &Packs{
 		storage: storage,
 	}
@@ -21,76 +36,414 @@ func NewPacks(storage *storage.PackStorage) *Packs {
 func (p *Packs) RegisterRoutes(app *fiber.App) {
 	group := app.Group("/packs")
 	group.Get("", p.GetPacks)
+	group.Get("/history", p.GetPackHistory)
+	group.Get("/at", p.GetPacksAtVersion)
+	group.Get("/recommend", p.RecommendPacks)
+	group.Get("/fit/:amount", p.FitPacks)
+	group.Get("/:amount", p.GetPack)
+	group.Post("/import", p.ImportPacks)
+	group.Post("/validate", p.ValidatePacks)
+	group.Post("/preset/:name", p.ApplyPreset)
 	group.Post("/:amount", p.AddPack)
+	// The label route goes first, so the label isn't taken for a new amount
+	group.Put("/:amount/label", p.LabelPack)
 	group.Put("/:oldAmount/:newAmount", p.UpdatePack)
 	group.Delete("/:amount", p.DeletePack)
+	group.Post("/:amount/pin", p.PinPack)
+	group.Delete("/:amount/pin", p.UnpinPack)
+	group.Put("/id/:id/:newAmount", p.UpdatePackByID)
+	group.Delete("/id/:id", p.DeletePackByID)
 }
 
 // GetPacks handles GET /packs
 // @Summary Get all available packs
-// @Description Get a list of all available packs
+// @Description Get a list of all available packs, largest first by default, optionally filtered by the range of amounts.
+// @Description Supports conditional requests with If-None-Match and If-Modified-Since, the validators describe the whole pack set.
+// @Description The X-Pack-Set-Version header holds a version which increases on every change of the packs.
 // @Tags packs
 // @Produce json
+// @Param min query int false "Min pack amount, inclusive"
+// @Param max query int false "Max pack amount, inclusive"
+// @Param sort query string false "Sort by amount ascending or descending" Enums(asc, desc) default(desc)
+// @Param If-None-Match header string false "ETag of a previously fetched pack set"
+// @Param If-Modified-Since header string false "Last-Modified of a previously fetched pack set"
 // @Success 200 {array} models.Pack
+// @Success 304 "Not Modified"
+// @Header 200,304 {string} ETag "Hash of the pack set"
+// @Header 200,304 {string} Last-Modified "Time of the last change of the pack set"
+// @Header 200,304 {integer} X-Pack-Set-Version "Version of the pack set"
+// @Failure 400 {object} map[string]string "Invalid amount range or sort"
 // @Router /packs [get]
 func (p *Packs) GetPacks(c *fiber.Ctx) error {
-	packs := p.storage.GetPacks()
+	minAmount, err := optionalPositiveQuery(c, "min", "min")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+	maxAmount, err := optionalPositiveQuery(c, "max", "max")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+	if minAmount > 0 && maxAmount > 0 && minAmount > maxAmount {
+		return invalidParam(c, &paramError{name: "amount range", reason: errParamRange})
+	}
+	ascending := false
+	switch c.Query("sort") {
+	case "", "desc":
+	case "asc":
+		ascending = true
+	default:
+		return invalidParam(c, &paramError{name: "sort", reason: errParamNotSort})
+	}
+
+	packs, state := p.storage.GetPacksWithState(c.UserContext())
+
+	c.Set(fiber.HeaderETag, `"`+state.Hash+`"`)
+	c.Set(fiber.HeaderLastModified, state.ModifiedAt.UTC().Format(http.TimeFormat))
+	c.Set(PackSetVersionHeader, strconv.Itoa(state.Version))
+	if notModified(c, state.ModifiedAt) {
+		return c.SendStatus(http.StatusNotModified)
+	}
+
+	packs = filterPacks(packs, minAmount, maxAmount)
+	if ascending {
+		slices.Reverse(packs)
+	}
 	return c.Status(http.StatusOK).JSON(packs)
 }
 
+// filterPacks keeps the packs with amounts within the inclusive range, a zero bound is not set.
+// The packs are copies, so they are filtered in place.
+func filterPacks(packs []*models.Pack, minAmount, maxAmount int) []*models.Pack {
+	return slices.DeleteFunc(packs, func(pack *models.Pack) bool {
+		return (minAmount > 0 && pack.Amount < minAmount) || (maxAmount > 0 && pack.Amount > maxAmount)
+	})
+}
+
+// notModified checks the conditional request headers against the ETag set on the response and the modification time.
+// If-None-Match takes precedence over If-Modified-Since.
+func notModified(c *fiber.Ctx, modifiedAt time.Time) bool {
+	if c.Get(fiber.HeaderIfNoneMatch) != "" {
+		return c.Fresh()
+	}
+
+	since, err := http.ParseTime(c.Get(fiber.HeaderIfModifiedSince))
+	if err != nil {
+		return false
+	}
+	// Last-Modified has a resolution of one second
+	return !modifiedAt.Truncate(time.Second).After(since)
+}
+
+// GetPackHistory handles GET /packs/history
+// @Summary Get pack change history
+// @Description Get the log of pack additions, updates and deletions, oldest first
+// @Tags packs
+// @Produce json
+// @Success 200 {array} models.PackChange
+// @Router /packs/history [get]
+func (p *Packs) GetPackHistory(c *fiber.Ctx) error {
+	history := p.storage.GetPackHistory(c.UserContext())
+	return c.Status(http.StatusOK).JSON(history)
+}
+
+// GetPacksAtVersion handles GET /packs/at
+// @Summary Get the packs of a past version
+// @Description Get the packs as they were at the specified version of the pack set, as in the X-Pack-Set-Version header
+// @Description and the version of the pack history entries. Only the most recent versions are kept.
+// @Tags packs
+// @Produce json
+// @Param version query int true "Version of the pack set"
+// @Success 200 {array} models.Pack
+// @Failure 400 {object} map[string]string "Invalid version"
+// @Failure 404 {object} map[string]string "Pack set version not found"
+// @Router /packs/at [get]
+func (p *Packs) GetPacksAtVersion(c *fiber.Ctx) error {
+	version, ok, err := optionalNonNegativeQuery(c, "version", "version")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+	if !ok {
+		return invalidParam(c, &paramError{name: "version", reason: errParamRequired})
+	}
+
+	packs, err := p.storage.PacksAtVersion(c.UserContext(), version)
+	if errors.Is(err, storage.ErrVersionNotFound) {
+		return c.Status(http.StatusNotFound).JSON(map[string]string{"error": "Pack set version not found"})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(map[string]string{"error": err.Error()})
+	}
+
+	return c.Status(http.StatusOK).JSON(packs)
+}
+
+// RecommendPacks handles GET /packs/recommend
+// @Summary Recommend new pack sizes
+// @Description Suggest up to two new pack sizes which would most reduce the cumulative overpack of the recorded orders
+// @Tags packs
+// @Produce json
+// @Param count query int false "Number of suggestions, 1 by default, at most 2"
+// @Success 200 {object} models.PackRecommendation
+// @Failure 400 {object} map[string]string "Invalid count"
+// @Failure 404 {object} map[string]string "No packs available"
+// @Failure 503 {object} map[string]string "Recommendation timed out"
+// @Router /packs/recommend [get]
+func (p *Packs) RecommendPacks(c *fiber.Ctx) error {
+	count, err := optionalPositiveQuery(c, "count", "count")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+	if count == 0 {
+		count = 1
+	}
+
+	recommendation, err := p.storage.RecommendPacks(c.UserContext(), count)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrNoPacksAvailable):
+			return c.Status(http.StatusNotFound).JSON(map[string]string{"error": "No packs available"})
+		case errors.Is(err, storage.ErrComputationTimeout):
+			return c.Status(http.StatusServiceUnavailable).JSON(map[string]string{"error": "Recommendation timed out"})
+		default:
+			return c.Status(http.StatusInternalServerError).JSON(map[string]string{"error": "Failed to recommend packs"})
+		}
+	}
+
+	return c.Status(http.StatusOK).JSON(recommendation)
+}
+
+// FitPacks handles GET /packs/fit/{amount}
+// @Summary Check how the packs fit a number of items
+// @Description Tell whether some packing holds exactly the items, the largest pack holding at most the items and the
+// @Description smallest pack holding at least them, without computing or recording an order
+// @Tags packs
+// @Produce json
+// @Param amount path int true "Number of items"
+// @Success 200 {object} models.PackFit
+// @Failure 400 {object} map[string]string "Invalid amount"
+// @Failure 404 {object} map[string]string "No packs available"
+// @Failure 422 {object} map[string]string "The packs are too complex to check for an exact fit"
+// @Failure 503 {object} map[string]string "Exact fit check timed out"
+// @Router /packs/fit/{amount} [get]
+func (p *Packs) FitPacks(c *fiber.Ctx) error {
+	amount, err := positiveParam(c, "amount", "amount")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+
+	fit, err := p.storage.FitPacks(c.UserContext(), amount)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrNoPacksAvailable):
+			return c.Status(http.StatusNotFound).JSON(map[string]string{"error": "No packs available"})
+		case errors.Is(err, packing.ErrComputationComplexity):
+			return c.Status(http.StatusUnprocessableEntity).JSON(map[string]string{"error": "The packs are too complex to check for an exact fit"})
+		case errors.Is(err, storage.ErrComputationTimeout):
+			return c.Status(http.StatusServiceUnavailable).JSON(map[string]string{"error": "Exact fit check timed out"})
+		default:
+			return c.Status(http.StatusInternalServerError).JSON(map[string]string{"error": "Failed to fit packs"})
+		}
+	}
+
+	return c.Status(http.StatusOK).JSON(fit)
+}
+
+// GetPack handles GET /packs/{amount}
+// @Summary Get a pack
+// @Description Get the pack with the specified amount, can be used to check whether it exists
+// @Tags packs
+// @Produce json
+// @Param amount path int true "Pack amount"
+// @Success 200 {object} models.Pack
+// @Failure 400 {object} map[string]string "Invalid amount"
+// @Failure 404 {object} map[string]string "Pack not found"
+// @Router /packs/{amount} [get]
+func (p *Packs) GetPack(c *fiber.Ctx) error {
+	amount, err := positiveParam(c, "amount", "amount")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+
+	pack, err := p.storage.GetPack(c.UserContext(), amount)
+	if err != nil {
+		if errors.Is(err, storage.ErrPackNotFound) {
+			return c.Status(http.StatusNotFound).JSON(map[string]string{"error": "Pack not found"})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(map[string]string{"error": "Failed to get pack"})
+	}
+
+	return c.Status(http.StatusOK).JSON(pack)
+}
+
 // AddPack handles POST /packs/{amount}
 // @Summary Add a new pack
-// @Description Add a new pack with the specified amount
+// @Description Add a new pack with the specified amount and an optional label and return it.
+// @Description An existing pack keeps its label and is returned as it is.
 // @Tags packs
 // @Produce json
 // @Param amount path int true "Pack amount"
+// @Param label query string false "Human-readable name of the pack, at most 64 characters"
 // @Success 201 {object} models.Pack
-// @Failure 400 {object} map[string]string "Invalid amount"
-// @Failure 409 {object} map[string]string "Limit for packs reached"
+// @Failure 400 {object} map[string]string "Invalid amount or label, or an amount below the minimum pack amount or not a multiple of the pack multiple"
+// @Failure 409 {object} models.LimitErrorResponse "Limit for packs reached or pack with this label already exists"
 // @Router /packs/{amount} [post]
 func (p *Packs) AddPack(c *fiber.Ctx) error {
-	amount, err := c.ParamsInt("amount")
-	if err != nil || amount <= 0 {
-		return c.Status(http.StatusBadRequest).JSON(map[string]string{"error": "Invalid amount"})
+	amount, err := positiveParam(c, "amount", "amount")
+	if err != nil {
+		return invalidParam(c, err)
 	}
 
-	err = p.storage.AddPack(amount)
+	label, err := labelQuery(c)
 	if err != nil {
-		return c.Status(http.StatusConflict).JSON(map[string]string{"error": err.Error()})
+		return invalidParam(c, err)
 	}
 
-	return c.Status(http.StatusCreated).JSON(map[string]int{"amount": amount})
+	pack, err := p.storage.AddLabeledPack(c.UserContext(), amount, label)
+	if err != nil {
+		var limitErr *storage.LimitError
+		if errors.As(err, &limitErr) {
+			return packLimitReached(c, limitErr)
+		}
+		if errors.Is(err, storage.ErrLabelExists) {
+			return c.Status(http.StatusConflict).JSON(map[string]string{"error": "Pack with this label already exists"})
+		}
+		if pErr := amountParamError(err, "amount"); pErr != nil {
+			return invalidParam(c, pErr)
+		}
+		return c.Status(http.StatusInternalServerError).JSON(map[string]string{"error": "Failed to add pack"})
+	}
+
+	return c.Status(http.StatusCreated).JSON(pack)
+}
+
+// ApplyPreset handles POST /packs/preset/{name}
+// @Summary Apply a pack preset
+// @Description Replace the packs with the built-in preset with the specified name and return the new packs.
+// @Description The packs with amounts of the preset are kept with their labels and pins, the others are deleted.
+// @Description The preset is applied as a whole, nothing changes if any of its amounts is rejected.
+// @Tags packs
+// @Produce json
+// @Param name path string true "Preset name" Enums(default, metric, dozens)
+// @Success 200 {array} models.Pack
+// @Failure 400 {object} map[string]string "Preset amount below the minimum pack amount or not a multiple of the pack multiple"
+// @Failure 404 {object} map[string]string "Preset not found"
+// @Failure 409 {object} models.LimitErrorResponse "Preset exceeds the limit for packs or a pinned pack isn't part of it"
+// @Router /packs/preset/{name} [post]
+func (p *Packs) ApplyPreset(c *fiber.Ctx) error {
+	packs, err := p.storage.ApplyPreset(c.UserContext(), c.Params("name"))
+	if err != nil {
+		var limitErr *storage.LimitError
+		switch {
+		case errors.Is(err, storage.ErrPresetNotFound):
+			return c.Status(http.StatusNotFound).JSON(map[string]string{
+				"error": "Preset not found, must be one of " + strings.Join(storage.PresetNames(), ", "),
+			})
+		case errors.As(err, &limitErr):
+			return packLimitReached(c, limitErr)
+		case errors.Is(err, storage.ErrPackPinned):
+			return c.Status(http.StatusConflict).JSON(map[string]string{"error": "A pinned pack isn't part of the preset, unpin it first"})
+		}
+		if pErr := amountParamError(err, "preset"); pErr != nil {
+			return invalidParam(c, pErr)
+		}
+		return c.Status(http.StatusInternalServerError).JSON(map[string]string{"error": "Failed to apply preset"})
+	}
+
+	return c.Status(http.StatusOK).JSON(packs)
+}
+
+// ImportPacks handles POST /packs/import
+// @Summary Import packs
+// @Description Add the packs with the amounts from the request body and report the outcome for every amount in the same order:
+// @Description added, skipped-duplicate-in-request, skipped-already-exists, rejected-limit or rejected-invalid.
+// @Tags packs
+// @Accept json
+// @Produce json
+// @Param amounts body []int true "Pack amounts"
+// @Success 200 {array} models.PackImportResult
+// @Failure 400 {object} models.ValidationErrorResponse "Invalid request body"
+// @Router /packs/import [post]
+func (p *Packs) ImportPacks(c *fiber.Ctx) error {
+	var amounts []int
+	if errs := parseBody(c, &amounts); errs != nil {
+		return invalidBody(c, errs)
+	}
+
+	return c.Status(http.StatusOK).JSON(p.storage.AddPacks(c.UserContext(), amounts))
+}
+
+// ValidatePacks handles POST /packs/validate
+// @Summary Validate a pack configuration
+// @Description Check whether the pack amounts from the request body could replace the current packs and list all the problems:
+// @Description limit-exceeded, not-positive, duplicate, too-small or not-multiple. Nothing is changed.
+// @Tags packs
+// @Accept json
+// @Produce json
+// @Param amounts body []int true "Pack amounts"
+// @Success 200 {object} models.PackValidation
+// @Failure 400 {object} models.ValidationErrorResponse "Invalid request body"
+// @Router /packs/validate [post]
+func (p *Packs) ValidatePacks(c *fiber.Ctx) error {
+	var amounts []int
+	if errs := parseBody(c, &amounts); errs != nil {
+		return invalidBody(c, errs)
+	}
+
+	return c.Status(http.StatusOK).JSON(p.storage.ValidatePacks(c.UserContext(), amounts))
 }
 
 // UpdatePack handles PUT /packs/{oldAmount}/{newAmount}
 // @Summary Update a pack
-// @Description Update a pack's amount
+// @Description Update a pack's amount and return the updated pack.
+// @Description With upsert=true a missing pack is created with the new amount instead of 404, within the limit of packs,
+// @Description and if only a pack with the new amount exists it is returned unchanged, so the call can be repeated.
 // @Tags packs
 // @Produce json
 // @Param oldAmount path int true "Current pack amount"
 // @Param newAmount path int true "New pack amount"
+// @Param upsert query bool false "Create the pack if there is no pack with the old amount"
 // @Success 200 {object} models.Pack
-// @Failure 400 {object} map[string]string "Invalid amount"
+// @Success 201 {object} models.Pack "Created with upsert=true"
+// @Failure 400 {object} map[string]string "Invalid amount or upsert, or below the minimum pack amount or not a multiple of the pack multiple"
 // @Failure 404 {object} map[string]string "Pack not found"
-// @Failure 409 {object} map[string]string "Pack with new amount already exists"
+// @Failure 409 {object} models.LimitErrorResponse "Pack with new amount already exists or limit for packs reached"
 // @Router /packs/{oldAmount}/{newAmount} [put]
 func (p *Packs) UpdatePack(c *fiber.Ctx) error {
-	oldAmount, err := c.ParamsInt("oldAmount")
-	if err != nil || oldAmount <= 0 {
-		return c.Status(http.StatusBadRequest).JSON(map[string]string{"error": "Invalid old amount"})
+	oldAmount, err := positiveParam(c, "oldAmount", "old amount")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+	newAmount, err := positiveParam(c, "newAmount", "new amount")
+	if err != nil {
+		return invalidParam(c, err)
 	}
-	newAmount, err := c.ParamsInt("newAmount")
-	if err != nil || newAmount <= 0 {
-		return c.Status(http.StatusBadRequest).JSON(map[string]string{"error": "Invalid new amount"})
+	upsert, err := optionalBoolQuery(c, "upsert", "upsert")
+	if err != nil {
+		return invalidParam(c, err)
 	}
 
-	err = p.storage.UpdatePack(oldAmount, newAmount)
+	var pack *models.Pack
+	created := false
+	if upsert {
+		pack, created, err = p.storage.UpsertPack(c.UserContext(), oldAmount, newAmount)
+	} else {
+		pack, err = p.storage.UpdatePack(c.UserContext(), oldAmount, newAmount)
+	}
 	if err == nil {
-		return c.Status(http.StatusOK).JSON(map[string]int{"oldAmount": oldAmount, "amount": newAmount})
+		if created {
+			return c.Status(http.StatusCreated).JSON(pack)
+		}
+		return c.Status(http.StatusOK).JSON(pack)
+	}
+
+	if pErr := amountParamError(err, "new amount"); pErr != nil {
+		return invalidParam(c, pErr)
 	}
 
-	// if err != nil
+	var limitErr *storage.LimitError
 	switch {
+	case errors.As(err, &limitErr):
+		return packLimitReached(c, limitErr)
 	case errors.Is(err, storage.ErrPackNotFound):
 		return c.Status(http.StatusNotFound).JSON(map[string]string{"error": "Pack not found"})
 	case errors.Is(err, storage.ErrPackExists):
@@ -101,24 +454,229 @@ func (p *Packs) UpdatePack(c *fiber.Ctx) error {
 }
 
 // DeletePack handles DELETE /packs/{amount}
+// Deleting a pack that doesn't exist is not treated as idempotent success: it returns 404,
+// so clients with stale state find out that the pack set differs from what they expect.
 // @Summary Delete a pack
-// @Description Delete a pack with the specified amount
+// @Description Delete a pack with the specified amount. Returns 404 if there is no such pack.
+// @Description With safe=true the pack isn't deleted while a recorded order uses its amount.
 // @Tags packs
 // @Produce json
 // @Param amount path int true "Pack amount"
+// @Param safe query bool false "Refuse to delete the pack if a recorded order uses it"
 // @Success 204 "No Content"
-// @Failure 400 {object} map[string]string "Invalid amount"
+// @Failure 400 {object} map[string]string "Invalid amount or safe"
+// @Failure 404 {object} map[string]string "Pack not found"
+// @Failure 409 {object} map[string]string "Pack is pinned or, with safe=true, used by recorded orders"
 // @Router /packs/{amount} [delete]
 func (p *Packs) DeletePack(c *fiber.Ctx) error {
-	amount, err := c.ParamsInt("amount")
-	if err != nil || amount <= 0 {
-		return c.Status(http.StatusBadRequest).JSON(map[string]string{"error": "Invalid amount"})
+	amount, err := positiveParam(c, "amount", "amount")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+	safe, err := optionalBoolQuery(c, "safe", "safe")
+	if err != nil {
+		return invalidParam(c, err)
 	}
 
-	err = p.storage.DeletePack(amount)
+	if safe {
+		err = p.storage.DeleteUnusedPack(c.UserContext(), amount)
+	} else {
+		err = p.storage.DeletePack(c.UserContext(), amount)
+	}
 	if err != nil {
-		return err
+		return deletePackError(c, err)
 	}
 
 	return c.SendStatus(http.StatusNoContent)
 }
+
+// PinPack handles POST /packs/{amount}/pin
+// @Summary Pin a pack
+// @Description Pin the pack with the specified amount, pinned packs can't be deleted until they are unpinned
+// @Tags packs
+// @Produce json
+// @Param amount path int true "Pack amount"
+// @Success 200 {object} models.Pack
+// @Failure 400 {object} map[string]string "Invalid amount"
+// @Failure 404 {object} map[string]string "Pack not found"
+// @Router /packs/{amount}/pin [post]
+func (p *Packs) PinPack(c *fiber.Ctx) error {
+	return p.setPinned(c, true)
+}
+
+// UnpinPack handles DELETE /packs/{amount}/pin
+// @Summary Unpin a pack
+// @Description Unpin the pack with the specified amount so it can be deleted again
+// @Tags packs
+// @Produce json
+// @Param amount path int true "Pack amount"
+// @Success 200 {object} models.Pack
+// @Failure 400 {object} map[string]string "Invalid amount"
+// @Failure 404 {object} map[string]string "Pack not found"
+// @Router /packs/{amount}/pin [delete]
+func (p *Packs) UnpinPack(c *fiber.Ctx) error {
+	return p.setPinned(c, false)
+}
+
+func (p *Packs) setPinned(c *fiber.Ctx, pinned bool) error {
+	amount, err := positiveParam(c, "amount", "amount")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+
+	pack, err := p.storage.SetPackPinned(c.UserContext(), amount, pinned)
+	if err != nil {
+		if errors.Is(err, storage.ErrPackNotFound) {
+			return c.Status(http.StatusNotFound).JSON(map[string]string{"error": "Pack not found"})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(map[string]string{"error": "Failed to update pack"})
+	}
+
+	return c.Status(http.StatusOK).JSON(pack)
+}
+
+// LabelPack handles PUT /packs/{amount}/label
+// @Summary Label a pack
+// @Description Set the human-readable label of the pack with the specified amount, an empty or missing label removes it.
+// @Description Labels are unique ignoring case and whitespace.
+// @Tags packs
+// @Produce json
+// @Param amount path int true "Pack amount"
+// @Param label query string false "Human-readable name of the pack, at most 64 characters"
+// @Success 200 {object} models.Pack
+// @Failure 400 {object} map[string]string "Invalid amount or label"
+// @Failure 404 {object} map[string]string "Pack not found"
+// @Failure 409 {object} map[string]string "Pack with this label already exists"
+// @Router /packs/{amount}/label [put]
+func (p *Packs) LabelPack(c *fiber.Ctx) error {
+	amount, err := positiveParam(c, "amount", "amount")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+	label, err := labelQuery(c)
+	if err != nil {
+		return invalidParam(c, err)
+	}
+
+	pack, err := p.storage.SetPackLabel(c.UserContext(), amount, label)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrPackNotFound):
+			return c.Status(http.StatusNotFound).JSON(map[string]string{"error": "Pack not found"})
+		case errors.Is(err, storage.ErrLabelExists):
+			return c.Status(http.StatusConflict).JSON(map[string]string{"error": "Pack with this label already exists"})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(map[string]string{"error": "Failed to update pack"})
+	}
+
+	return c.Status(http.StatusOK).JSON(pack)
+}
+
+// labelQuery returns the optional pack label from the query
+func labelQuery(c *fiber.Ctx) (string, error) {
+	label := c.Query("label")
+	if len(label) > maxLabelLength {
+		return "", &paramError{name: "label", reason: errLabelTooLong}
+	}
+	return label, nil
+}
+
+// UpdatePackByID handles PUT /packs/id/{id}/{newAmount}
+// @Summary Update a pack by ID
+// @Description Update the amount of the pack with the specified ID and return the updated pack
+// @Tags packs
+// @Produce json
+// @Param id path int true "Pack ID"
+// @Param newAmount path int true "New pack amount"
+// @Success 200 {object} models.Pack
+// @Failure 400 {object} map[string]string "Invalid ID or amount, or an amount below the minimum pack amount or not a multiple of the pack multiple"
+// @Failure 404 {object} map[string]string "Pack not found"
+// @Failure 409 {object} map[string]string "Pack with new amount already exists"
+// @Router /packs/id/{id}/{newAmount} [put]
+func (p *Packs) UpdatePackByID(c *fiber.Ctx) error {
+	id, err := positiveParam(c, "id", "ID")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+	newAmount, err := positiveParam(c, "newAmount", "new amount")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+
+	pack, err := p.storage.UpdatePackByID(c.UserContext(), id, newAmount)
+	if pErr := amountParamError(err, "new amount"); pErr != nil {
+		return invalidParam(c, pErr)
+	}
+	switch {
+	case err == nil:
+		return c.Status(http.StatusOK).JSON(pack)
+	case errors.Is(err, storage.ErrPackNotFound):
+		return c.Status(http.StatusNotFound).JSON(map[string]string{"error": "Pack not found"})
+	case errors.Is(err, storage.ErrPackExists):
+		return c.Status(http.StatusConflict).JSON(map[string]string{"error": "Pack with new amount already exists"})
+	default:
+		return c.Status(http.StatusInternalServerError).JSON(map[string]string{"error": "Failed to update pack"})
+	}
+}
+
+// DeletePackByID handles DELETE /packs/id/{id}
+// @Summary Delete a pack by ID
+// @Description Delete the pack with the specified ID
+// @Tags packs
+// @Produce json
+// @Param id path int true "Pack ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]string "Invalid ID"
+// @Failure 404 {object} map[string]string "Pack not found"
+// @Failure 409 {object} map[string]string "Pack is pinned"
+// @Router /packs/id/{id} [delete]
+func (p *Packs) DeletePackByID(c *fiber.Ctx) error {
+	id, err := positiveParam(c, "id", "ID")
+	if err != nil {
+		return invalidParam(c, err)
+	}
+
+	err = p.storage.DeletePackByID(c.UserContext(), id)
+	if err != nil {
+		return deletePackError(c, err)
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// packLimitReached responds with 409 and the count and the limit of packs
+func packLimitReached(c *fiber.Ctx, limitErr *storage.LimitError) error {
+	return c.Status(http.StatusConflict).JSON(models.LimitErrorResponse{
+		Error: fmt.Sprintf("Limit for packs reached: %d/%d pack sizes used", limitErr.Count, limitErr.Limit),
+		Count: limitErr.Count,
+		Limit: limitErr.Limit,
+	})
+}
+
+// amountParamError returns the parameter error for an amount below the minimum pack amount
+// or not a multiple of the pack multiple, nil for other errors
+func amountParamError(err error, name string) error {
+	var minErr *storage.MinAmountError
+	if errors.As(err, &minErr) {
+		return &paramError{name: name, reason: fmt.Errorf("must be at least %d", minErr.Min)}
+	}
+	var multipleErr *storage.MultipleError
+	if errors.As(err, &multipleErr) {
+		return &paramError{name: name, reason: fmt.Errorf("must be a multiple of %d", multipleErr.Multiple)}
+	}
+	return nil
+}
+
+// deletePackError maps the errors of the pack deletion to the response
+func deletePackError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, storage.ErrPackNotFound):
+		return c.Status(http.StatusNotFound).JSON(map[string]string{"error": "Pack not found"})
+	case errors.Is(err, storage.ErrPackPinned):
+		return c.Status(http.StatusConflict).JSON(map[string]string{"error": "Pack is pinned, unpin it first"})
+	case errors.Is(err, storage.ErrPackInUse):
+		return c.Status(http.StatusConflict).JSON(map[string]string{"error": "Pack is used by recorded orders"})
+	default:
+		return c.Status(http.StatusInternalServerError).JSON(map[string]string{"error": "Failed to delete pack"})
+	}
+}
@@ -0,0 +1,561 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeletePack(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 250)
+	app := newTestApp(packStorage)
+
+	// Present pack is deleted
+	resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/packs/250", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Empty(t, packStorage.GetPacks(t.Context()))
+
+	// Absent pack is reported as not found
+	resp, err = app.Test(httptest.NewRequest(http.MethodDelete, "/packs/250", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "Pack not found", errorMessage(t, resp))
+}
+
+func TestDeletePackSafe(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 250)
+	_ = packStorage.AddPack(t.Context(), 500)
+	_, _ = packStorage.CalculateOrder(t.Context(), 250)
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/packs/250?safe=true", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, "Pack is used by recorded orders", errorMessage(t, resp))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodDelete, "/packs/250?safe=maybe", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodDelete, "/packs/500?safe=true", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	// Without safe the pack is deleted although it's used
+	resp, err = app.Test(httptest.NewRequest(http.MethodDelete, "/packs/250", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Empty(t, packStorage.GetPacks(t.Context()))
+}
+
+func TestAddPack(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 250)
+	app := newTestApp(packStorage)
+
+	// The created pack is returned in the same shape GetPacks lists it
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/packs/500?label=box", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created models.Pack
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	assert.Equal(t, models.Pack{ID: 2, Amount: 500, Label: "box"}, created)
+
+	// An existing amount returns the existing pack
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/packs/250", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	var existing models.Pack
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&existing))
+	assert.Equal(t, models.Pack{ID: 1, Amount: 250}, existing)
+}
+
+func TestUpdatePack(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_, _ = packStorage.AddLabeledPack(t.Context(), 100, "box")
+	_, _ = packStorage.SetPackPinned(t.Context(), 100, true)
+	app := newTestApp(packStorage)
+
+	// The updated pack is returned with everything it keeps across the update
+	resp, err := app.Test(httptest.NewRequest(http.MethodPut, "/packs/100/150", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var updated models.Pack
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&updated))
+	assert.Equal(t, models.Pack{ID: 1, Amount: 150, Pinned: true, Label: "box"}, updated)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPut, "/packs/id/1/200", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&updated))
+	assert.Equal(t, models.Pack{ID: 1, Amount: 200, Pinned: true, Label: "box"}, updated)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPut, "/packs/100/250", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestUpsertPack(t *testing.T) {
+	packStorage := storage.NewPackStorage(storage.WithMaxPacks(2))
+	_ = packStorage.AddPack(t.Context(), 100)
+	app := newTestApp(packStorage)
+
+	// Without upsert a missing pack is not found
+	resp, err := app.Test(httptest.NewRequest(http.MethodPut, "/packs/200/250", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// With upsert it's created, then the same call returns it unchanged
+	for _, status := range []int{http.StatusCreated, http.StatusOK} {
+		resp, err = app.Test(httptest.NewRequest(http.MethodPut, "/packs/200/250?upsert=true", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, status, resp.StatusCode)
+		var pack models.Pack
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&pack))
+		assert.Equal(t, models.Pack{ID: 2, Amount: 250}, pack)
+	}
+
+	// An existing pack is updated
+	resp, err = app.Test(httptest.NewRequest(http.MethodPut, "/packs/100/150?upsert=true", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var updated models.Pack
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&updated))
+	assert.Equal(t, models.Pack{ID: 1, Amount: 150}, updated)
+
+	// Creating respects the limit of packs
+	resp, err = app.Test(httptest.NewRequest(http.MethodPut, "/packs/300/350?upsert=true", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	var body models.LimitErrorResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, models.LimitErrorResponse{
+		Error: "Limit for packs reached: 2/2 pack sizes used",
+		Count: 2,
+		Limit: 2,
+	}, body)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPut, "/packs/100/150?upsert=yes", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid upsert: must be true or false", errorMessage(t, resp))
+}
+
+func TestAddPackLimit(t *testing.T) {
+	packStorage := storage.NewPackStorage(storage.WithMaxPacks(1))
+	_ = packStorage.AddPack(t.Context(), 250)
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/packs/500", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	var body models.LimitErrorResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, models.LimitErrorResponse{
+		Error: "Limit for packs reached: 1/1 pack sizes used",
+		Count: 1,
+		Limit: 1,
+	}, body)
+}
+
+func TestPackMultiple(t *testing.T) {
+	packStorage := storage.NewPackStorage(storage.WithPackMultiple(50))
+	_ = packStorage.AddPack(t.Context(), 250)
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/packs/300", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/packs/310", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid amount: must be a multiple of 50", errorMessage(t, resp))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPut, "/packs/250/260", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid new amount: must be a multiple of 50", errorMessage(t, resp))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPut, "/packs/id/1/260", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid new amount: must be a multiple of 50", errorMessage(t, resp))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPut, "/packs/250/200", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestMinPackAmount(t *testing.T) {
+	app := newTestApp(storage.NewPackStorage(storage.WithMinPackAmount(100)))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/packs/100", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/packs/99", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid amount: must be at least 100", errorMessage(t, resp))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPut, "/packs/100/99", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid new amount: must be at least 100", errorMessage(t, resp))
+}
+
+func TestPinPack(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 250)
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/packs/250/pin", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var pack models.Pack
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&pack))
+	assert.True(t, pack.Pinned)
+
+	// Pinned packs can't be deleted
+	resp, err = app.Test(httptest.NewRequest(http.MethodDelete, "/packs/250", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, "Pack is pinned, unpin it first", errorMessage(t, resp))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodDelete, "/packs/id/1", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodDelete, "/packs/250/pin", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodDelete, "/packs/250", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/packs/250/pin", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestLabelPack(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/packs/5000?label=bulk%20pallet", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	pack, err := packStorage.GetPack(t.Context(), 5000)
+	assert.NoError(t, err)
+	assert.Equal(t, "bulk pallet", pack.Label)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPut, "/packs/5000/label?label=pallet", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var labeled models.Pack
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&labeled))
+	assert.Equal(t, models.Pack{ID: 1, Amount: 5000, Label: "pallet"}, labeled)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPut, "/packs/5000/label?label="+strings.Repeat("x", 65), nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid label: must be at most 64 characters", errorMessage(t, resp))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPut, "/packs/250/label?label=box", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// Labels are unique ignoring case and whitespace
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/packs/250?label=%20PALLET", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, "Pack with this label already exists", errorMessage(t, resp))
+	_ = packStorage.AddPack(t.Context(), 250)
+	resp, err = app.Test(httptest.NewRequest(http.MethodPut, "/packs/250/label?label=Pallet", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	// Amounts are still updated through the same prefix
+	resp, err = app.Test(httptest.NewRequest(http.MethodPut, "/packs/5000/4000", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestGetPack(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 250)
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/packs/250", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/packs/500", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// Static routes still take precedence
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/packs/history", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestGetPacksAtVersion(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 250)
+	_ = packStorage.AddPack(t.Context(), 500)
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/packs/at?version=1", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var packs []models.Pack
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&packs))
+	assert.Equal(t, []models.Pack{{ID: 1, Amount: 250}}, packs)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/packs/at?version=3", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "Pack set version not found", errorMessage(t, resp))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/packs/at", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid version: is required", errorMessage(t, resp))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/packs/at?version=-1", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid version: must not be negative", errorMessage(t, resp))
+}
+
+func TestFitPacks(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/packs/fit/300", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "No packs available", errorMessage(t, resp))
+
+	_ = packStorage.AddPack(t.Context(), 250)
+	_ = packStorage.AddPack(t.Context(), 500)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/packs/fit/300", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var fit models.PackFit
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&fit))
+	assert.Equal(t, models.PackFit{
+		RequestedItems:   300,
+		LargestFitting:   &models.Pack{ID: 1, Amount: 250},
+		SmallestCovering: &models.Pack{ID: 2, Amount: 500},
+	}, fit)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/packs/fit/abc", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid amount: must be an integer", errorMessage(t, resp))
+}
+
+func TestGetPacksRange(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	for _, amount := range []int{250, 500, 1000, 2000, 5000} {
+		_ = packStorage.AddPack(t.Context(), amount)
+	}
+	app := newTestApp(packStorage)
+
+	amounts := func(target string) []int {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, target, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var packs []models.Pack
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&packs))
+		amounts := []int{}
+		for _, pack := range packs {
+			amounts = append(amounts, pack.Amount)
+		}
+		return amounts
+	}
+	assert.Equal(t, []int{5000, 2000, 1000, 500, 250}, amounts("/packs"))
+	assert.Equal(t, []int{2000, 1000, 500}, amounts("/packs?min=500&max=2000"))
+	assert.Equal(t, []int{5000, 2000, 1000}, amounts("/packs?min=1000"))
+	assert.Equal(t, []int{250, 500}, amounts("/packs?max=999&sort=asc"))
+	assert.Equal(t, []int{1000}, amounts("/packs?min=1000&max=1000&sort=desc"))
+	assert.Equal(t, []int{}, amounts("/packs?min=300&max=400"))
+
+	tests := []struct {
+		target  string
+		message string
+	}{
+		{"/packs?min=2000&max=500", "Invalid amount range: min must not exceed max"},
+		{"/packs?min=0", "Invalid min: must be positive"},
+		{"/packs?max=x", "Invalid max: must be an integer"},
+		{"/packs?sort=up", "Invalid sort: must be asc or desc"},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.target, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, tt.target)
+		assert.Equal(t, tt.message, errorMessage(t, resp), tt.target)
+	}
+}
+
+func TestGetPacksETag(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 250)
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/packs", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	etag := resp.Header.Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	// Unchanged pack set
+	req := httptest.NewRequest(http.MethodGet, "/packs", nil)
+	req.Header.Set("If-None-Match", etag)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	// Changed pack set
+	_ = packStorage.AddPack(t.Context(), 500)
+	req = httptest.NewRequest(http.MethodGet, "/packs", nil)
+	req.Header.Set("If-None-Match", etag)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEqual(t, etag, resp.Header.Get("ETag"))
+}
+
+func TestGetPacksLastModified(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 250)
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/packs", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "1", resp.Header.Get(PackSetVersionHeader))
+	lastModified := resp.Header.Get("Last-Modified")
+	assert.NotEmpty(t, lastModified)
+
+	req := httptest.NewRequest(http.MethodGet, "/packs", nil)
+	req.Header.Set("If-Modified-Since", lastModified)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	// Modified before the last change
+	req = httptest.NewRequest(http.MethodGet, "/packs", nil)
+	req.Header.Set("If-Modified-Since", "Mon, 02 Jan 2006 15:04:05 GMT")
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_ = packStorage.AddPack(t.Context(), 500)
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/packs", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "2", resp.Header.Get(PackSetVersionHeader))
+}
+
+func TestImportPacks(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 250)
+	app := newTestApp(packStorage)
+
+	req := httptest.NewRequest(http.MethodPost, "/packs/import", strings.NewReader(`[500, 250, 500, 0]`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var results []models.PackImportResult
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&results))
+	statuses := make([]string, len(results))
+	for i, result := range results {
+		statuses[i] = result.Status
+	}
+	assert.Equal(t, []string{models.ImportAdded, models.ImportAlreadyExists, models.ImportDuplicateInRequest, models.ImportRejectedInvalid}, statuses)
+
+	req = httptest.NewRequest(http.MethodPost, "/packs/import", strings.NewReader(`{"amounts": [500]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestApplyPreset(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 300)
+	app := newTestApp(packStorage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/packs/preset/metric", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var packs []models.Pack
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&packs))
+	assert.Len(t, packs, 6)
+	assert.Equal(t, 5000, packs[0].Amount)
+	assert.Equal(t, 100, packs[5].Amount)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/packs/preset/imperial", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "Preset not found, must be one of default, dozens, metric", errorMessage(t, resp))
+
+	_, _ = packStorage.SetPackPinned(t.Context(), 100, true)
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/packs/preset/default", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Len(t, packStorage.GetPacks(t.Context()), 6)
+}
+
+func TestApplyPresetRejected(t *testing.T) {
+	app := newTestApp(storage.NewPackStorage(storage.WithPackMultiple(250)))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/packs/preset/metric", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid preset: must be a multiple of 250", errorMessage(t, resp))
+
+	app = newTestApp(storage.NewPackStorage(storage.WithMaxPacks(5)))
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/packs/preset/metric", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+func TestValidatePacks(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 250)
+	app := newTestApp(packStorage)
+
+	req := httptest.NewRequest(http.MethodPost, "/packs/validate", strings.NewReader(`[500, 500, 0]`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var validation models.PackValidation
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&validation))
+	assert.False(t, validation.Valid)
+	assert.Len(t, validation.Violations, 2)
+	assert.Equal(t, models.ViolationDuplicate, validation.Violations[0].Reason)
+	assert.Equal(t, models.ViolationNotPositive, validation.Violations[1].Reason)
+
+	// The packs are untouched
+	assert.Len(t, packStorage.GetPacks(t.Context()), 1)
+}
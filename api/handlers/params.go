@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	errParamNotInteger  = errors.New("must be an integer")
+	errParamOutOfRange  = errors.New("is out of range")
+	errParamNotPositive = errors.New("must be positive")
+	errParamNegative    = errors.New("must not be negative")
+	errParamZero        = errors.New("must not be zero")
+	errParamRequired    = errors.New("is required")
+	errParamNotLimit    = errors.New("must be a number of items or a percentage like 10%")
+	errParamRange       = errors.New("min must not exceed max")
+	errParamNotBool     = errors.New("must be true or false")
+	errParamNotStrategy = errors.New("must be greedy, min-overpack, fewest-sizes, distinct-packs or max-under")
+	errParamNotSort     = errors.New("must be asc or desc")
+	errParamGreedyOnly  = errors.New("greedy strategy only")
+)
+
+// maxParamValue bounds the numbers of items and the pack amounts of the parameters, so an amount plus a pack amount,
+// the most an order can overpack, can't overflow an int
+const maxParamValue = math.MaxInt / 2
+
+// paramError describes an invalid path or query parameter
+type paramError struct {
+	name   string
+	reason error
+}
+
+func (e *paramError) Error() string {
+	return "invalid " + e.name + ": " + e.reason.Error()
+}
+
+func (e *paramError) Unwrap() error {
+	return e.reason
+}
+
+// positiveParam parses a path parameter as a positive int.
+// Unlike c.ParamsInt it rejects values that don't fit into an int explicitly instead of wrapping them.
+func positiveParam(c *fiber.Ctx, key, name string) (int, error) {
+	return parsePositive(c.Params(key), name)
+}
+
+// optionalPositiveQuery parses an optional query parameter as a positive int.
+// It returns 0 if the parameter is not set.
+func optionalPositiveQuery(c *fiber.Ctx, key, name string) (int, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return 0, nil
+	}
+
+	return parsePositive(raw, name)
+}
+
+// optionalNonNegativeQuery parses an optional query parameter as a non-negative int.
+// It also tells whether the parameter is set, as 0 is a valid value.
+func optionalNonNegativeQuery(c *fiber.Ctx, key, name string) (int, bool, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return 0, false, nil
+	}
+
+	value, err := strconv.ParseInt(raw, 10, strconv.IntSize)
+	switch {
+	case errors.Is(err, strconv.ErrRange):
+		return 0, false, &paramError{name: name, reason: errParamOutOfRange}
+	case err != nil:
+		return 0, false, &paramError{name: name, reason: errParamNotInteger}
+	case value < 0:
+		return 0, false, &paramError{name: name, reason: errParamNegative}
+	}
+
+	return int(value), true, nil
+}
+
+// optionalBoolQuery parses an optional boolean query parameter. It returns false if the parameter is not set.
+func optionalBoolQuery(c *fiber.Ctx, key, name string) (bool, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return false, nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, &paramError{name: name, reason: errParamNotBool}
+	}
+
+	return value, nil
+}
+
+// overpackLimit is a parsed max overpack limit, either absolute or relative to the requested items
+type overpackLimit struct {
+	items   int
+	percent float64
+	// isPercent tells whether percent is set instead of items
+	isPercent bool
+}
+
+// optionalOverpackQuery parses an optional max overpack query parameter
+// which is either a number of items ("100") or a percentage ("10%").
+func optionalOverpackQuery(c *fiber.Ctx, key, name string) (*overpackLimit, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+
+	if percent, ok := strings.CutSuffix(raw, "%"); ok {
+		value, err := strconv.ParseFloat(percent, 64)
+		switch {
+		case err != nil:
+			return nil, &paramError{name: name, reason: errParamNotLimit}
+		case value < 0:
+			return nil, &paramError{name: name, reason: errParamNegative}
+		}
+		return &overpackLimit{percent: value, isPercent: true}, nil
+	}
+
+	value, err := strconv.ParseInt(raw, 10, strconv.IntSize)
+	switch {
+	case errors.Is(err, strconv.ErrRange):
+		return nil, &paramError{name: name, reason: errParamOutOfRange}
+	case err != nil:
+		return nil, &paramError{name: name, reason: errParamNotLimit}
+	case value < 0:
+		return nil, &paramError{name: name, reason: errParamNegative}
+	}
+
+	return &overpackLimit{items: int(value)}, nil
+}
+
+// returnParam parses a path parameter as a returned quantity, either negative like a ledger entry or positive.
+// It returns the number of returned items.
+func returnParam(c *fiber.Ctx, key, name string) (int, error) {
+	value, err := strconv.ParseInt(c.Params(key), 10, strconv.IntSize)
+	switch {
+	case errors.Is(err, strconv.ErrRange) || value < -maxParamValue || value > maxParamValue:
+		return 0, &paramError{name: name, reason: errParamOutOfRange}
+	case err != nil:
+		return 0, &paramError{name: name, reason: errParamNotInteger}
+	case value == 0:
+		return 0, &paramError{name: name, reason: errParamZero}
+	case value < 0:
+		return int(-value), nil
+	}
+
+	return int(value), nil
+}
+
+// parsePositive parses a positive int up to maxParamValue
+func parsePositive(raw, name string) (int, error) {
+	value, err := strconv.ParseInt(raw, 10, strconv.IntSize)
+	switch {
+	case errors.Is(err, strconv.ErrRange) || value > maxParamValue:
+		return 0, &paramError{name: name, reason: errParamOutOfRange}
+	case err != nil:
+		return 0, &paramError{name: name, reason: errParamNotInteger}
+	case value <= 0:
+		return 0, &paramError{name: name, reason: errParamNotPositive}
+	}
+
+	return int(value), nil
+}
+
+// invalidParam responds with a uniform 400 for a parameter parsing error
+func invalidParam(c *fiber.Ctx, err error) error {
+	message := "Invalid parameter"
+	var pErr *paramError
+	if errors.As(err, &pErr) {
+		message = "Invalid " + pErr.name + ": " + pErr.reason.Error()
+	}
+
+	return c.Status(http.StatusBadRequest).JSON(map[string]string{"error": message})
+}
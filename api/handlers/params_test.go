@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/internal/storage"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestApp(storage *storage.PackStorage) *fiber.App {
+	app := fiber.New()
+	NewOrders(storage).RegisterRoutes(app)
+	NewPacks(storage).RegisterRoutes(app)
+	NewStats(storage).RegisterRoutes(app)
+	return app
+}
+
+func errorMessage(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	var body map[string]string
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return body["error"]
+}
+
+func TestPositiveParam(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 100)
+	app := newTestApp(packStorage)
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		message string
+	}{
+		{"overflow amount", http.MethodPost, "/orders/items/99999999999999999999999", "Invalid amount: is out of range"},
+		{"max int amount", http.MethodPost, "/orders/items/9223372036854775807", "Invalid amount: is out of range"},
+		{"max int min-overpack", http.MethodPost, "/orders/items/9223372036854775807?strategy=min-overpack", "Invalid amount: is out of range"},
+		{"max int candidates", http.MethodGet, "/orders/candidates/9223372036854775807", "Invalid amount: is out of range"},
+		{"max int return", http.MethodPost, "/orders/return/-9223372036854775807", "Invalid amount: is out of range"},
+		{"max int pack", http.MethodPost, "/packs/9223372036854775807", "Invalid amount: is out of range"},
+		{"negative amount", http.MethodPost, "/orders/items/-5", "Invalid amount: must be positive"},
+		{"zero amount", http.MethodPost, "/orders/items/0", "Invalid amount: must be positive"},
+		{"not a number", http.MethodPost, "/orders/items/abc", "Invalid amount: must be an integer"},
+		{"overflow pack", http.MethodPost, "/packs/99999999999999999999999", "Invalid amount: is out of range"},
+		{"negative pack", http.MethodDelete, "/packs/-1", "Invalid amount: must be positive"},
+		{"overflow old amount", http.MethodPut, "/packs/99999999999999999999999/100", "Invalid old amount: is out of range"},
+		{"negative new amount", http.MethodPut, "/packs/100/-100", "Invalid new amount: must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(tt.method, tt.path, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			assert.Equal(t, tt.message, errorMessage(t, resp))
+		})
+	}
+
+	// Valid value still works
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/orders/items/100", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestPositiveParamBound(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 100)
+	app := newTestApp(packStorage)
+
+	// The largest amount is packed without overflowing
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/orders/items/"+strconv.Itoa(maxParamValue), nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var order models.Order
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&order))
+	assert.Equal(t, maxParamValue+97, order.TotalItems)
+	assert.Equal(t, 97, order.OverpackedItems)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/orders/items/"+strconv.Itoa(maxParamValue+1), nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "Invalid amount: is out of range", errorMessage(t, resp))
+}
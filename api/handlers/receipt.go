@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+)
+
+// receiptTemplate renders an order as a standalone HTML page, the labels and tags are escaped by html/template
+var receiptTemplate = template.Must(template.New("receipt").Funcs(template.FuncMap{
+	"packName": packName,
+	"date":     receiptDate,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Receipt for order #{{.ID}}</title>
+</head>
+<body>
+<h1>Receipt for order #{{.ID}}</h1>
+<p>Created: {{date .CreatedAt}}</p>
+{{- if .Reference}}
+<p>Reference: {{.Reference}}</p>
+{{- end}}
+<table>
+<thead><tr><th>Pack</th><th>Quantity</th><th>Subtotal</th></tr></thead>
+<tbody>
+{{- range .Packs}}
+<tr><td>{{packName .Pack}}</td><td>{{.Quantity}}</td><td>{{.Subtotal}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+<p>Requested items: {{.RequestedItems}}</p>
+<p>Total items: {{.TotalItems}}</p>
+{{- if .UnfulfilledItems}}
+<p>Unfulfilled items: {{.UnfulfilledItems}}</p>
+{{- end}}
+<p>Overpacked items: {{.OverpackedItems}} ({{.OverpackPercent}}%)</p>
+</body>
+</html>
+`))
+
+// writeHTMLReceipt renders the order as an HTML receipt
+func writeHTMLReceipt(w io.Writer, order models.Order) error {
+	return receiptTemplate.Execute(w, order)
+}
+
+// writeTextReceipt renders the order as a plain text receipt with the packs in aligned columns
+func writeTextReceipt(w io.Writer, order models.Order) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Receipt for order #%d\n", order.ID)
+	fmt.Fprintf(tw, "Created: %s\n", receiptDate(order.CreatedAt))
+	if order.Reference != "" {
+		fmt.Fprintf(tw, "Reference: %s\n", order.Reference)
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "Pack\tQuantity\tSubtotal")
+	for _, pack := range order.Packs {
+		fmt.Fprintf(tw, "%s\t%d\t%d\n", packName(pack.Pack), pack.Quantity, pack.Subtotal)
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "Requested items: %d\n", order.RequestedItems)
+	fmt.Fprintf(tw, "Total items: %d\n", order.TotalItems)
+	if order.UnfulfilledItems > 0 {
+		fmt.Fprintf(tw, "Unfulfilled items: %d\n", order.UnfulfilledItems)
+	}
+	fmt.Fprintf(tw, "Overpacked items: %d (%g%%)\n", order.OverpackedItems, order.OverpackPercent)
+	return tw.Flush()
+}
+
+// packName names a pack on a receipt by its amount and its label if it has one
+func packName(pack *models.Pack) string {
+	if pack.Label == "" {
+		return fmt.Sprintf("%d", pack.Amount)
+	}
+	return fmt.Sprintf("%d (%s)", pack.Amount, pack.Label)
+}
+
+func receiptDate(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
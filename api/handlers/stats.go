@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/corel-frim/item-packer-inc/internal/storage"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Stats struct {
+	storage storage.Store
+}
+
+func NewStats(storage storage.Store) *Stats {
+	return &Stats{
+		storage: storage,
+	}
+}
+
+func (s *Stats) RegisterRoutes(app *fiber.App) {
+	group := app.Group("/stats")
+	group.Get("/summary", s.GetSummary)
+}
+
+// GetSummary handles GET /stats/summary
+// @Summary Get the order totals
+// @Description Get the number of orders and the total requested, shipped and overpacked items of the recorded orders
+// @Tags stats
+// @Produce json
+// @Success 200 {object} models.OrderSummary
+// @Router /stats/summary [get]
+func (s *Stats) GetSummary(c *fiber.Ctx) error {
+	return c.Status(http.StatusOK).JSON(s.storage.OrderSummary(c.UserContext()))
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSummary(t *testing.T) {
+	packStorage := storage.NewPackStorage()
+	_ = packStorage.AddPack(t.Context(), 250)
+	app := newTestApp(packStorage)
+
+	_, _ = packStorage.CalculateOrder(t.Context(), 200)
+	_, _ = packStorage.CalculateOrder(t.Context(), 300)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/stats/summary", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var summary models.OrderSummary
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&summary))
+	assert.Equal(t, models.OrderSummary{
+		Orders:                 2,
+		RequestedItems:         500,
+		ShippedItems:           750,
+		OverpackedItems:        250,
+		AverageOverpackPercent: 45.84,
+	}, summary)
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+)
+
+const (
+	defaultNetwork = "tcp"
+	defaultAddress = ":8080"
+)
+
+var errUnknownNetwork = errors.New("unknown LISTEN_NETWORK, must be tcp, tcp4, tcp6 or unix")
+
+// listenConfig maps the LISTEN_NETWORK and LISTEN_ADDRESS values to the network and the address to listen on.
+// TCP listens on :8080 by default, a Unix socket needs the path of the socket file.
+func listenConfig(network, address string) (string, string, error) {
+	if network == "" {
+		network = defaultNetwork
+	}
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+		if address == "" {
+			address = defaultAddress
+		}
+	case "unix":
+		if address == "" {
+			return "", "", errors.New("LISTEN_ADDRESS must be the path of the socket file for LISTEN_NETWORK=unix")
+		}
+	default:
+		return "", "", errUnknownNetwork
+	}
+	return network, address, nil
+}
+
+// listen opens the listener on the network and the address.
+// A socket file left behind by a server that didn't shut down cleanly is removed first, other files are kept.
+func listen(network, address string) (net.Listener, error) {
+	if network == "unix" {
+		if info, err := os.Stat(address); err == nil && info.Mode()&os.ModeSocket != 0 {
+			if err := os.Remove(address); err != nil {
+				return nil, fmt.Errorf("remove stale socket %s: %w", address, err)
+			}
+		}
+	}
+	return net.Listen(network, address)
+}
+
+// removeSocket removes the socket file of a Unix socket listener, it's fine if it's already gone
+func removeSocket(network, address string) error {
+	if network != "unix" {
+		return nil
+	}
+	if err := os.Remove(address); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove socket %s: %w", address, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// allowedMethods answers the requests to the paths of the routes with a method none of their routes handles:
+// OPTIONS gets 204 and other methods get 405, both with the Allow header listing the methods of the routes.
+// It must be added after the routes, the requests to other paths are passed on.
+func allowedMethods(routes []fiber.Route) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		methods := routeMethods(routes, c.Path())
+		if len(methods) == 0 {
+			return c.Next()
+		}
+
+		c.Set(fiber.HeaderAllow, strings.Join(append(methods, fiber.MethodOptions), ", "))
+		if c.Method() == fiber.MethodOptions {
+			return c.SendStatus(http.StatusNoContent)
+		}
+		return fiber.ErrMethodNotAllowed
+	}
+}
+
+// routeMethods returns the sorted methods of the routes matching the path
+func routeMethods(routes []fiber.Route, path string) []string {
+	var methods []string
+	for _, route := range routes {
+		if !slices.Contains(methods, route.Method) && matchRoute(route.Path, path) {
+			methods = append(methods, route.Method)
+		}
+	}
+	slices.Sort(methods)
+	return methods
+}
+
+// matchRoute tells whether the path matches the route path like the router does by default: ignoring the case and
+// a trailing slash, with a :param matching any non-empty segment
+func matchRoute(routePath, path string) bool {
+	routeSegments := strings.Split(strings.Trim(routePath, "/"), "/")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(routeSegments) != len(segments) {
+		return false
+	}
+	for i, segment := range routeSegments {
+		if strings.HasPrefix(segment, ":") {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if !strings.EqualFold(segment, segments[i]) {
+			return false
+		}
+	}
+	return true
+}
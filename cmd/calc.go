@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/corel-frim/item-packer-inc/packing"
+)
+
+const strategyUsage = "packing strategy: greedy (default), min-overpack, fewest-sizes, distinct-packs or max-under"
+
+// calc packs the items into the packs given on the command line, e.g. calc --packs 250,500,1000 --items 1750,
+// and writes the order as JSON to stdout. It returns the exit code: 0 on success, 1 when the items can't be packed
+// and 2 for invalid arguments.
+func calc(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("calc", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	packList := flags.String("packs", "", "comma separated pack amounts, e.g. 250,500,1000")
+	items := flags.Int("items", 0, "number of items to pack")
+	strategyName := flags.String("strategy", "", strategyUsage)
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	packs, err := parsePackList(*packList)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid --packs: %v\n", err)
+		return 2
+	}
+	if *items <= 0 {
+		fmt.Fprintln(stderr, "invalid --items: must be a positive integer")
+		return 2
+	}
+	opts, err := strategyOptions(*strategyName)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid --strategy: %v\n", err)
+		return 2
+	}
+
+	order, err := packing.Pack(packs, *items, opts...)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to pack the items: %v\n", err)
+		return 1
+	}
+
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(order); err != nil {
+		fmt.Fprintf(stderr, "failed to write the order: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parsePackList parses comma separated positive pack amounts
+func parsePackList(list string) ([]int, error) {
+	if strings.TrimSpace(list) == "" {
+		return nil, errors.New("must not be empty")
+	}
+	fields := strings.Split(list, ",")
+	packs := make([]int, len(fields))
+	for i, field := range fields {
+		amount, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || amount <= 0 {
+			return nil, fmt.Errorf("%q is not a positive integer", field)
+		}
+		packs[i] = amount
+	}
+	return packs, nil
+}
+
+// strategyOptions selects the named strategy, the default one when the name is empty
+func strategyOptions(name string) ([]packing.Option, error) {
+	if name == "" {
+		return nil, nil
+	}
+	strategy, err := packing.ParseStrategy(name)
+	if err != nil {
+		return nil, err
+	}
+	return []packing.Option{packing.WithStrategy(strategy)}, nil
+}
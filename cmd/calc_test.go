@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalc(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := calc([]string{"--packs", "250,500,1000", "--items", "1750"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Empty(t, stderr.String())
+
+	var order models.Order
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &order))
+	assert.Equal(t, 1750, order.TotalItems)
+	assert.Equal(t, 0, order.OverpackedItems)
+
+	stdout.Reset()
+	code = calc([]string{"--packs", "4, 6, 9", "--items", "11", "--strategy", "min-overpack"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &order))
+	assert.Equal(t, 12, order.TotalItems)
+
+	// Items overflowing the totals are rejected instead of packed into negative totals
+	stdout.Reset()
+	code = calc([]string{"--packs", "250,500,1000", "--items", "9223372036854775807"}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	assert.Empty(t, stdout.String())
+	assert.Contains(t, stderr.String(), "requested items are out of range")
+}
+
+func TestCalcInvalidArguments(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		stderr string
+	}{
+		{"no packs", []string{"--items", "10"}, "invalid --packs: must not be empty\n"},
+		{"invalid pack", []string{"--packs", "250,abc", "--items", "10"}, "invalid --packs: \"abc\" is not a positive integer\n"},
+		{"negative pack", []string{"--packs", "-250", "--items", "10"}, "invalid --packs: \"-250\" is not a positive integer\n"},
+		{"no items", []string{"--packs", "250"}, "invalid --items: must be a positive integer\n"},
+		{"unknown strategy", []string{"--packs", "250", "--items", "10", "--strategy", "best"}, "invalid --strategy: unknown strategy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, 2, calc(tt.args, &stdout, &stderr))
+			assert.Empty(t, stdout.String())
+			assert.Contains(t, stderr.String(), tt.stderr)
+		})
+	}
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, calc([]string{"--unknown"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "flag provided but not defined")
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/packing"
+)
+
+// batchHeader is the header of the CSV written by calc-batch
+var batchHeader = []string{"line", "requestedItems", "totalItems", "overpackedItems", "overpackPercent", "packs", "error"}
+
+// calcBatch packs every quantity of the input, one per line, into the packs given on the command line and writes a
+// CSV row for every quantity, e.g. calc-batch --packs 250,500,1000 --input quantities.txt --output orders.csv.
+// The input is read from stdin and the CSV is written to stdout unless files are given. Blank lines are skipped.
+//
+// Malformed lines and quantities that can't be packed are reported on stderr and with the error column of their row,
+// the following lines are still processed. It returns the exit code: 0 when every line was packed, 1 when some
+// weren't or the files can't be read or written, and 2 for invalid arguments.
+func calcBatch(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("calc-batch", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	packList := flags.String("packs", "", "comma separated pack amounts, e.g. 250,500,1000")
+	input := flags.String("input", "", "file with a quantity of items per line, stdin by default")
+	output := flags.String("output", "", "CSV file to write the orders to, stdout by default")
+	strategyName := flags.String("strategy", "", strategyUsage)
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	packs, err := parsePackList(*packList)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid --packs: %v\n", err)
+		return 2
+	}
+	opts, err := strategyOptions(*strategyName)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid --strategy: %v\n", err)
+		return 2
+	}
+
+	if *input != "" {
+		file, err := os.Open(*input)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to open the input: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		stdin = file
+	}
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to create the output: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		stdout = file
+	}
+
+	failed, err := writeBatch(stdin, stdout, stderr, packs, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to process the batch: %v\n", err)
+		return 1
+	}
+	if failed > 0 {
+		fmt.Fprintf(stderr, "%d lines failed\n", failed)
+		return 1
+	}
+	return 0
+}
+
+// writeBatch writes the CSV of the orders of the quantities read from the input and returns the number of lines
+// that failed. The error is only set when the input can't be read or the CSV can't be written.
+func writeBatch(input io.Reader, output, stderr io.Writer, packs []int, opts []packing.Option) (int, error) {
+	writer := csv.NewWriter(output)
+	if err := writer.Write(batchHeader); err != nil {
+		return 0, err
+	}
+
+	failed := 0
+	scanner := bufio.NewScanner(input)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var row []string
+		order, err := batchOrder(text, packs, opts)
+		if err != nil {
+			fmt.Fprintf(stderr, "line %d: %v\n", line, err)
+			failed++
+			row = batchErrorRow(line, text, err)
+		} else {
+			row = batchRow(line, order)
+		}
+		if err := writer.Write(row); err != nil {
+			return failed, err
+		}
+	}
+	writer.Flush()
+	if err := scanner.Err(); err != nil {
+		return failed, err
+	}
+	return failed, writer.Error()
+}
+
+// batchOrder packs the quantity of a line
+func batchOrder(text string, packs []int, opts []packing.Option) (models.Order, error) {
+	items, err := strconv.Atoi(text)
+	if err != nil || items <= 0 {
+		return models.Order{}, fmt.Errorf("invalid quantity %q, must be a positive integer", text)
+	}
+	return packing.Pack(packs, items, opts...)
+}
+
+// batchRow is the CSV row of an order
+func batchRow(line int, order models.Order) []string {
+	return []string{
+		strconv.Itoa(line),
+		strconv.Itoa(order.RequestedItems),
+		strconv.Itoa(order.TotalItems),
+		strconv.Itoa(order.OverpackedItems),
+		strconv.FormatFloat(order.OverpackPercent, 'f', -1, 64),
+		formatPacks(order.Packs),
+		"",
+	}
+}
+
+// formatPacks encodes the packs of an order for the packs column as <amount>x<quantity> entries separated by
+// semicolons, e.g. 500x2;250x1. Every amount appears once with the quantities of all its entries summed up, the
+// entries are sorted by descending amount and packs without a quantity are left out, so the same packing is always
+// encoded the same way whatever the order of its packs. An order without packs is an empty string.
+func formatPacks(orderPacks []models.OrderPack) string {
+	quantities := make(map[int]int, len(orderPacks))
+	for _, orderPack := range orderPacks {
+		if orderPack.Quantity > 0 {
+			quantities[orderPack.Pack.Amount] += orderPack.Quantity
+		}
+	}
+
+	amounts := slices.Sorted(maps.Keys(quantities))
+	slices.Reverse(amounts)
+	entries := make([]string, len(amounts))
+	for i, amount := range amounts {
+		entries[i] = fmt.Sprintf("%dx%d", amount, quantities[amount])
+	}
+	return strings.Join(entries, ";")
+}
+
+// batchErrorRow is the CSV row of a line that failed, with the line as the requested items
+func batchErrorRow(line int, text string, err error) []string {
+	return []string{strconv.Itoa(line), text, "", "", "", "", err.Error()}
+}
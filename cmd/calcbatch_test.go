@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/packing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalcBatch(t *testing.T) {
+	input := strings.NewReader("1\n251\n\nabc\n-5\n12001\n")
+	var stdout, stderr bytes.Buffer
+	code := calcBatch([]string{"--packs", "250,500,1000,2000,5000"}, input, &stdout, &stderr)
+
+	// The malformed lines fail without stopping the batch
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "line,requestedItems,totalItems,overpackedItems,overpackPercent,packs,error\n"+
+		"1,1,250,249,24900,250x1,\n"+
+		"2,251,500,249,99.2,500x1,\n"+
+		"4,abc,,,,,\"invalid quantity \"\"abc\"\", must be a positive integer\"\n"+
+		"5,-5,,,,,\"invalid quantity \"\"-5\"\", must be a positive integer\"\n"+
+		"6,12001,12250,249,2.07,5000x2;2000x1;250x1,\n", stdout.String())
+	assert.Equal(t, "line 4: invalid quantity \"abc\", must be a positive integer\n"+
+		"line 5: invalid quantity \"-5\", must be a positive integer\n"+
+		"2 lines failed\n", stderr.String())
+}
+
+func TestCalcBatchFiles(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "quantities.txt")
+	output := filepath.Join(dir, "orders.csv")
+	assert.NoError(t, os.WriteFile(input, []byte("750\n"), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	code := calcBatch([]string{"--packs", "250,500", "--input", input, "--output", output}, nil, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Empty(t, stdout.String())
+	assert.Empty(t, stderr.String())
+
+	csv, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	assert.Equal(t, "line,requestedItems,totalItems,overpackedItems,overpackPercent,packs,error\n"+
+		"1,750,750,0,0,500x1;250x1,\n", string(csv))
+
+	code = calcBatch([]string{"--packs", "250", "--input", filepath.Join(dir, "missing.txt")}, nil, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr.String(), "failed to open the input")
+}
+
+func TestCalcBatchInvalidArguments(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, calcBatch([]string{"--packs", "0"}, strings.NewReader("1\n"), &stdout, &stderr))
+	assert.Equal(t, 2, calcBatch([]string{"--packs", "250", "--strategy", "best"}, strings.NewReader("1\n"), &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+}
+
+func TestFormatPacks(t *testing.T) {
+	order, err := packing.Pack([]int{250, 500, 1000, 2000, 5000}, 12001, packing.WithUnusedPacks())
+	assert.NoError(t, err)
+	assert.Equal(t, "5000x2;2000x1;250x1", formatPacks(order.Packs))
+
+	// Split, unsorted and empty entries are encoded the same way
+	small, large := &models.Pack{Amount: 250}, &models.Pack{Amount: 500}
+	assert.Equal(t, "500x2;250x3", formatPacks([]models.OrderPack{
+		{Quantity: 1, Pack: small},
+		{Quantity: 2, Pack: large},
+		{Quantity: 0, Pack: &models.Pack{Amount: 1000}},
+		{Quantity: 2, Pack: small},
+	}))
+	assert.Empty(t, formatPacks(nil))
+}
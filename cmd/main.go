@@ -1,24 +1,219 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
 	"github.com/corel-frim/item-packer-inc/api"
+	"github.com/corel-frim/item-packer-inc/api/grpcapi"
 	"github.com/corel-frim/item-packer-inc/internal/storage"
+	"github.com/corel-frim/item-packer-inc/packing"
+	"github.com/gofiber/fiber/v2/log"
 )
 
 // @title Item Packer API
 // @version 1.0
-// nolint:errcheck
 func main() {
+	// The first argument selects the command, the server runs without one
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "serve":
+		serve()
+	case "calc":
+		os.Exit(calc(args, os.Stdout, os.Stderr))
+	case "calc-batch":
+		os.Exit(calcBatch(args, os.Stdin, os.Stdout, os.Stderr))
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q, must be serve, calc or calc-batch\n", command)
+		os.Exit(2)
+	}
+}
+
+// serve runs the API configured with the environment variables until it receives a termination signal
+// nolint:errcheck
+func serve() {
+	var storageOpts []storage.Option
+
+	// Drop orders older than ORDER_RETENTION (e.g. "24h"), disabled by default
+	retention := os.Getenv("ORDER_RETENTION")
+	if retention != "" {
+		duration, err := time.ParseDuration(retention)
+		if err != nil {
+			log.Fatalf("invalid ORDER_RETENTION: %v", err)
+		}
+		storageOpts = append(storageOpts, storage.WithOrderRetention(duration))
+	}
+
+	// Abort order computations taking longer than ORDER_TIMEOUT (e.g. "5s"), disabled by default
+	if timeout := os.Getenv("ORDER_TIMEOUT"); timeout != "" {
+		duration, err := time.ParseDuration(timeout)
+		if err != nil {
+			log.Fatalf("invalid ORDER_TIMEOUT: %v", err)
+		}
+		storageOpts = append(storageOpts, storage.WithComputeTimeout(duration))
+	}
+
+	// Pack the orders that don't select a strategy with DEFAULT_STRATEGY, greedy by default
+	if name := os.Getenv("DEFAULT_STRATEGY"); name != "" {
+		strategy, err := packing.ParseStrategy(name)
+		if err != nil {
+			log.Fatalf("invalid DEFAULT_STRATEGY: %v", err)
+		}
+		storageOpts = append(storageOpts, storage.WithDefaultStrategy(strategy))
+	}
+
+	// Only accept pack amounts that are multiples of PACK_MULTIPLE (e.g. "50"), any amount by default
+	if multiple := os.Getenv("PACK_MULTIPLE"); multiple != "" {
+		value, err := strconv.Atoi(multiple)
+		if err != nil || value <= 0 {
+			log.Fatalf("invalid PACK_MULTIPLE: must be a positive integer")
+		}
+		storageOpts = append(storageOpts, storage.WithPackMultiple(value))
+	}
+
+	// Only accept pack amounts of at least MIN_PACK_AMOUNT (e.g. "100"), any positive amount by default
+	if minAmount := os.Getenv("MIN_PACK_AMOUNT"); minAmount != "" {
+		value, err := strconv.Atoi(minAmount)
+		if err != nil || value <= 0 {
+			log.Fatalf("invalid MIN_PACK_AMOUNT: must be a positive integer")
+		}
+		storageOpts = append(storageOpts, storage.WithMinPackAmount(value))
+	}
+
+	// Reject orders needing more than MAX_ORDER_PACKS packs (e.g. "10000"), unlimited by default
+	if limit := os.Getenv("MAX_ORDER_PACKS"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value <= 0 {
+			log.Fatalf("invalid MAX_ORDER_PACKS: must be a positive integer")
+		}
+		storageOpts = append(storageOpts, storage.WithMaxOrderPacks(value))
+	}
+
+	// Flag the orders overpacking more than OVERPACK_WARN_PERCENT percent (e.g. "10") with a warning, disabled by default
+	if percent := os.Getenv("OVERPACK_WARN_PERCENT"); percent != "" {
+		value, err := strconv.ParseFloat(percent, 64)
+		if err != nil || value <= 0 {
+			log.Fatalf("invalid OVERPACK_WARN_PERCENT: must be a positive number")
+		}
+		storageOpts = append(storageOpts, storage.WithOverpackPercentWarning(value))
+	}
+
+	// Flag the orders overpacking more than OVERPACK_WARN_ITEMS items (e.g. "500") with a warning, disabled by default
+	if items := os.Getenv("OVERPACK_WARN_ITEMS"); items != "" {
+		value, err := strconv.Atoi(items)
+		if err != nil || value <= 0 {
+			log.Fatalf("invalid OVERPACK_WARN_ITEMS: must be a positive integer")
+		}
+		storageOpts = append(storageOpts, storage.WithOverpackItemsWarning(value))
+	}
+
+	// Compute the orders without recording them when ORDER_HISTORY is false, recorded by default
+	if history := os.Getenv("ORDER_HISTORY"); history != "" {
+		enabled, err := strconv.ParseBool(history)
+		if err != nil {
+			log.Fatalf("invalid ORDER_HISTORY: must be true or false")
+		}
+		if !enabled {
+			storageOpts = append(storageOpts, storage.WithoutOrderHistory())
+		}
+	}
+
+	// Round the percentages with PERCENT_ROUNDING (half-up, floor or ceil) to PERCENT_DECIMALS decimal places,
+	// half-up to 2 decimal places by default
+	rounding := packing.DefaultRounding
+	if mode := os.Getenv("PERCENT_ROUNDING"); mode != "" {
+		value, err := packing.ParseRoundingMode(mode)
+		if err != nil {
+			log.Fatalf("invalid PERCENT_ROUNDING: %v", err)
+		}
+		rounding.Mode = value
+	}
+	if decimals := os.Getenv("PERCENT_DECIMALS"); decimals != "" {
+		value, err := strconv.Atoi(decimals)
+		if err != nil || value < 0 || value > packing.MaxRoundingDecimals {
+			log.Fatalf("invalid PERCENT_DECIMALS: must be an integer between 0 and %d", packing.MaxRoundingDecimals)
+		}
+		rounding.Decimals = value
+	}
+	storageOpts = append(storageOpts, storage.WithPercentRounding(rounding))
+
 	// Create a new storage instance
-	packStorage := storage.NewPackStorage()
+	packStorage := storage.NewPackStorage(storageOpts...)
+
+	// Start with the packs of the PACK_PRESET preset, the default packs by default
+	preset := os.Getenv("PACK_PRESET")
+	if preset == "" {
+		preset = storage.DefaultPreset
+	}
+	if _, err := packStorage.ApplyPreset(context.Background(), preset); err != nil {
+		// The default packs may not fit the pack restrictions, the server starts without them then
+		if os.Getenv("PACK_PRESET") != "" {
+			log.Fatalf("invalid PACK_PRESET: %v", err)
+		}
+		log.Warnf("failed to add the default packs: %v", err)
+	}
 
-	// Add some default packs
-	packStorage.AddPack(250)
-	packStorage.AddPack(500)
-	packStorage.AddPack(1000)
-	packStorage.AddPack(2000)
-	packStorage.AddPack(5000)
+	if retention != "" {
+		// Prune expired entries in the background every JANITOR_INTERVAL, once a minute by default
+		interval := time.Minute
+		if envInterval := os.Getenv("JANITOR_INTERVAL"); envInterval != "" {
+			parsed, err := time.ParseDuration(envInterval)
+			if err != nil {
+				log.Fatalf("invalid JANITOR_INTERVAL: %v", err)
+			}
+			interval = parsed
+		}
+		packStorage.StartJanitor(context.Background(), interval)
+	}
 
 	newAPI := api.NewAPI(packStorage)
-	newAPI.Start()
+	go func() {
+		if err := newAPI.Start(); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	// Serve the gRPC API on GRPC_PORT (e.g. "9090") next to the REST API, disabled by default
+	var grpcServer *grpcapi.Server
+	if port := os.Getenv("GRPC_PORT"); port != "" {
+		value, err := strconv.Atoi(port)
+		if err != nil || value <= 0 || value > 65535 {
+			log.Fatalf("invalid GRPC_PORT: must be a port number")
+		}
+		listener, err := net.Listen("tcp", ":"+port)
+		if err != nil {
+			log.Fatalf("failed to listen on GRPC_PORT: %v", err)
+		}
+		grpcServer = grpcapi.NewServer(packStorage)
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
+
+	// Wait for a termination signal and shut down gracefully
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	// Close the storage first to end the order streams, the server waits for them to finish otherwise
+	_ = packStorage.Close(context.Background())
+	if err := newAPI.Shutdown(); err != nil {
+		log.Errorf("failed to shut down the server: %v", err)
+	}
+	if grpcServer != nil {
+		grpcServer.Stop()
+	}
 }
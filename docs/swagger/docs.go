@@ -15,22 +15,22 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/order/items/{amount}": {
-            "post": {
-                "description": "Create an order with the specified number of items",
+        "/admin/config": {
+            "get": {
+                "description": "Export the packs with their labels and pins, the pack limit and the default strategy as JSON.\nThe configuration can be imported again with POST /admin/config.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "admin"
                 ],
-                "summary": "Create an order",
+                "summary": "Get the pack configuration",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Number of items",
-                        "name": "amount",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Admin auth key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
                         "required": true
                     }
                 ],
@@ -38,11 +38,66 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Order"
+                            "$ref": "#/definitions/models.PackConfig"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid admin key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API is disabled",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Atomically replace the packs, the pack limit and the default strategy with the configuration, keeping the recorded orders.\nThe body is JSON unless the Content-Type is YAML (application/yaml, application/x-yaml or text/yaml), unknown YAML fields are rejected.\nA zero softLimit or an empty defaultStrategy keeps the current one. The configuration is checked like a restored snapshot.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Import the pack configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin auth key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Configuration to import",
+                        "name": "config",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PackConfig"
                         }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
-                        "description": "Invalid amount",
+                        "description": "Invalid config",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -50,8 +105,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "No packs available",
+                    "401": {
+                        "description": "Invalid admin key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API is disabled",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -62,80 +126,146 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders": {
+        "/admin/config.yaml": {
             "get": {
-                "description": "Retrieve a list of all orders",
+                "description": "Export the same configuration as GET /admin/config as YAML, to keep it in a repository",
                 "produces": [
-                    "application/json"
+                    "application/yaml"
                 ],
                 "tags": [
-                    "orders"
+                    "admin"
+                ],
+                "summary": "Get the pack configuration as YAML",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin auth key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all orders",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Order"
+                            "$ref": "#/definitions/models.PackConfig"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid admin key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API is disabled",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
             }
         },
-        "/packs": {
-            "get": {
-                "description": "Get a list of all available packs",
+        "/admin/restore": {
+            "post": {
+                "description": "Atomically replace the full storage state with the given snapshot",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "packs"
+                    "admin"
+                ],
+                "summary": "Restore storage snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin auth key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Snapshot to restore",
+                        "name": "snapshot",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Snapshot"
+                        }
+                    }
                 ],
-                "summary": "Get all available packs",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid snapshot",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Pack"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid admin key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API is disabled",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
             }
         },
-        "/packs/{amount}": {
-            "post": {
-                "description": "Add a new pack with the specified amount",
+        "/admin/snapshot": {
+            "get": {
+                "description": "Dump the full storage state (packs, orders and config) as JSON",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "packs"
+                    "admin"
                 ],
-                "summary": "Add a new pack",
+                "summary": "Get storage snapshot",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Pack amount",
-                        "name": "amount",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Admin auth key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Pack"
+                            "$ref": "#/definitions/models.Snapshot"
                         }
                     },
-                    "400": {
-                        "description": "Invalid amount",
+                    "401": {
+                        "description": "Invalid admin key",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -143,8 +273,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Limit for packs reached",
+                    "403": {
+                        "description": "Admin API is disabled",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -153,31 +283,45 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a pack with the specified amount",
+            }
+        },
+        "/admin/stats": {
+            "get": {
+                "description": "Report the current pack and order counts, the configured limits, a rough memory estimate\nand the hit rate of the cache of computed orders. It doesn't change anything.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "packs"
+                    "admin"
                 ],
-                "summary": "Delete a pack",
+                "summary": "Get storage stats",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Pack amount",
-                        "name": "amount",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Admin auth key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StorageStats"
+                        }
                     },
-                    "400": {
-                        "description": "Invalid amount",
+                    "401": {
+                        "description": "Invalid admin key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API is disabled",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -188,41 +332,153 @@ const docTemplate = `{
                 }
             }
         },
-        "/packs/{oldAmount}/{newAmount}": {
-            "put": {
-                "description": "Update a pack's amount",
+        "/calculate": {
+            "post": {
+                "description": "Calculate the optimal packing for the requested items using the pack amounts from the request. Nothing is stored.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "packs"
+                    "calculate"
                 ],
-                "summary": "Update a pack",
+                "summary": "Calculate an order against the given packs",
+                "parameters": [
+                    {
+                        "description": "Pack amounts and number of items",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CalculateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, with the reason of every invalid field",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/order/items/{amount}": {
+            "post": {
+                "description": "Create an order with the specified number of items.\nThe optional body tags the order with a reference and metadata which are recorded and echoed back.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Create an order",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Current pack amount",
-                        "name": "oldAmount",
+                        "description": "Number of items",
+                        "name": "amount",
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "description": "Reference of at most 64 characters and at most 10 metadata entries of at most 256 characters",
+                        "name": "tags",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderTags"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "greedy",
+                            "min-overpack",
+                            "fewest-sizes",
+                            "distinct-packs",
+                            "max-under"
+                        ],
+                        "type": "string",
+                        "default": "greedy",
+                        "description": "Packing algorithm",
+                        "name": "strategy",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "New pack amount",
-                        "name": "newAmount",
-                        "in": "path",
-                        "required": true
+                        "description": "Pack amount used to fill the remaining items instead of the smallest pack, greedy strategy only, rejected with any other strategy",
+                        "name": "fillerPack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Max overpacked items, either a number of items (100) or a percentage of the requested items (10%)",
+                        "name": "maxOverpack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of packs of a single size",
+                        "name": "maxPerSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pack amount the order must include at least once",
+                        "name": "requirePack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Guarantee an overpack below the smallest pack amount, orders that can't meet it are rejected with 422",
+                        "name": "belowSmallestPack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the unused packs with zero quantity",
+                        "name": "includeUnused",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Respond with the order and the steps of its computation",
+                        "name": "trace",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Respond with flat packs {amount, quantity, subtotal} instead of nested packs",
+                        "name": "flat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also list every single pack as a carton, at most 1000",
+                        "name": "cartons",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "The order, models.TracedOrder with trace=true, models.FlatOrder or models.FlatTracedOrder with flat=true",
                         "schema": {
-                            "$ref": "#/definitions/models.Pack"
+                            "$ref": "#/definitions/models.Order"
                         }
                     },
                     "400": {
-                        "description": "Invalid amount",
+                        "description": "Invalid amount, strategy, filler pack, max overpack, max per size, required pack, include unused, trace, flat or cartons, or request body with the reason of every invalid field under errors",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -231,7 +487,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Pack not found",
+                        "description": "No packs available",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -239,8 +495,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Pack with new amount already exists",
+                    "422": {
+                        "description": "Overpack exceeds the allowed maximum, items can't be packed within the constraints, the order is too complex or needs too many packs (in total or for cartons)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Order computation timed out",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -250,12 +515,1905 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Order": {
-            "type": "object",
+        },
+        "/orders": {
+            "get": {
+                "description": "Retrieve a list of all orders, oldest first, optionally filtered by the range of requested items and the reference.\nThe list is empty if no orders match.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get all orders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Min requested items, inclusive",
+                        "name": "minItems",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max requested items, inclusive",
+                        "name": "maxItems",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reference the orders were tagged with",
+                        "name": "reference",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Respond with flat packs {amount, quantity, subtotal} instead of nested packs",
+                        "name": "flat",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The orders, or models.FlatOrder with flat=true",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Order"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid items range or flat",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create an order for every number of items in the request body, e.g. [250, 1001], with the same options.\nEvery entry is processed even if others fail. The response lists the outcome of every entry in the same order\nwith the status a single order would get, the order on success and the error otherwise.\nIt's 200 if all entries succeed and 207 Multi-Status otherwise.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Create a batch of orders",
+                "parameters": [
+                    {
+                        "description": "Numbers of items, at most 100",
+                        "name": "items",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    {
+                        "enum": [
+                            "greedy",
+                            "min-overpack",
+                            "fewest-sizes",
+                            "distinct-packs",
+                            "max-under"
+                        ],
+                        "type": "string",
+                        "default": "greedy",
+                        "description": "Packing algorithm",
+                        "name": "strategy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pack amount used to fill the remaining items instead of the smallest pack, greedy strategy only, rejected with any other strategy",
+                        "name": "fillerPack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Max overpacked items, either a number of items (100) or a percentage of the requested items (10%)",
+                        "name": "maxOverpack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of packs of a single size",
+                        "name": "maxPerSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pack amount the order must include at least once",
+                        "name": "requirePack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Guarantee an overpack below the smallest pack amount, orders that can't meet it are rejected with 422",
+                        "name": "belowSmallestPack",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "All orders were created",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BatchOrderResult"
+                            }
+                        }
+                    },
+                    "207": {
+                        "description": "Some orders failed",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BatchOrderResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid strategy, filler pack, max overpack, max per size or required pack, or request body with the reason of every invalid field under errors",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/candidates/{amount}": {
+            "get": {
+                "description": "Pack the specified number of items into up to count distinct orders ranked by the strategy, best first, to pick among\nthe trade-offs. Every candidate holds more items than the one before it, so the overpack grows. No orders are recorded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get candidate orders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of items",
+                        "name": "amount",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of candidates, 3 by default, at most 10",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "greedy",
+                            "min-overpack",
+                            "fewest-sizes",
+                            "distinct-packs",
+                            "max-under"
+                        ],
+                        "type": "string",
+                        "default": "greedy",
+                        "description": "Packing algorithm",
+                        "name": "strategy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pack amount used to fill the remaining items instead of the smallest pack, greedy strategy only, rejected with any other strategy",
+                        "name": "fillerPack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Max overpacked items, either a number of items (100) or a percentage of the requested items (10%)",
+                        "name": "maxOverpack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of packs of a single size",
+                        "name": "maxPerSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pack amount the order must include at least once",
+                        "name": "requirePack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Guarantee an overpack below the smallest pack amount, orders that can't meet it are rejected with 422",
+                        "name": "belowSmallestPack",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Order"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount, count, strategy, filler pack, max overpack, max per size or required pack",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No packs available",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Overpack exceeds the allowed maximum, items can't be packed within the constraints, the order is too complex or needs too many packs",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Order computation timed out",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/compare/{amount}": {
+            "get": {
+                "description": "Pack the specified number of items with both the greedy and the min-overpack strategies against the current packs\nand report both results side by side with their overpack and pack counts. No orders are recorded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Compare the greedy and the min-overpack strategies",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of items",
+                        "name": "amount",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pack amount used to fill the remaining items instead of the smallest pack, greedy strategy only, rejected with any other strategy",
+                        "name": "fillerPack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Max overpacked items, either a number of items (100) or a percentage of the requested items (10%)",
+                        "name": "maxOverpack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of packs of a single size",
+                        "name": "maxPerSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pack amount the order must include at least once",
+                        "name": "requirePack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Guarantee an overpack below the smallest pack amount, orders that can't meet it are rejected with 422",
+                        "name": "belowSmallestPack",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StrategyComparison"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount, filler pack, max overpack, max per size or required pack",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No packs available",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Either strategy exceeds the allowed overpack, can't pack the items within the constraints, the order is too complex or needs too many packs",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Order computation timed out",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/minpacks/{amount}": {
+            "get": {
+                "description": "Get the fewest packs holding the specified number of items with the least overpack against the current packs,\nthe pack count of the min-overpack strategy, as a plain number. The order is not recorded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get the minimum number of packs for an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of items",
+                        "name": "amount",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No packs available",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "The order is too complex or needs too many packs",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Order computation timed out",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/quote/{amount}": {
+            "get": {
+                "description": "Get the total and overpacked items and the number of packs for the specified number of items without the pack breakdown. The order is not recorded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Quote an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of items",
+                        "name": "amount",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "greedy",
+                            "min-overpack",
+                            "fewest-sizes",
+                            "distinct-packs",
+                            "max-under"
+                        ],
+                        "type": "string",
+                        "default": "greedy",
+                        "description": "Packing algorithm",
+                        "name": "strategy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pack amount used to fill the remaining items instead of the smallest pack, greedy strategy only, rejected with any other strategy",
+                        "name": "fillerPack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Max overpacked items, either a number of items (100) or a percentage of the requested items (10%)",
+                        "name": "maxOverpack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of packs of a single size",
+                        "name": "maxPerSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pack amount the order must include at least once",
+                        "name": "requirePack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Guarantee an overpack below the smallest pack amount, orders that can't meet it are rejected with 422",
+                        "name": "belowSmallestPack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version of the pack set to quote against instead of the current packs, see GET /packs/at",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Quote"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount, strategy, filler pack, max overpack, max per size, required pack or version",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No packs available or pack set version not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Overpack exceeds the allowed maximum, items can't be packed within the constraints, the order is too complex or needs too many packs",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Order computation timed out",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/recompute": {
+            "post": {
+                "description": "Pack the requested items of every recorded order again with the current packs and the default strategy,\nkeeping their timestamps, references and metadata. Orders that can't be packed anymore are left unchanged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Recompute the recorded orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin auth key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecomputeSummary"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid admin key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API is disabled",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No packs available",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Packs changed during the recomputation",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Recomputation timed out",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/return/{amount}": {
+            "post": {
+                "description": "Get the packs to disassemble to take the returned items out of packed stock. The amount is the returned quantity,\neither negative like a ledger entry (-750) or positive (750), zero is rejected. The packs are chosen like the packs\nof an order of the returned items with the same query parameters, looseItems are the items left over in the opened packs.\nThe max-under strategy only succeeds if it packs the items exactly. No order is recorded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Process a return",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Returned quantity, negative or positive",
+                        "name": "amount",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "greedy",
+                            "min-overpack",
+                            "fewest-sizes",
+                            "distinct-packs",
+                            "max-under"
+                        ],
+                        "type": "string",
+                        "default": "greedy",
+                        "description": "Packing algorithm",
+                        "name": "strategy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pack amount used to fill the remaining items instead of the smallest pack, greedy strategy only, rejected with any other strategy",
+                        "name": "fillerPack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Max loose items, either a number of items (100) or a percentage of the returned items (10%)",
+                        "name": "maxOverpack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of packs of a single size",
+                        "name": "maxPerSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pack amount to disassemble at least once",
+                        "name": "requirePack",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Guarantee fewer loose items than the smallest pack amount, returns that can't meet it are rejected with 422",
+                        "name": "belowSmallestPack",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Return"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount, strategy, filler pack, max overpack, max per size or required pack",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No packs available",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Loose items exceed the allowed maximum, the items can't be taken out within the constraints, the return is too complex or needs too many packs",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Order computation timed out",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/stream": {
+            "get": {
+                "description": "Stream every new order as a server-sent event named \"order\" with the order as JSON data.\nOrders are dropped for clients that don't keep up.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Stream new orders",
+                "responses": {
+                    "200": {
+                        "description": "Stream of orders",
+                        "schema": {
+                            "$ref": "#/definitions/models.Order"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/receipt": {
+            "get": {
+                "description": "Render the recorded order with the specified ID as a receipt listing the packs, their quantities and subtotals,\nand the totals. The format is negotiated with the Accept header: HTML by default or plain text.",
+                "produces": [
+                    "text/html",
+                    "text/plain"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get the receipt of an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Receipt",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "Neither HTML nor plain text is acceptable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/packs": {
+            "get": {
+                "description": "Get a list of all available packs, largest first by default, optionally filtered by the range of amounts.\nSupports conditional requests with If-None-Match and If-Modified-Since, the validators describe the whole pack set.\nThe X-Pack-Set-Version header holds a version which increases on every change of the packs.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packs"
+                ],
+                "summary": "Get all available packs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Min pack amount, inclusive",
+                        "name": "min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max pack amount, inclusive",
+                        "name": "max",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort by amount ascending or descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previously fetched pack set",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of a previously fetched pack set",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Pack"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash of the pack set"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Time of the last change of the pack set"
+                            },
+                            "X-Pack-Set-Version": {
+                                "type": "integer",
+                                "description": "Version of the pack set"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash of the pack set"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Time of the last change of the pack set"
+                            },
+                            "X-Pack-Set-Version": {
+                                "type": "integer",
+                                "description": "Version of the pack set"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount range or sort",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/packs/at": {
+            "get": {
+                "description": "Get the packs as they were at the specified version of the pack set, as in the X-Pack-Set-Version header\nand the version of the pack history entries. Only the most recent versions are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packs"
+                ],
+                "summary": "Get the packs of a past version",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Version of the pack set",
+                        "name": "version",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Pack"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid version",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Pack set version not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/packs/fit/{amount}": {
+            "get": {
+                "description": "Tell whether some packing holds exactly the items, the largest pack holding at most the items and the\nsmallest pack holding at least them, without computing or recording an order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packs"
+                ],
+                "summary": "Check how the packs fit a number of items",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of items",
+                        "name": "amount",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PackFit"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No packs available",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "The packs are too complex to check for an exact fit",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Exact fit check timed out",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/packs/history": {
+            "get": {
+                "description": "Get the log of pack additions, updates and deletions, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packs"
+                ],
+                "summary": "Get pack change history",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PackChange"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/packs/id/{id}": {
+            "delete": {
+                "description": "Delete the pack with the specified ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packs"
+                ],
+                "summary": "Delete a pack by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Pack is pinned",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/packs/id/{id}/{newAmount}": {
+            "put": {
+                "description": "Update the amount of the pack with the specified ID and return the updated pack",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packs"
+                ],
+                "summary": "Update a pack by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "New pack amount",
+                        "name": "newAmount",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Pack"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or amount, or an amount below the minimum pack amount or not a multiple of the pack multiple",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Pack with new amount already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/packs/import": {
+            "post": {
+                "description": "Add the packs with the amounts from the request body and report the outcome for every amount in the same order:\nadded, skipped-duplicate-in-request, skipped-already-exists, rejected-limit or rejected-invalid.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packs"
+                ],
+                "summary": "Import packs",
+                "parameters": [
+                    {
+                        "description": "Pack amounts",
+                        "name": "amounts",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PackImportResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/packs/preset/{name}": {
+            "post": {
+                "description": "Replace the packs with the built-in preset with the specified name and return the new packs.\nThe packs with amounts of the preset are kept with their labels and pins, the others are deleted.\nThe preset is applied as a whole, nothing changes if any of its amounts is rejected.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packs"
+                ],
+                "summary": "Apply a pack preset",
+                "parameters": [
+                    {
+                        "enum": [
+                            "default",
+                            "metric",
+                            "dozens"
+                        ],
+                        "type": "string",
+                        "description": "Preset name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Pack"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Preset amount below the minimum pack amount or not a multiple of the pack multiple",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Preset not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Preset exceeds the limit for packs or a pinned pack isn't part of it",
+                        "schema": {
+                            "$ref": "#/definitions/models.LimitErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/packs/recommend": {
+            "get": {
+                "description": "Suggest up to two new pack sizes which would most reduce the cumulative overpack of the recorded orders",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packs"
+                ],
+                "summary": "Recommend new pack sizes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of suggestions, 1 by default, at most 2",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PackRecommendation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid count",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No packs available",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Recommendation timed out",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/packs/validate": {
+            "post": {
+                "description": "Check whether the pack amounts from the request body could replace the current packs and list all the problems:\nlimit-exceeded, not-positive, duplicate, too-small or not-multiple. Nothing is changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packs"
+                ],
+                "summary": "Validate a pack configuration",
+                "parameters": [
+                    {
+                        "description": "Pack amounts",
+                        "name": "amounts",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PackValidation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/packs/{amount}": {
+            "get": {
+                "description": "Get the pack with the specified amount, can be used to check whether it exists",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packs"
+                ],
+                "summary": "Get a pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack amount",
+                        "name": "amount",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Pack"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a new pack with the specified amount and an optional label and return it.\nAn existing pack keeps its label and is returned as it is.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packs"
+                ],
+                "summary": "Add a new pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack amount",
+                        "name": "amount",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Human-readable name of the pack, at most 64 characters",
+                        "name": "label",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Pack"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount or label, or an amount below the minimum pack amount or not a multiple of the pack multiple",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Limit for packs reached or pack with this label already exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.LimitErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a pack with the specified amount. Returns 404 if there is no such pack.\nWith safe=true the pack isn't deleted while a recorded order uses its amount.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packs"
+                ],
+                "summary": "Delete a pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack amount",
+                        "name": "amount",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Refuse to delete the pack if a recorded order uses it",
+                        "name": "safe",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid amount or safe",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Pack is pinned or, with safe=true, used by recorded orders",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/packs/{amount}/label": {
+            "put": {
+                "description": "Set the human-readable label of the pack with the specified amount, an empty or missing label removes it.\nLabels are unique ignoring case and whitespace.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packs"
+                ],
+                "summary": "Label a pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack amount",
+                        "name": "amount",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Human-readable name of the pack, at most 64 characters",
+                        "name": "label",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Pack"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount or label",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Pack with this label already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/packs/{amount}/pin": {
+            "post": {
+                "description": "Pin the pack with the specified amount, pinned packs can't be deleted until they are unpinned",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packs"
+                ],
+                "summary": "Pin a pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack amount",
+                        "name": "amount",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Pack"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Unpin the pack with the specified amount so it can be deleted again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packs"
+                ],
+                "summary": "Unpin a pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack amount",
+                        "name": "amount",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Pack"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/packs/{oldAmount}/{newAmount}": {
+            "put": {
+                "description": "Update a pack's amount and return the updated pack.\nWith upsert=true a missing pack is created with the new amount instead of 404, within the limit of packs,\nand if only a pack with the new amount exists it is returned unchanged, so the call can be repeated.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packs"
+                ],
+                "summary": "Update a pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Current pack amount",
+                        "name": "oldAmount",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "New pack amount",
+                        "name": "newAmount",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Create the pack if there is no pack with the old amount",
+                        "name": "upsert",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Pack"
+                        }
+                    },
+                    "201": {
+                        "description": "Created with upsert=true",
+                        "schema": {
+                            "$ref": "#/definitions/models.Pack"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount or upsert, or below the minimum pack amount or not a multiple of the pack multiple",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Pack with new amount already exists or limit for packs reached",
+                        "schema": {
+                            "$ref": "#/definitions/models.LimitErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/summary": {
+            "get": {
+                "description": "Get the number of orders and the total requested, shipped and overpacked items of the recorded orders",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get the order totals",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderSummary"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.BatchOrderResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "integer"
+                },
+                "order": {
+                    "$ref": "#/definitions/models.Order"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CacheStats": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "entries": {
+                    "type": "integer"
+                },
+                "hitRatePercent": {
+                    "description": "HitRatePercent is the share of the lookups finding a cached order, 0 without lookups",
+                    "type": "number"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CalculateRequest": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "integer"
+                },
+                "packs": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.Carton": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "packId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ConfigPack": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "pinned": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.LimitErrorResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Order": {
+            "type": "object",
             "properties": {
+                "availablePacks": {
+                    "description": "AvailablePacks are the amounts of all the packs the order was packed from, largest first,\nso the order can be audited after the packs change",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "cartons": {
+                    "description": "Cartons lists every single pack of the order, it's only set on request with WithCartons",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Carton"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID identifies a recorded order, it's assigned when the order is recorded",
+                    "type": "integer"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "overpackPercent": {
+                    "description": "OverpackPercent is OverpackedItems relative to RequestedItems in percent, rounded half up to 2 decimal places\nunless another rounding is configured",
+                    "type": "number"
+                },
                 "overpackedItems": {
                     "type": "integer"
                 },
@@ -265,11 +2423,26 @@ const docTemplate = `{
                         "$ref": "#/definitions/models.OrderPack"
                     }
                 },
+                "reference": {
+                    "description": "Reference and Metadata are set by the client to correlate the order with its own systems",
+                    "type": "string"
+                },
                 "requestedItems": {
                     "type": "integer"
                 },
                 "totalItems": {
                     "type": "integer"
+                },
+                "unfulfilledItems": {
+                    "description": "UnfulfilledItems is the shortfall of the orders of the max-under strategy holding fewer items than requested",
+                    "type": "integer"
+                },
+                "warnings": {
+                    "description": "Warnings are advisories about the order, like an overpack above the configured threshold.\nUnlike the hard limits they don't reject the order.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
@@ -281,6 +2454,44 @@ const docTemplate = `{
                 },
                 "quantity": {
                     "type": "integer"
+                },
+                "subtotal": {
+                    "description": "Subtotal is the number of items in all packs of this size (quantity * amount)",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OrderSummary": {
+            "type": "object",
+            "properties": {
+                "averageOverpackPercent": {
+                    "type": "number"
+                },
+                "orders": {
+                    "type": "integer"
+                },
+                "overpackedItems": {
+                    "type": "integer"
+                },
+                "requestedItems": {
+                    "type": "integer"
+                },
+                "shippedItems": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OrderTags": {
+            "type": "object",
+            "properties": {
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "reference": {
+                    "type": "string"
                 }
             }
         },
@@ -289,6 +2500,342 @@ const docTemplate = `{
             "properties": {
                 "amount": {
                     "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "label": {
+                    "description": "Label is a human-readable name of the pack like \"bulk pallet\", the solver ignores it",
+                    "type": "string"
+                },
+                "pinned": {
+                    "description": "Pinned packs can't be deleted until they are unpinned",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.PackChange": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "newAmount": {
+                    "type": "integer"
+                },
+                "oldAmount": {
+                    "type": "integer"
+                },
+                "packId": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version is the version of the pack set after the change",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PackConfig": {
+            "type": "object",
+            "properties": {
+                "defaultStrategy": {
+                    "type": "string"
+                },
+                "packs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ConfigPack"
+                    }
+                },
+                "softLimit": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PackFit": {
+            "type": "object",
+            "properties": {
+                "exactFit": {
+                    "description": "ExactFit tells whether some packing holds exactly the requested items",
+                    "type": "boolean"
+                },
+                "largestFitting": {
+                    "description": "LargestFitting is the largest pack holding at most the requested items, omitted if every pack is larger",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Pack"
+                        }
+                    ]
+                },
+                "requestedItems": {
+                    "type": "integer"
+                },
+                "smallestCovering": {
+                    "description": "SmallestCovering is the smallest pack holding at least the requested items, omitted if every pack is smaller",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Pack"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.PackImportResult": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PackRecommendation": {
+            "type": "object",
+            "properties": {
+                "currentOverpack": {
+                    "type": "integer"
+                },
+                "orders": {
+                    "type": "integer"
+                },
+                "suggestions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PackSuggestion"
+                    }
+                }
+            }
+        },
+        "models.PackSuggestion": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "overpack": {
+                    "type": "integer"
+                },
+                "reduction": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PackValidation": {
+            "type": "object",
+            "properties": {
+                "valid": {
+                    "type": "boolean"
+                },
+                "violations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PackViolation"
+                    }
+                }
+            }
+        },
+        "models.PackViolation": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Quote": {
+            "type": "object",
+            "properties": {
+                "overpackedItems": {
+                    "type": "integer"
+                },
+                "packCount": {
+                    "type": "integer"
+                },
+                "requestedItems": {
+                    "type": "integer"
+                },
+                "totalItems": {
+                    "type": "integer"
+                },
+                "unfulfilledItems": {
+                    "description": "UnfulfilledItems is the shortfall of the max-under strategy, see Order",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RecomputeSummary": {
+            "type": "object",
+            "properties": {
+                "changed": {
+                    "description": "Changed is the number of orders packed differently than before",
+                    "type": "integer"
+                },
+                "failed": {
+                    "description": "Failed is the number of orders that can't be packed anymore, they are left unchanged",
+                    "type": "integer"
+                },
+                "orders": {
+                    "description": "Orders is the number of recorded orders when the recomputation started",
+                    "type": "integer"
+                },
+                "overpackDelta": {
+                    "description": "OverpackDelta is the change of the total overpack of the changed orders, negative if it went down",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Return": {
+            "type": "object",
+            "properties": {
+                "disassembledItems": {
+                    "type": "integer"
+                },
+                "looseItems": {
+                    "type": "integer"
+                },
+                "packs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderPack"
+                    }
+                },
+                "returnedItems": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Snapshot": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/models.SnapshotConfig"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Order"
+                    }
+                },
+                "packs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Pack"
+                    }
+                }
+            }
+        },
+        "models.SnapshotConfig": {
+            "type": "object",
+            "properties": {
+                "softLimit": {
+                    "description": "SoftLimit is the max number of packs, the order limit isn't part of the snapshot",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StorageStats": {
+            "type": "object",
+            "properties": {
+                "cache": {
+                    "$ref": "#/definitions/models.CacheStats"
+                },
+                "maxOrderPacks": {
+                    "description": "MaxOrderPacks is the max number of packs of a single order, 0 means no limit",
+                    "type": "integer"
+                },
+                "maxOrders": {
+                    "type": "integer"
+                },
+                "maxPacks": {
+                    "type": "integer"
+                },
+                "memoryBytes": {
+                    "description": "MemoryBytes is a rough estimate of the memory held by the packs, the orders, the pack history and the cache",
+                    "type": "integer"
+                },
+                "orderHistory": {
+                    "description": "OrderHistory tells whether the orders are recorded",
+                    "type": "boolean"
+                },
+                "orders": {
+                    "type": "integer"
+                },
+                "packs": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StrategyComparison": {
+            "type": "object",
+            "properties": {
+                "greedy": {
+                    "$ref": "#/definitions/models.StrategyResult"
+                },
+                "optimal": {
+                    "$ref": "#/definitions/models.StrategyResult"
+                },
+                "overpackReduction": {
+                    "type": "integer"
+                },
+                "packCountDifference": {
+                    "type": "integer"
+                },
+                "requestedItems": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StrategyResult": {
+            "type": "object",
+            "properties": {
+                "order": {
+                    "$ref": "#/definitions/models.Order"
+                },
+                "overpackPercent": {
+                    "type": "number"
+                },
+                "overpackedItems": {
+                    "type": "integer"
+                },
+                "packCount": {
+                    "type": "integer"
+                },
+                "strategy": {
+                    "type": "string"
+                },
+                "totalItems": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ValidationErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 }
             }
         }
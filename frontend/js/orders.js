@@ -10,6 +10,7 @@ const ordersModule = (function() {
     const overpackedItemsEl = document.getElementById('overpacked-items');
     const totalItemsEl = document.getElementById('total-items');
     const orderPacksList = document.getElementById('order-packs');
+    const orderWarningsList = document.getElementById('order-warnings');
     const ordersTableBody = document.getElementById('orders-table').querySelector('tbody');
 
     /**
@@ -108,9 +109,19 @@ const ordersModule = (function() {
         orderPacksList.innerHTML = '';
         order.packs.forEach(pack => {
             const li = document.createElement('li');
-            li.textContent = `${pack.quantity} x ${pack.pack.amount}`;
+            li.textContent = `${pack.quantity} x ${pack.pack.amount}` + (pack.pack.label ? ` (${pack.pack.label})` : '');
             orderPacksList.appendChild(li);
         });
+
+        // Clear and update warnings list, hidden if there are none
+        const warnings = order.warnings || [];
+        orderWarningsList.innerHTML = '';
+        warnings.forEach(warning => {
+            const li = document.createElement('li');
+            li.textContent = warning;
+            orderWarningsList.appendChild(li);
+        });
+        orderWarningsList.classList.toggle('hidden', warnings.length === 0);
         
         // Show the result
         orderResult.classList.remove('hidden');
@@ -1,20 +1,461 @@
 package models
 
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
 // Pack represents a package with a specific amount of items
 type Pack struct {
+	ID     int `json:"id,omitempty"`
 	Amount int `json:"amount"`
+	// Pinned packs can't be deleted until they are unpinned
+	Pinned bool `json:"pinned,omitempty"`
+	// Label is a human-readable name of the pack like "bulk pallet", the solver ignores it
+	Label string `json:"label,omitempty"`
 }
 
 // OrderPack represents a pack used in an order with its quantity
 type OrderPack struct {
 	Quantity int   `json:"quantity"`
 	Pack     *Pack `json:"pack"`
+	// Subtotal is the number of items in all packs of this size (quantity * amount)
+	Subtotal int `json:"subtotal"`
 }
 
 // Order represents a customer order with requested items and packing details
 type Order struct {
-	RequestedItems  int         `json:"requestedItems"`
-	OverpackedItems int         `json:"overpackedItems"`
-	TotalItems      int         `json:"totalItems"`
-	Packs           []OrderPack `json:"packs"`
+	// ID identifies a recorded order, it's assigned when the order is recorded
+	ID              int `json:"id,omitempty"`
+	RequestedItems  int `json:"requestedItems"`
+	OverpackedItems int `json:"overpackedItems"`
+	// OverpackPercent is OverpackedItems relative to RequestedItems in percent, rounded half up to 2 decimal places
+	// unless another rounding is configured
+	OverpackPercent float64 `json:"overpackPercent"`
+	TotalItems      int     `json:"totalItems"`
+	// UnfulfilledItems is the shortfall of the orders of the max-under strategy holding fewer items than requested
+	UnfulfilledItems int         `json:"unfulfilledItems,omitempty"`
+	Packs            []OrderPack `json:"packs"`
+	CreatedAt        time.Time   `json:"createdAt,omitzero"`
+	// Reference and Metadata are set by the client to correlate the order with its own systems
+	Reference string            `json:"reference,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	// Cartons lists every single pack of the order, it's only set on request with WithCartons
+	Cartons []Carton `json:"cartons,omitempty"`
+	// Warnings are advisories about the order, like an overpack above the configured threshold.
+	// Unlike the hard limits they don't reject the order.
+	Warnings []string `json:"warnings,omitempty"`
+	// AvailablePacks are the amounts of all the packs the order was packed from, largest first,
+	// so the order can be audited after the packs change
+	AvailablePacks []int `json:"availablePacks,omitempty"`
+}
+
+// Carton represents a single pack of an order, numbered from 1 in the order of the packs
+type Carton struct {
+	Number int `json:"number"`
+	PackID int `json:"packId"`
+	Amount int `json:"amount"`
+}
+
+// PackCount returns the total number of packs of the order
+func (o Order) PackCount() int {
+	count := 0
+	for _, op := range o.Packs {
+		count += op.Quantity
+	}
+	return count
+}
+
+// WithCartons returns the order with the cartons expanded from the quantities of its packs, e.g. 2x500 is two
+// cartons of 500. There are as many cartons as packs, so callers should bound PackCount first.
+func (o Order) WithCartons() Order {
+	cartons := make([]Carton, 0, o.PackCount())
+	for _, op := range o.Packs {
+		for range op.Quantity {
+			cartons = append(cartons, Carton{Number: len(cartons) + 1, PackID: op.Pack.ID, Amount: op.Pack.Amount})
+		}
+	}
+	o.Cartons = cartons
+	return o
+}
+
+// OrderTags represents the optional body of an order creation with the client's reference and metadata
+type OrderTags struct {
+	Reference string            `json:"reference,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// Limits of the order tags, so the recorded orders can't be used to store large amounts of data
+const (
+	MaxReferenceLength = 64
+	MaxMetadataEntries = 10
+	// MaxMetadataLength is the max length of a metadata key or value
+	MaxMetadataLength = 256
+)
+
+var (
+	errReferenceTooLong = fmt.Sprintf("must be at most %d characters", MaxReferenceLength)
+	errMetadataTooLarge = fmt.Sprintf("must have at most %d entries with keys and values of at most %d characters",
+		MaxMetadataEntries, MaxMetadataLength)
+)
+
+// FieldErrors checks the tags against their size limits and returns the reasons the fields are invalid by the name of
+// the field, nil if the tags are valid. Every transport creating orders checks the tags with it.
+func (t OrderTags) FieldErrors() map[string]string {
+	var errs map[string]string
+	add := func(field, reason string) {
+		if errs == nil {
+			errs = make(map[string]string)
+		}
+		if _, ok := errs[field]; !ok {
+			errs[field] = reason
+		}
+	}
+
+	if len(t.Reference) > MaxReferenceLength {
+		add("reference", errReferenceTooLong)
+	}
+	if len(t.Metadata) > MaxMetadataEntries {
+		add("metadata", errMetadataTooLarge)
+	}
+	for key, value := range t.Metadata {
+		if key == "" {
+			add("metadata", "keys must not be empty")
+			continue
+		}
+		if len(key) > MaxMetadataLength || len(value) > MaxMetadataLength {
+			add("metadata."+key, errMetadataTooLarge)
+		}
+	}
+	return errs
+}
+
+// MarshalJSON serializes the order with the packs always being an array, so clients don't have to check for null
+func (o Order) MarshalJSON() ([]byte, error) {
+	// order has the same fields without the methods to avoid recursion
+	type order Order
+	if o.Packs == nil {
+		o.Packs = []OrderPack{}
+	}
+	return json.Marshal(order(o))
+}
+
+// FlatOrderPack represents a pack used in an order by its amount instead of the nested pack
+type FlatOrderPack struct {
+	Amount   int `json:"amount"`
+	Quantity int `json:"quantity"`
+	Subtotal int `json:"subtotal"`
+}
+
+// FlatOrder represents an order with flat packs for the clients that only need the amounts and quantities
+type FlatOrder struct {
+	ID               int               `json:"id,omitempty"`
+	RequestedItems   int               `json:"requestedItems"`
+	OverpackedItems  int               `json:"overpackedItems"`
+	OverpackPercent  float64           `json:"overpackPercent"`
+	TotalItems       int               `json:"totalItems"`
+	UnfulfilledItems int               `json:"unfulfilledItems,omitempty"`
+	Packs            []FlatOrderPack   `json:"packs"`
+	CreatedAt        time.Time         `json:"createdAt,omitzero"`
+	Reference        string            `json:"reference,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+	Cartons          []Carton          `json:"cartons,omitempty"`
+	Warnings         []string          `json:"warnings,omitempty"`
+	AvailablePacks   []int             `json:"availablePacks,omitempty"`
+}
+
+// Flat returns the order with flat packs
+func (o Order) Flat() FlatOrder {
+	packs := make([]FlatOrderPack, len(o.Packs))
+	for i, pack := range o.Packs {
+		packs[i] = FlatOrderPack{Amount: pack.Pack.Amount, Quantity: pack.Quantity, Subtotal: pack.Subtotal}
+	}
+
+	return FlatOrder{
+		ID:               o.ID,
+		RequestedItems:   o.RequestedItems,
+		OverpackedItems:  o.OverpackedItems,
+		OverpackPercent:  o.OverpackPercent,
+		TotalItems:       o.TotalItems,
+		UnfulfilledItems: o.UnfulfilledItems,
+		Packs:            packs,
+		CreatedAt:        o.CreatedAt,
+		Reference:        o.Reference,
+		Metadata:         o.Metadata,
+		Cartons:          o.Cartons,
+		Warnings:         o.Warnings,
+		AvailablePacks:   o.AvailablePacks,
+	}
+}
+
+// Trace step actions
+const (
+	TraceFill     = "fill"
+	TraceTopUp    = "top-up"
+	TraceMerge    = "merge"
+	TraceOptimal  = "optimal"
+	TraceRequired = "required"
+	TraceDrop     = "drop"
+)
+
+// TraceStep represents a single step of an order computation.
+// Fill, top-up, optimal and required steps add Quantity packs of Amount, drop steps remove them, merge steps replace
+// FromQuantity packs of FromAmount with Quantity packs of Amount.
+type TraceStep struct {
+	Action       string `json:"action"`
+	Amount       int    `json:"amount"`
+	Quantity     int    `json:"quantity"`
+	FromAmount   int    `json:"fromAmount,omitempty"`
+	FromQuantity int    `json:"fromQuantity,omitempty"`
+}
+
+// TracedOrder represents an order together with the steps of its computation
+type TracedOrder struct {
+	Order Order       `json:"order"`
+	Trace []TraceStep `json:"trace"`
+}
+
+// FlatTracedOrder represents an order with flat packs together with the steps of its computation
+type FlatTracedOrder struct {
+	Order FlatOrder   `json:"order"`
+	Trace []TraceStep `json:"trace"`
+}
+
+// CalculateRequest represents a stateless order calculation request with its own pack amounts
+type CalculateRequest struct {
+	Packs []int `json:"packs"`
+	Items int   `json:"items"`
+}
+
+// Quote represents the totals of a packing without the pack breakdown
+type Quote struct {
+	RequestedItems  int `json:"requestedItems"`
+	OverpackedItems int `json:"overpackedItems"`
+	TotalItems      int `json:"totalItems"`
+	// UnfulfilledItems is the shortfall of the max-under strategy, see Order
+	UnfulfilledItems int `json:"unfulfilledItems,omitempty"`
+	PackCount        int `json:"packCount"`
+}
+
+// Return represents the packs to disassemble to take the returned items out of packed stock.
+// The packs hold at least the returned items, LooseItems are the items left over in the opened packs.
+type Return struct {
+	ReturnedItems     int         `json:"returnedItems"`
+	DisassembledItems int         `json:"disassembledItems"`
+	LooseItems        int         `json:"looseItems"`
+	Packs             []OrderPack `json:"packs"`
+}
+
+// BatchOrderResult represents the outcome of a single entry of a batch of orders.
+// Status is the HTTP status the entry would get as a single order, Order is set on success and Error otherwise.
+type BatchOrderResult struct {
+	Items  int    `json:"items"`
+	Status int    `json:"status"`
+	Order  *Order `json:"order,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// OrderSummary represents the totals over the recorded orders.
+// AverageOverpackPercent is the mean of the overpack percentages of the orders.
+type OrderSummary struct {
+	Orders                 int     `json:"orders"`
+	RequestedItems         int     `json:"requestedItems"`
+	ShippedItems           int     `json:"shippedItems"`
+	OverpackedItems        int     `json:"overpackedItems"`
+	AverageOverpackPercent float64 `json:"averageOverpackPercent"`
+}
+
+// PackRecommendation represents suggested new pack sizes reducing the overpack of the recorded orders
+type PackRecommendation struct {
+	Orders          int              `json:"orders"`
+	CurrentOverpack int              `json:"currentOverpack"`
+	Suggestions     []PackSuggestion `json:"suggestions"`
+}
+
+// PackSuggestion represents a suggested pack size.
+// Overpack is the cumulative overpack with this and all previous suggestions added.
+type PackSuggestion struct {
+	Amount    int `json:"amount"`
+	Overpack  int `json:"overpack"`
+	Reduction int `json:"reduction"`
+}
+
+// StrategyComparison represents the packings of the same request with the greedy and the min-overpack strategies.
+// OverpackReduction and PackCountDifference are how many fewer items and packs the optimal packing uses,
+// PackCountDifference is negative when it needs more packs.
+type StrategyComparison struct {
+	RequestedItems      int            `json:"requestedItems"`
+	Greedy              StrategyResult `json:"greedy"`
+	Optimal             StrategyResult `json:"optimal"`
+	OverpackReduction   int            `json:"overpackReduction"`
+	PackCountDifference int            `json:"packCountDifference"`
+}
+
+// StrategyResult represents the packing of a single strategy in a comparison
+type StrategyResult struct {
+	Strategy        string  `json:"strategy"`
+	OverpackedItems int     `json:"overpackedItems"`
+	OverpackPercent float64 `json:"overpackPercent"`
+	TotalItems      int     `json:"totalItems"`
+	PackCount       int     `json:"packCount"`
+	Order           Order   `json:"order"`
+}
+
+// Snapshot represents the full state of the storage used for backups and restores
+type Snapshot struct {
+	Packs  []*Pack        `json:"packs"`
+	Orders []Order        `json:"orders"`
+	Config SnapshotConfig `json:"config"`
+}
+
+// SnapshotConfig represents the storage configuration included in a snapshot
+type SnapshotConfig struct {
+	// SoftLimit is the max number of packs, the order limit isn't part of the snapshot
+	SoftLimit int `json:"softLimit"`
+}
+
+// PackConfig represents the pack configuration with the limits and the strategy, exported and imported as JSON or
+// YAML to keep it in a repository
+type PackConfig struct {
+	SoftLimit       int          `json:"softLimit" yaml:"softLimit"`
+	DefaultStrategy string       `json:"defaultStrategy" yaml:"defaultStrategy"`
+	Packs           []ConfigPack `json:"packs" yaml:"packs"`
+}
+
+// ConfigPack represents a pack of a PackConfig, identified by its amount
+type ConfigPack struct {
+	Amount int    `json:"amount" yaml:"amount"`
+	Label  string `json:"label,omitempty" yaml:"label,omitempty"`
+	Pinned bool   `json:"pinned,omitempty" yaml:"pinned,omitempty"`
+}
+
+// Pack import statuses
+const (
+	ImportAdded              = "added"
+	ImportDuplicateInRequest = "skipped-duplicate-in-request"
+	ImportAlreadyExists      = "skipped-already-exists"
+	ImportRejectedLimit      = "rejected-limit"
+	ImportRejectedInvalid    = "rejected-invalid"
+)
+
+// Pack configuration violations
+const (
+	ViolationNotPositive   = "not-positive"
+	ViolationDuplicate     = "duplicate"
+	ViolationLimitExceeded = "limit-exceeded"
+	ViolationTooSmall      = "too-small"
+	ViolationNotMultiple   = "not-multiple"
+)
+
+// PackViolation represents a problem of a proposed pack configuration.
+// Index is the position of the offending amount, -1 for problems of the configuration as a whole.
+type PackViolation struct {
+	Index   int    `json:"index"`
+	Amount  int    `json:"amount,omitempty"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// PackValidation represents the result of validating a proposed pack configuration
+type PackValidation struct {
+	Valid      bool            `json:"valid"`
+	Violations []PackViolation `json:"violations"`
+}
+
+// APIError represents an error response. RequestID identifies the request in the logs for unexpected failures.
+type APIError struct {
+	Error     string `json:"error"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// ValidationErrorResponse represents an invalid request body with the reason of every invalid field,
+// e.g. {"items": "must be positive"}. Errors about the body as a whole are under "body".
+type ValidationErrorResponse struct {
+	Error  string            `json:"error"`
+	Errors map[string]string `json:"errors"`
+}
+
+// LimitErrorResponse represents an error caused by a reached limit, with the current count and the limit
+type LimitErrorResponse struct {
+	Error string `json:"error"`
+	Count int    `json:"count"`
+	Limit int    `json:"limit"`
+}
+
+// PackImportResult represents the outcome of importing a single pack amount.
+// ID is set for added packs and packs that already exist.
+type PackImportResult struct {
+	Amount int    `json:"amount"`
+	Status string `json:"status"`
+	ID     int    `json:"id,omitempty"`
+}
+
+// Pack change actions
+const (
+	PackAdded    = "add"
+	PackUpdated  = "update"
+	PackDeleted  = "delete"
+	PackPinned   = "pin"
+	PackUnpinned = "unpin"
+	PackLabeled  = "label"
+)
+
+// PackChange represents a single change of the pack configuration
+type PackChange struct {
+	Action    string `json:"action"`
+	PackID    int    `json:"packId"`
+	OldAmount int    `json:"oldAmount,omitempty"`
+	NewAmount int    `json:"newAmount,omitempty"`
+	// Version is the version of the pack set after the change
+	Version   int       `json:"version"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// RecomputeSummary represents the outcome of packing the recorded orders again with the current packs
+type RecomputeSummary struct {
+	// Orders is the number of recorded orders when the recomputation started
+	Orders int `json:"orders"`
+	// Changed is the number of orders packed differently than before
+	Changed int `json:"changed"`
+	// Failed is the number of orders that can't be packed anymore, they are left unchanged
+	Failed int `json:"failed"`
+	// OverpackDelta is the change of the total overpack of the changed orders, negative if it went down
+	OverpackDelta int `json:"overpackDelta"`
+}
+
+// StorageStats represents the current usage and limits of the storage for debugging
+type StorageStats struct {
+	Packs     int `json:"packs"`
+	Orders    int `json:"orders"`
+	MaxPacks  int `json:"maxPacks"`
+	MaxOrders int `json:"maxOrders"`
+	// MaxOrderPacks is the max number of packs of a single order, 0 means no limit
+	MaxOrderPacks int `json:"maxOrderPacks"`
+	// OrderHistory tells whether the orders are recorded
+	OrderHistory bool `json:"orderHistory"`
+	// MemoryBytes is a rough estimate of the memory held by the packs, the orders, the pack history and the cache
+	MemoryBytes int        `json:"memoryBytes"`
+	Cache       CacheStats `json:"cache"`
+}
+
+// CacheStats represents the usage of the cache of computed orders
+type CacheStats struct {
+	Entries  int   `json:"entries"`
+	Capacity int   `json:"capacity"`
+	Hits     int64 `json:"hits"`
+	Misses   int64 `json:"misses"`
+	// HitRatePercent is the share of the lookups finding a cached order, 0 without lookups
+	HitRatePercent float64 `json:"hitRatePercent"`
+}
+
+// PackFit describes how the packs relate to a number of items without computing an order
+type PackFit struct {
+	RequestedItems int `json:"requestedItems"`
+	// ExactFit tells whether some packing holds exactly the requested items
+	ExactFit bool `json:"exactFit"`
+	// LargestFitting is the largest pack holding at most the requested items, omitted if every pack is larger
+	LargestFitting *Pack `json:"largestFitting,omitempty"`
+	// SmallestCovering is the smallest pack holding at least the requested items, omitted if every pack is smaller
+	SmallestCovering *Pack `json:"smallestCovering,omitempty"`
 }
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderJSON(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Nil and empty packs serialize the same way
+	for _, packs := range [][]OrderPack{nil, {}} {
+		data, err := json.Marshal(Order{Packs: packs, CreatedAt: createdAt})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"requestedItems":0,"overpackedItems":0,"overpackPercent":0,"totalItems":0,"packs":[],"createdAt":"2025-01-01T12:00:00Z"}`, string(data))
+	}
+
+	// The fields keep the order of the struct
+	data, err := json.Marshal(Order{
+		RequestedItems:  1,
+		OverpackedItems: 249,
+		OverpackPercent: 24900,
+		TotalItems:      250,
+		Packs:           []OrderPack{{Quantity: 1, Pack: &Pack{ID: 1, Amount: 250}, Subtotal: 250}},
+		CreatedAt:       createdAt,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"requestedItems":1,"overpackedItems":249,"overpackPercent":24900,"totalItems":250,`+
+		`"packs":[{"quantity":1,"pack":{"id":1,"amount":250},"subtotal":250}],"createdAt":"2025-01-01T12:00:00Z"}`, string(data))
+
+	// Pointers and slices of orders use the same encoding
+	data, err = json.Marshal([]*Order{{}})
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"packs":[]`)
+
+	// Unrecorded orders like /calculate results have no timestamp and their packs no ID
+	data, err = json.Marshal(Order{Packs: []OrderPack{{Quantity: 1, Pack: &Pack{Amount: 250}, Subtotal: 250}}})
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), `"createdAt"`)
+	assert.NotContains(t, string(data), `"id"`)
+	data, err = json.Marshal(Order{}.Flat())
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), `"createdAt"`)
+}
+
+func TestFlatOrderJSON(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	data, err := json.Marshal(Order{
+		RequestedItems:  501,
+		OverpackedItems: 249,
+		OverpackPercent: 49.7,
+		TotalItems:      750,
+		Packs: []OrderPack{
+			{Quantity: 1, Pack: &Pack{ID: 2, Amount: 500}, Subtotal: 500},
+			{Quantity: 1, Pack: &Pack{ID: 1, Amount: 250}, Subtotal: 250},
+		},
+		CreatedAt: createdAt,
+	}.Flat())
+	assert.NoError(t, err)
+	assert.Equal(t, `{"requestedItems":501,"overpackedItems":249,"overpackPercent":49.7,"totalItems":750,`+
+		`"packs":[{"amount":500,"quantity":1,"subtotal":500},{"amount":250,"quantity":1,"subtotal":250}],`+
+		`"createdAt":"2025-01-01T12:00:00Z"}`, string(data))
+
+	// Orders without packs have an empty array too
+	data, err = json.Marshal(Order{}.Flat())
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"packs":[]`)
+
+	data, err = json.Marshal(Order{Warnings: []string{"overpack of 249 items exceeds 200 items"}}.Flat())
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"warnings":["overpack of 249 items exceeds 200 items"]`)
+
+	data, err = json.Marshal(Order{AvailablePacks: []int{500, 250}}.Flat())
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"availablePacks":[500,250]`)
+}
+
+func TestOrderWithCartons(t *testing.T) {
+	order := Order{
+		Packs: []OrderPack{
+			{Quantity: 2, Pack: &Pack{ID: 2, Amount: 500}, Subtotal: 1000},
+			{Quantity: 1, Pack: &Pack{ID: 1, Amount: 250}, Subtotal: 250},
+		},
+	}
+	assert.Equal(t, 3, order.PackCount())
+
+	withCartons := order.WithCartons()
+	assert.Equal(t, []Carton{
+		{Number: 1, PackID: 2, Amount: 500},
+		{Number: 2, PackID: 2, Amount: 500},
+		{Number: 3, PackID: 1, Amount: 250},
+	}, withCartons.Cartons)
+	assert.Equal(t, withCartons.Cartons, withCartons.Flat().Cartons)
+
+	// The compact form is unchanged and doesn't list cartons
+	assert.Nil(t, order.Cartons)
+	data, err := json.Marshal(order)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "cartons")
+}
+
+func TestOrderTagsFieldErrors(t *testing.T) {
+	assert.Nil(t, OrderTags{}.FieldErrors())
+	assert.Nil(t, OrderTags{Reference: "PO-1", Metadata: map[string]string{"source": "web"}}.FieldErrors())
+
+	errs := OrderTags{
+		Reference: strings.Repeat("x", MaxReferenceLength+1),
+		Metadata:  map[string]string{"": "empty", "note": strings.Repeat("x", MaxMetadataLength+1)},
+	}.FieldErrors()
+	assert.Equal(t, map[string]string{
+		"reference":     "must be at most 64 characters",
+		"metadata":      "keys must not be empty",
+		"metadata.note": "must have at most 10 entries with keys and values of at most 256 characters",
+	}, errs)
+}
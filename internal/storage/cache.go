@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/packing"
+)
+
+// orderCacheSize is the max number of computed orders kept in the cache
+const orderCacheSize = 256
+
+// orderCacheKey identifies a computation: the same pack set version, items and options always produce the same order
+type orderCacheKey struct {
+	version        int
+	requestedItems int
+	options        packing.Key
+}
+
+// orderCache memoizes computed orders for the current pack set version.
+// Entries of older versions can never be hit again, so the whole cache is dropped when the version changes.
+type orderCache struct {
+	mu      sync.Mutex
+	version int
+	entries map[orderCacheKey]models.Order
+	// keys holds the cached keys in insertion order to evict the oldest entry when the cache is full
+	keys []orderCacheKey
+	// hits and misses count the lookups since the cache was created
+	hits   int64
+	misses int64
+}
+
+func newOrderCache() *orderCache {
+	return &orderCache{entries: make(map[orderCacheKey]models.Order)}
+}
+
+func (c *orderCache) get(key orderCacheKey) (models.Order, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	order, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return models.Order{}, false
+	}
+	c.hits++
+
+	return copyOrder(order), true
+}
+
+// stats returns the usage of the cache with the hit rate rounded with the rounding
+func (c *orderCache) stats(rounding packing.Rounding) models.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := models.CacheStats{
+		Entries:  len(c.entries),
+		Capacity: orderCacheSize,
+		Hits:     c.hits,
+		Misses:   c.misses,
+	}
+	if lookups := c.hits + c.misses; lookups > 0 {
+		stats.HitRatePercent = rounding.Percent(int(c.hits), int(lookups))
+	}
+
+	return stats
+}
+
+func (c *orderCache) put(key orderCacheKey, order models.Order) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// A computation started before a pack change may finish after a newer one, don't let it reset the cache
+	if key.version < c.version {
+		return
+	}
+	if key.version > c.version {
+		c.version = key.version
+		c.entries = make(map[orderCacheKey]models.Order)
+		c.keys = c.keys[:0]
+	}
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+
+	if len(c.keys) >= orderCacheSize {
+		delete(c.entries, c.keys[0])
+		c.keys = c.keys[1:]
+	}
+	c.entries[key] = copyOrder(order)
+	c.keys = append(c.keys, key)
+}
+
+// memory estimates the bytes held by the cached orders
+func (c *orderCache) memory() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bytes := 0
+	for _, order := range c.entries {
+		bytes += orderMemory(order)
+	}
+	return bytes
+}
+
+// copyOrder copies the slices and the metadata of the order so the cached entry isn't shared with the callers.
+// The packs referenced by the order packs are the immutable solver packs and aren't copied.
+func copyOrder(order models.Order) models.Order {
+	packs := make([]models.OrderPack, len(order.Packs))
+	copy(packs, order.Packs)
+	order.Packs = packs
+	order.AvailablePacks = slices.Clone(order.AvailablePacks)
+	order.Metadata = maps.Clone(order.Metadata)
+	order.Cartons = slices.Clone(order.Cartons)
+	order.Warnings = slices.Clone(order.Warnings)
+
+	return order
+}
@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/packing"
+	"github.com/stretchr/testify/assert"
+)
+
+// countSolves replaces the solver of the storage with one counting its calls
+func countSolves(storage *PackStorage) *int {
+	calls := 0
+	storage.solve = func(ctx context.Context, packs []*models.Pack, requestedItems int, opts ...packing.Option) (models.Order, error) {
+		calls++
+		return packing.SolveContext(ctx, packs, requestedItems, opts...)
+	}
+	return &calls
+}
+
+func TestCalculateOrderCache(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 500)
+	calls := countSolves(storage)
+
+	first, err := storage.CalculateOrder(t.Context(), 501)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *calls)
+
+	second, err := storage.CalculateOrder(t.Context(), 501)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *calls)
+	assert.Equal(t, first.Packs, second.Packs)
+	assert.Len(t, storage.GetOrders(t.Context()), 2)
+
+	// Quotes share the cache
+	_, err = storage.QuoteOrder(t.Context(), 501)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *calls)
+
+	// Different options are cached separately
+	_, err = storage.CalculateOrder(t.Context(), 501, packing.WithFillerPack(500))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, *calls)
+	_, err = storage.CalculateOrder(t.Context(), 501, packing.WithFillerPack(500))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, *calls)
+
+	// Modifying a returned order doesn't affect the cache
+	second.Packs[0].Quantity = 100
+	second.AvailablePacks[0] = 1
+	third, _ := storage.CalculateOrder(t.Context(), 501)
+	assert.Equal(t, first.Packs, third.Packs)
+	assert.Equal(t, []int{500, 250}, third.AvailablePacks)
+}
+
+func TestCachedOrderCopies(t *testing.T) {
+	order := models.Order{
+		Packs:          []models.OrderPack{{Quantity: 1, Subtotal: 250}},
+		AvailablePacks: []int{250},
+		Metadata:       map[string]string{"source": "test"},
+		Cartons:        []models.Carton{{Number: 1, Amount: 250}},
+		Warnings:       make([]string, 1, 2),
+	}
+	cached := copyOrder(order)
+
+	// Modifying the original, including appending within the capacity, leaves the copy as it was
+	order.Packs[0].Quantity = 2
+	order.AvailablePacks[0] = 500
+	order.Metadata["source"] = "changed"
+	order.Cartons[0].Amount = 500
+	_ = append(order.Warnings[:1], "appended")
+	order.Warnings[0] = "changed"
+
+	assert.Equal(t, models.Order{
+		Packs:          []models.OrderPack{{Quantity: 1, Subtotal: 250}},
+		AvailablePacks: []int{250},
+		Metadata:       map[string]string{"source": "test"},
+		Cartons:        []models.Carton{{Number: 1, Amount: 250}},
+		Warnings:       []string{""},
+	}, cached)
+}
+
+func TestCalculateOrderCacheInvalidation(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 500)
+	calls := countSolves(storage)
+
+	order, _ := storage.CalculateOrder(t.Context(), 251)
+	assert.Equal(t, 500, order.TotalItems)
+
+	_, _ = storage.UpdatePack(t.Context(), 250, 100)
+	order, _ = storage.CalculateOrder(t.Context(), 251)
+	assert.Equal(t, 2, *calls)
+	assert.Equal(t, 300, order.TotalItems)
+
+	_ = storage.DeletePack(t.Context(), 100)
+	order, _ = storage.CalculateOrder(t.Context(), 251)
+	assert.Equal(t, 3, *calls)
+	assert.Equal(t, 500, order.TotalItems)
+
+	// Errors aren't cached
+	_ = storage.DeletePack(t.Context(), 500)
+	_, err := storage.CalculateOrder(t.Context(), 251)
+	assert.ErrorIs(t, err, ErrNoPacksAvailable)
+	_, _ = storage.CalculateOrder(t.Context(), 251)
+	assert.Equal(t, 5, *calls)
+}
+
+func TestOrderCacheBounded(t *testing.T) {
+	cache := newOrderCache()
+
+	for i := 1; i <= orderCacheSize+10; i++ {
+		cache.put(orderCacheKey{version: 1, requestedItems: i}, models.Order{RequestedItems: i})
+	}
+	assert.Len(t, cache.entries, orderCacheSize)
+	assert.Len(t, cache.keys, orderCacheSize)
+
+	// The oldest entries are evicted first
+	_, ok := cache.get(orderCacheKey{version: 1, requestedItems: 1})
+	assert.False(t, ok)
+	_, ok = cache.get(orderCacheKey{version: 1, requestedItems: orderCacheSize + 10})
+	assert.True(t, ok)
+
+	// A newer version drops the older entries, stale results are ignored
+	cache.put(orderCacheKey{version: 2, requestedItems: 1}, models.Order{RequestedItems: 1})
+	assert.Len(t, cache.entries, 1)
+	cache.put(orderCacheKey{version: 1, requestedItems: 2}, models.Order{RequestedItems: 2})
+	assert.Len(t, cache.entries, 1)
+}
+
+func TestCalculateOrderTraceBypassesCache(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+	calls := countSolves(storage)
+
+	_, _ = storage.CalculateOrder(t.Context(), 300)
+	var trace packing.Trace
+	_, _ = storage.CalculateOrder(t.Context(), 300, packing.WithTrace(&trace))
+	assert.Equal(t, 2, *calls)
+	assert.NotEmpty(t, trace.Steps)
+
+	// Traced results aren't cached either
+	_, _ = storage.CalculateOrder(t.Context(), 300, packing.WithTrace(&packing.Trace{}))
+	assert.Equal(t, 3, *calls)
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/packing"
+)
+
+// OrderCandidates returns up to count candidate orders for the requested items against the current packs, best first,
+// without recording them. The candidates needing more packs than allowed are left out. See packing.CandidatesContext.
+// It fails with ErrComputationTimeout if the context is done before the candidates are computed.
+func (s *PackStorage) OrderCandidates(ctx context.Context, requestedItems, count int, opts ...packing.Option) ([]models.Order, error) {
+	opts = s.orderOptions(opts)
+
+	s.mu.RLock()
+	// The solver doesn't modify the packs, so there's no need to copy them
+	packs := s.solverPacks
+	s.mu.RUnlock()
+
+	if s.computeTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.computeTimeout)
+		defer cancel()
+	}
+
+	orders, err := packing.CandidatesContext(ctx, packs, requestedItems, count, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]models.Order, 0, len(orders))
+	available := packAmounts(packs)
+	var packsErr error
+	for _, order := range orders {
+		order.AvailablePacks = available
+		if err := s.checkOrderPacks(order); err != nil {
+			packsErr = err
+			continue
+		}
+		candidates = append(candidates, s.addWarnings(order))
+	}
+	if len(candidates) == 0 {
+		return nil, packsErr
+	}
+
+	return candidates, nil
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/packing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderCandidates(t *testing.T) {
+	storage := NewPackStorage(WithDefaultStrategy(packing.StrategyMinOverpack), WithOverpackItemsWarning(1))
+	_, err := storage.OrderCandidates(t.Context(), 11, 3)
+	assert.ErrorIs(t, err, ErrNoPacksAvailable)
+
+	for _, amount := range []int{4, 6, 9} {
+		_ = storage.AddPack(t.Context(), amount)
+	}
+
+	// The default strategy applies, 6+6 is the best
+	candidates, err := storage.OrderCandidates(t.Context(), 11, 3)
+	assert.NoError(t, err)
+	assert.Len(t, candidates, 3)
+	var totals []int
+	for _, order := range candidates {
+		totals = append(totals, order.TotalItems)
+	}
+	assert.Equal(t, []int{12, 13, 14}, totals)
+	assert.Nil(t, candidates[0].Warnings)
+	assert.Equal(t, []string{"overpack of 2 items exceeds 1 items"}, candidates[1].Warnings)
+
+	// Candidates aren't recorded
+	assert.Empty(t, storage.GetOrders(t.Context()))
+}
+
+func TestOrderCandidatesMaxOrderPacks(t *testing.T) {
+	storage := NewPackStorage(WithMaxOrderPacks(2))
+	_ = storage.AddPack(t.Context(), 250)
+
+	// 500 and 750 need too many packs
+	candidates, err := storage.OrderCandidates(t.Context(), 251, 3)
+	assert.NoError(t, err)
+	assert.Len(t, candidates, 1)
+	assert.Equal(t, 500, candidates[0].TotalItems)
+
+	_, err = storage.OrderCandidates(t.Context(), 501, 3)
+	assert.ErrorIs(t, err, ErrTooManyOrderPacks)
+}
+
+func TestOrderCandidatesCancelled(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	_, err := storage.OrderCandidates(ctx, 251, 3)
+	assert.ErrorIs(t, err, ErrComputationTimeout)
+	assert.ErrorIs(t, err, context.Canceled)
+}
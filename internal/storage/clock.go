@@ -0,0 +1,15 @@
+package storage
+
+import "time"
+
+// Clock tells the current time. The storage reads the time only through it, so tests can control it.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the Clock reading the wall clock
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/packing"
+)
+
+// CompareStrategies packs the requested items with both the greedy and the min-overpack strategies against the
+// current packs without recording orders. The strategies override the one selected by the options.
+func (s *PackStorage) CompareStrategies(ctx context.Context, requestedItems int, opts ...packing.Option) (models.StrategyComparison, error) {
+	greedy, err := s.strategyResult(ctx, packing.StrategyGreedy, requestedItems, opts)
+	if err != nil {
+		return models.StrategyComparison{}, err
+	}
+	optimal, err := s.strategyResult(ctx, packing.StrategyMinOverpack, requestedItems, opts)
+	if err != nil {
+		return models.StrategyComparison{}, err
+	}
+
+	return models.StrategyComparison{
+		RequestedItems:      requestedItems,
+		Greedy:              greedy,
+		Optimal:             optimal,
+		OverpackReduction:   greedy.OverpackedItems - optimal.OverpackedItems,
+		PackCountDifference: greedy.PackCount - optimal.PackCount,
+	}, nil
+}
+
+func (s *PackStorage) strategyResult(ctx context.Context, strategy packing.Strategy, requestedItems int, opts []packing.Option) (models.StrategyResult, error) {
+	// The strategy goes last to override the one of the options
+	opts = append(opts[:len(opts):len(opts)], packing.WithStrategy(strategy))
+	order, err := s.computeOrder(ctx, requestedItems, opts)
+	if err != nil {
+		return models.StrategyResult{}, err
+	}
+
+	return models.StrategyResult{
+		Strategy:        string(strategy),
+		OverpackedItems: order.OverpackedItems,
+		OverpackPercent: order.OverpackPercent,
+		TotalItems:      order.TotalItems,
+		PackCount:       order.PackCount(),
+		Order:           order,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/packing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareStrategies(t *testing.T) {
+	storage := NewPackStorage()
+
+	_, err := storage.CompareStrategies(t.Context(), 11)
+	assert.ErrorIs(t, err, ErrNoPacksAvailable)
+
+	for _, amount := range []int{4, 6, 9} {
+		_ = storage.AddPack(t.Context(), amount)
+	}
+
+	// Greedy packs 9+4, min-overpack 6+6
+	comparison, err := storage.CompareStrategies(t.Context(), 11, packing.WithStrategy(packing.StrategyGreedy))
+	assert.NoError(t, err)
+	assert.Equal(t, 11, comparison.RequestedItems)
+	assert.Equal(t, "greedy", comparison.Greedy.Strategy)
+	assert.Equal(t, 13, comparison.Greedy.TotalItems)
+	assert.Equal(t, 2, comparison.Greedy.OverpackedItems)
+	assert.Equal(t, 2, comparison.Greedy.PackCount)
+	assert.Equal(t, "min-overpack", comparison.Optimal.Strategy)
+	assert.Equal(t, 12, comparison.Optimal.TotalItems)
+	assert.Equal(t, 1, comparison.Optimal.OverpackedItems)
+	assert.Equal(t, 2, comparison.Optimal.PackCount)
+	assert.Len(t, comparison.Optimal.Order.Packs, 1)
+	assert.Equal(t, 1, comparison.OverpackReduction)
+	assert.Equal(t, 0, comparison.PackCountDifference)
+
+	// Comparisons don't record orders
+	assert.Empty(t, storage.GetOrders(t.Context()))
+}
+
+func TestCompareStrategiesWithOptions(t *testing.T) {
+	storage := NewPackStorage()
+	for _, amount := range []int{4, 6, 9} {
+		_ = storage.AddPack(t.Context(), amount)
+	}
+
+	// Both strategies get the other options
+	_, err := storage.CompareStrategies(t.Context(), 11, packing.WithMaxOverpack(0))
+	assert.ErrorIs(t, err, packing.ErrOverpackExceeded)
+
+	comparison, err := storage.CompareStrategies(t.Context(), 18, packing.WithMaxOverpack(0))
+	assert.NoError(t, err)
+	assert.Zero(t, comparison.OverpackReduction)
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConcurrentReadersAndWriters is meant to be run with -race
+func TestConcurrentReadersAndWriters(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 500)
+
+	const iterations = 200
+	var wg sync.WaitGroup
+
+	// Writers
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			_ = storage.AddPack(t.Context(), 1000+i%5)
+			_ = storage.DeletePack(t.Context(), 1000+(i+2)%5)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			_, _ = storage.UpdatePack(t.Context(), 500, 600)
+			_, _ = storage.UpdatePack(t.Context(), 600, 500)
+		}
+	}()
+
+	// Readers
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				packs := storage.GetPacks(t.Context())
+				for j := 1; j < len(packs); j++ {
+					assert.Greater(t, packs[j-1].Amount, packs[j].Amount)
+				}
+
+				order, err := storage.CalculateOrder(t.Context(), 1+i*7)
+				assert.NoError(t, err)
+				assert.GreaterOrEqual(t, order.TotalItems, order.RequestedItems)
+
+				_ = storage.GetOrders(t.Context())
+				_ = storage.GetPackHistory(t.Context())
+			}
+		}()
+	}
+
+	wg.Wait()
+	assert.LessOrEqual(t, len(storage.GetOrders(t.Context())), SoftLimit)
+}
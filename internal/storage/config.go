@@ -0,0 +1,218 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/packing"
+)
+
+// Config holds the tunables of a PackStorage. Zero values fall back to the defaults.
+type Config struct {
+	// MaxPacks is the max number of packs, SoftLimit by default
+	MaxPacks int
+	// MaxOrders is the max number of recorded orders, the oldest ones are dropped beyond it. SoftLimit by default
+	MaxOrders int
+	// Retention is the max age of the recorded orders, 0 means orders are only limited by count
+	Retention time.Duration
+	// Clock tells the current time, the wall clock by default
+	Clock Clock
+	// DefaultStrategy is used for the orders that don't select a strategy, packing.StrategyGreedy by default
+	DefaultStrategy packing.Strategy
+	// ComputeTimeout bounds the computation of a single order, 0 means no limit besides the context of the call
+	ComputeTimeout time.Duration
+	// PackMultiple restricts the pack amounts to its multiples, 0 means any positive amount
+	PackMultiple int
+	// MinPackAmount rejects smaller pack amounts, 0 means any positive amount
+	MinPackAmount int
+	// MaxOrderPacks is the max number of packs of a single order, 0 means no limit
+	MaxOrderPacks int
+	// DisableOrderHistory computes the orders without recording them
+	DisableOrderHistory bool
+	// WarnOverpackPercent adds a warning to the orders overpacking more than this percentage, 0 means no warning
+	WarnOverpackPercent float64
+	// WarnOverpackItems adds a warning to the orders overpacking more than this number of items, 0 means no warning
+	WarnOverpackItems int
+	// PercentRounding rounds the overpack percent of the orders and the percentages of the stats,
+	// packing.DefaultRounding by default
+	PercentRounding packing.Rounding
+}
+
+// Option configures a PackStorage
+type Option func(*Config)
+
+// WithConfig replaces the whole configuration, the options following it can still override single fields
+func WithConfig(config Config) Option {
+	return func(c *Config) {
+		*c = config
+	}
+}
+
+// WithMaxPacks sets the max number of packs
+func WithMaxPacks(limit int) Option {
+	return func(c *Config) {
+		c.MaxPacks = limit
+	}
+}
+
+// WithMaxOrders sets the max number of recorded orders
+func WithMaxOrders(limit int) Option {
+	return func(c *Config) {
+		c.MaxOrders = limit
+	}
+}
+
+// WithOrderRetention sets the max age of the recorded orders
+func WithOrderRetention(retention time.Duration) Option {
+	return func(c *Config) {
+		c.Retention = retention
+	}
+}
+
+// WithClock sets the clock used for the timestamps and the retention
+func WithClock(clock Clock) Option {
+	return func(c *Config) {
+		c.Clock = clock
+	}
+}
+
+// WithDefaultStrategy sets the strategy used for the orders that don't select one
+func WithDefaultStrategy(strategy packing.Strategy) Option {
+	return func(c *Config) {
+		c.DefaultStrategy = strategy
+	}
+}
+
+// WithComputeTimeout aborts the computations of orders taking longer than the timeout with ErrComputationTimeout
+func WithComputeTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.ComputeTimeout = timeout
+	}
+}
+
+// WithPackMultiple rejects pack amounts that aren't multiples of the specified amount with ErrInvalidPackMultiple
+func WithPackMultiple(multiple int) Option {
+	return func(c *Config) {
+		c.PackMultiple = multiple
+	}
+}
+
+// WithMinPackAmount rejects pack amounts below the specified amount with ErrAmountTooSmall
+func WithMinPackAmount(amount int) Option {
+	return func(c *Config) {
+		c.MinPackAmount = amount
+	}
+}
+
+// WithMaxOrderPacks rejects the orders needing more packs than the limit with ErrTooManyOrderPacks
+func WithMaxOrderPacks(limit int) Option {
+	return func(c *Config) {
+		c.MaxOrderPacks = limit
+	}
+}
+
+// WithoutOrderHistory computes the orders without recording them, for deployments only calculating packings
+func WithoutOrderHistory() Option {
+	return func(c *Config) {
+		c.DisableOrderHistory = true
+	}
+}
+
+// WithOverpackPercentWarning adds a warning to the orders overpacking more than the percentage of the requested items
+// without rejecting them
+func WithOverpackPercentWarning(percent float64) Option {
+	return func(c *Config) {
+		c.WarnOverpackPercent = percent
+	}
+}
+
+// WithOverpackItemsWarning adds a warning to the orders overpacking more than the number of items without rejecting them
+func WithOverpackItemsWarning(items int) Option {
+	return func(c *Config) {
+		c.WarnOverpackItems = items
+	}
+}
+
+// WithPercentRounding rounds the percentages of the orders and the stats with the rounding
+func WithPercentRounding(rounding packing.Rounding) Option {
+	return func(c *Config) {
+		c.PercentRounding = rounding
+	}
+}
+
+func newConfig(opts []Option) Config {
+	var c Config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.Clock == nil {
+		c.Clock = systemClock{}
+	}
+	if c.DefaultStrategy == "" {
+		c.DefaultStrategy = packing.StrategyGreedy
+	}
+	if c.PercentRounding == (packing.Rounding{}) {
+		c.PercentRounding = packing.DefaultRounding
+	}
+	return c
+}
+
+// packLimit returns the max number of packs
+func (s *PackStorage) packLimit() int {
+	if s.maxPacks > 0 {
+		return s.maxPacks
+	}
+	return SoftLimit
+}
+
+// orderLimit returns the max number of recorded orders
+func (s *PackStorage) orderLimit() int {
+	if s.maxOrders > 0 {
+		return s.maxOrders
+	}
+	return SoftLimit
+}
+
+// orderOptions prepends the configured defaults to the options of a computation,
+// so the strategy selected by the options overrides the default strategy
+func (s *PackStorage) orderOptions(opts []packing.Option) []packing.Option {
+	defaults := []packing.Option{packing.WithRounding(s.rounding)}
+	if s.defaultStrategy != packing.StrategyGreedy {
+		defaults = append(defaults, packing.WithStrategy(s.defaultStrategy))
+	}
+	return append(defaults, opts...)
+}
+
+// addWarnings returns the order with a warning for every configured threshold it exceeds
+func (s *PackStorage) addWarnings(order models.Order) models.Order {
+	if s.warnOverpackPercent > 0 && order.OverpackPercent > s.warnOverpackPercent {
+		order.Warnings = append(order.Warnings,
+			fmt.Sprintf("overpack of %g%% exceeds %g%%", order.OverpackPercent, s.warnOverpackPercent))
+	}
+	if s.warnOverpackItems > 0 && order.OverpackedItems > s.warnOverpackItems {
+		order.Warnings = append(order.Warnings,
+			fmt.Sprintf("overpack of %d items exceeds %d items", order.OverpackedItems, s.warnOverpackItems))
+	}
+	return order
+}
+
+// checkPackAmount returns a MinAmountError if the amount is below the configured minimum pack amount
+// and a MultipleError if it isn't a multiple of the configured pack multiple
+func (s *PackStorage) checkPackAmount(amount int) error {
+	if amount < s.minPackAmount {
+		return &MinAmountError{Amount: amount, Min: s.minPackAmount}
+	}
+	if s.packMultiple > 0 && amount%s.packMultiple != 0 {
+		return &MultipleError{Amount: amount, Multiple: s.packMultiple}
+	}
+	return nil
+}
+
+// checkOrderPacks returns an OrderPacksError if the order needs more packs than the configured maximum
+func (s *PackStorage) checkOrderPacks(order models.Order) error {
+	if count := order.PackCount(); s.maxOrderPacks > 0 && count > s.maxOrderPacks {
+		return &OrderPacksError{Count: count, Limit: s.maxOrderPacks}
+	}
+	return nil
+}
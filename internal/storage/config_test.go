@@ -0,0 +1,283 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/packing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPackStorageDefaults(t *testing.T) {
+	storage := NewPackStorage()
+	assert.Equal(t, SoftLimit, storage.packLimit())
+	assert.Equal(t, SoftLimit, storage.orderLimit())
+	assert.Zero(t, storage.retention)
+	assert.Equal(t, packing.StrategyGreedy, storage.defaultStrategy)
+	assert.Equal(t, packing.DefaultRounding, storage.rounding)
+}
+
+func TestNewPackStorageWithOptions(t *testing.T) {
+	clock := newFakeClock()
+	storage := NewPackStorage(
+		WithMaxPacks(1),
+		WithMaxOrders(2),
+		WithOrderRetention(time.Hour),
+		WithClock(clock),
+	)
+
+	assert.NoError(t, storage.AddPack(t.Context(), 250))
+	assert.ErrorIs(t, storage.AddPack(t.Context(), 500), ErrSoftLimitReached)
+
+	for range 3 {
+		order, err := storage.CalculateOrder(t.Context(), 100)
+		assert.NoError(t, err)
+		assert.Equal(t, clock.Now(), order.CreatedAt)
+	}
+	assert.Len(t, storage.GetOrders(t.Context()), 2)
+
+	// Orders older than the retention are dropped
+	clock.Advance(2 * time.Hour)
+	_, err := storage.CalculateOrder(t.Context(), 100)
+	assert.NoError(t, err)
+	assert.Len(t, storage.GetOrders(t.Context()), 1)
+}
+
+func TestNewPackStorageWithConfig(t *testing.T) {
+	storage := NewPackStorage(WithConfig(Config{MaxPacks: 3, MaxOrders: 5}), WithMaxOrders(4))
+	assert.Equal(t, 3, storage.packLimit())
+	assert.Equal(t, 4, storage.orderLimit())
+}
+
+func TestComputeTimeout(t *testing.T) {
+	storage := NewPackStorage(WithComputeTimeout(10 * time.Millisecond))
+	_ = storage.AddPack(t.Context(), 250)
+
+	// A solver running until it's cancelled
+	storage.solve = func(ctx context.Context, _ []*models.Pack, _ int, _ ...packing.Option) (models.Order, error) {
+		<-ctx.Done()
+		return models.Order{}, fmt.Errorf("%w: %w", ErrComputationTimeout, ctx.Err())
+	}
+
+	_, err := storage.CalculateOrder(t.Context(), 100)
+	assert.ErrorIs(t, err, ErrComputationTimeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, storage.GetOrders(t.Context()))
+}
+
+func TestCalculateOrderCancelled(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+	calls := countSolves(storage)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	_, err := storage.CalculateOrder(ctx, 100)
+	assert.ErrorIs(t, err, ErrComputationTimeout)
+	assert.Empty(t, storage.GetOrders(t.Context()))
+
+	// Failed computations aren't cached
+	_, err = storage.CalculateOrder(t.Context(), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, *calls)
+}
+
+func TestDefaultStrategy(t *testing.T) {
+	storage := NewPackStorage(WithDefaultStrategy(packing.StrategyMinOverpack))
+	for _, amount := range []int{4, 6, 9} {
+		assert.NoError(t, storage.AddPack(t.Context(), amount))
+	}
+
+	// min-overpack packs 11 items as 6+6 instead of 9+4
+	order, err := storage.CalculateOrder(t.Context(), 11)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, order.TotalItems)
+
+	// The strategy of the request overrides the default
+	order, err = storage.CalculateOrder(t.Context(), 11, packing.WithStrategy(packing.StrategyGreedy))
+	assert.NoError(t, err)
+	assert.Equal(t, 13, order.TotalItems)
+}
+
+func TestPackMultiple(t *testing.T) {
+	storage := NewPackStorage(WithPackMultiple(50))
+
+	assert.NoError(t, storage.AddPack(t.Context(), 250))
+	assert.NoError(t, storage.AddPack(t.Context(), 50))
+
+	err := storage.AddPack(t.Context(), 260)
+	assert.ErrorIs(t, err, ErrInvalidPackMultiple)
+	var multipleErr *MultipleError
+	assert.ErrorAs(t, err, &multipleErr)
+	assert.Equal(t, MultipleError{Amount: 260, Multiple: 50}, *multipleErr)
+
+	_, err = storage.UpdatePack(t.Context(), 250, 300)
+	assert.NoError(t, err)
+	_, err = storage.UpdatePack(t.Context(), 300, 325)
+	assert.ErrorIs(t, err, ErrInvalidPackMultiple)
+	_, err = storage.UpdatePackByID(t.Context(), 1, 350)
+	assert.NoError(t, err)
+	_, err = storage.UpdatePackByID(t.Context(), 1, 375)
+	assert.ErrorIs(t, err, ErrInvalidPackMultiple)
+
+	results := storage.AddPacks(t.Context(), []int{500, 510})
+	assert.Equal(t, models.ImportAdded, results[0].Status)
+	assert.Equal(t, models.ImportRejectedInvalid, results[1].Status)
+
+	var amounts []int
+	for _, pack := range storage.GetPacks(t.Context()) {
+		amounts = append(amounts, pack.Amount)
+	}
+	assert.Equal(t, []int{500, 350, 50}, amounts)
+
+	// No restriction by default
+	storage = NewPackStorage()
+	assert.NoError(t, storage.AddPack(t.Context(), 7))
+}
+
+func TestMinPackAmount(t *testing.T) {
+	storage := NewPackStorage(WithMinPackAmount(100), WithPackMultiple(50))
+
+	assert.NoError(t, storage.AddPack(t.Context(), 100))
+	err := storage.AddPack(t.Context(), 99)
+	assert.ErrorIs(t, err, ErrAmountTooSmall)
+	var minErr *MinAmountError
+	assert.ErrorAs(t, err, &minErr)
+	assert.Equal(t, MinAmountError{Amount: 99, Min: 100}, *minErr)
+
+	// The minimum is checked before the multiple
+	assert.ErrorIs(t, storage.AddPack(t.Context(), 50), ErrAmountTooSmall)
+	assert.NoError(t, storage.AddPack(t.Context(), 150))
+
+	_, err = storage.UpdatePack(t.Context(), 150, 50)
+	assert.ErrorIs(t, err, ErrAmountTooSmall)
+	_, err = storage.UpdatePack(t.Context(), 150, 200)
+	assert.NoError(t, err)
+	_, err = storage.UpdatePackByID(t.Context(), 1, 99)
+	assert.ErrorIs(t, err, ErrAmountTooSmall)
+	_, err = storage.UpdatePackByID(t.Context(), 1, 300)
+	assert.NoError(t, err)
+
+	results := storage.AddPacks(t.Context(), []int{100, 99})
+	assert.Equal(t, models.ImportAdded, results[0].Status)
+	assert.Equal(t, models.ImportRejectedInvalid, results[1].Status)
+
+	var amounts []int
+	for _, pack := range storage.GetPacks(t.Context()) {
+		amounts = append(amounts, pack.Amount)
+	}
+	assert.Equal(t, []int{300, 200, 100}, amounts)
+
+	// Any positive amount by default
+	storage = NewPackStorage()
+	assert.NoError(t, storage.AddPack(t.Context(), 1))
+}
+
+func TestMaxOrderPacks(t *testing.T) {
+	storage := NewPackStorage(WithMaxOrderPacks(4))
+	_ = storage.AddPack(t.Context(), 250)
+
+	order, err := storage.CalculateOrder(t.Context(), 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, order.PackCount())
+
+	_, err = storage.CalculateOrder(t.Context(), 1001)
+	assert.ErrorIs(t, err, ErrTooManyOrderPacks)
+	var packsErr *OrderPacksError
+	assert.ErrorAs(t, err, &packsErr)
+	assert.Equal(t, OrderPacksError{Count: 5, Limit: 4}, *packsErr)
+	assert.Len(t, storage.GetOrders(t.Context()), 1)
+
+	// Cached orders are checked too
+	_, err = storage.QuoteOrder(t.Context(), 1001)
+	assert.ErrorIs(t, err, ErrTooManyOrderPacks)
+
+	// No limit by default
+	storage = NewPackStorage()
+	_ = storage.AddPack(t.Context(), 1)
+	order, err = storage.CalculateOrder(t.Context(), 100_000)
+	assert.NoError(t, err)
+	assert.Equal(t, 100_000, order.PackCount())
+}
+
+func TestWithoutOrderHistory(t *testing.T) {
+	clock := newFakeClock()
+	storage := NewPackStorage(WithoutOrderHistory(), WithClock(clock))
+	_ = storage.AddPack(t.Context(), 250)
+
+	for range 3 {
+		order, err := storage.CalculateOrder(t.Context(), 100)
+		assert.NoError(t, err)
+		assert.Equal(t, 250, order.TotalItems)
+		assert.Equal(t, clock.Now(), order.CreatedAt)
+		assert.Zero(t, order.ID)
+	}
+	assert.NotNil(t, storage.GetOrders(t.Context()))
+	assert.Empty(t, storage.GetOrders(t.Context()))
+
+	// Restored orders aren't recorded either
+	snapshot := models.Snapshot{Packs: []*models.Pack{{Amount: 250}}, Orders: []models.Order{{ID: 1, RequestedItems: 100}}}
+	assert.NoError(t, storage.Restore(t.Context(), snapshot))
+	assert.Empty(t, storage.GetOrders(t.Context()))
+}
+
+func TestOverpackWarnings(t *testing.T) {
+	storage := NewPackStorage(WithOverpackPercentWarning(40), WithOverpackItemsWarning(200))
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 500)
+
+	// 250 for 200 items overpacks 25%
+	order, err := storage.CalculateOrder(t.Context(), 200)
+	assert.NoError(t, err)
+	assert.Nil(t, order.Warnings)
+
+	// 500 for 251 items overpacks 99.2%
+	order, err = storage.CalculateOrder(t.Context(), 251)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"overpack of 99.2% exceeds 40%",
+		"overpack of 249 items exceeds 200 items",
+	}, order.Warnings)
+	assert.Equal(t, order.Warnings, storage.GetOrders(t.Context())[1].Warnings)
+
+	// Cached orders get the warnings once
+	order, err = storage.CalculateOrder(t.Context(), 251)
+	assert.NoError(t, err)
+	assert.Len(t, order.Warnings, 2)
+
+	// The warnings coexist with the hard limit
+	_, err = storage.CalculateOrder(t.Context(), 251, packing.WithMaxOverpack(100))
+	assert.ErrorIs(t, err, packing.ErrOverpackExceeded)
+
+	// No warnings by default
+	storage = NewPackStorage()
+	_ = storage.AddPack(t.Context(), 500)
+	order, err = storage.CalculateOrder(t.Context(), 1)
+	assert.NoError(t, err)
+	assert.Nil(t, order.Warnings)
+}
+
+func TestPercentRounding(t *testing.T) {
+	storage := NewPackStorage(WithPercentRounding(packing.Rounding{Mode: packing.RoundFloor, Decimals: 1}))
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 500)
+
+	// 149 items overpacked by 101 is 67.78...%, 200 items overpacked by 50 is 25%
+	for range 2 {
+		order, err := storage.CalculateOrder(t.Context(), 149)
+		assert.NoError(t, err)
+		assert.Equal(t, 67.7, order.OverpackPercent)
+	}
+	_, _ = storage.CalculateOrder(t.Context(), 200)
+	candidates, err := storage.OrderCandidates(t.Context(), 149, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 67.7, candidates[0].OverpackPercent)
+
+	// The mean of 67.7, 67.7 and 25 is 53.46...
+	assert.Equal(t, 53.4, storage.OrderSummary(t.Context()).AverageOverpackPercent)
+	// One of 3 lookups hit the cache
+	assert.Equal(t, 33.3, storage.Stats(t.Context()).Cache.HitRatePercent)
+}
@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/packing"
+)
+
+// FitPacks tells whether the current packs hold exactly the requested items and which packs are closest to them,
+// without computing or recording an order
+func (s *PackStorage) FitPacks(ctx context.Context, requestedItems int) (models.PackFit, error) {
+	s.mu.RLock()
+	// The solver doesn't modify the packs, so there's no need to copy them
+	packs := s.solverPacks
+	s.mu.RUnlock()
+
+	exact, err := packing.ExactFit(ctx, packs, requestedItems)
+	if err != nil {
+		return models.PackFit{}, err
+	}
+
+	fit := models.PackFit{RequestedItems: requestedItems, ExactFit: exact}
+	// The packs are sorted in descending order, so the last larger pack is the smallest one
+	for _, pack := range packs {
+		if pack.Amount >= requestedItems {
+			fit.SmallestCovering = copyPack(pack)
+		}
+		if pack.Amount <= requestedItems && fit.LargestFitting == nil {
+			fit.LargestFitting = copyPack(pack)
+		}
+	}
+
+	return fit, nil
+}
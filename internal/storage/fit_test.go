@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFitPacks(t *testing.T) {
+	storage := NewPackStorage()
+	_, err := storage.FitPacks(t.Context(), 100)
+	assert.ErrorIs(t, err, ErrNoPacksAvailable)
+
+	for _, amount := range []int{250, 500, 1000} {
+		_ = storage.AddPack(t.Context(), amount)
+	}
+
+	tests := []struct {
+		name     string
+		items    int
+		exact    bool
+		largest  int
+		smallest int
+	}{
+		{"below every pack", 100, false, 0, 250},
+		{"between packs", 750, true, 500, 1000},
+		{"matches a pack", 500, true, 500, 500},
+		{"above every pack", 1001, false, 1000, 0},
+		{"exact above every pack", 2250, true, 1000, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fit, err := storage.FitPacks(t.Context(), tt.items)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.items, fit.RequestedItems)
+			assert.Equal(t, tt.exact, fit.ExactFit)
+			assert.Equal(t, tt.largest, amountOf(fit.LargestFitting))
+			assert.Equal(t, tt.smallest, amountOf(fit.SmallestCovering))
+		})
+	}
+
+	// Nothing is recorded
+	assert.Empty(t, storage.GetOrders(t.Context()))
+}
+
+// amountOf returns the amount of the pack, 0 for nil
+func amountOf(pack *models.Pack) int {
+	if pack == nil {
+		return 0
+	}
+	return pack.Amount
+}
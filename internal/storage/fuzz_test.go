@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/packing"
+)
+
+// FuzzCalculateOrder packs random requests with random pack sets of up to 4 sizes and checks the invariants of the
+// orders of every strategy. Run it with `go test -fuzz=FuzzCalculateOrder ./internal/storage`.
+func FuzzCalculateOrder(f *testing.F) {
+	f.Add(uint16(250), uint16(500), uint16(1000), uint16(5000), uint32(12001), uint8(0))
+	f.Add(uint16(4), uint16(6), uint16(9), uint16(0), uint32(11), uint8(1))
+	f.Add(uint16(23), uint16(31), uint16(53), uint16(0), uint32(500000), uint8(1))
+	f.Add(uint16(250), uint16(500), uint16(0), uint16(0), uint32(750), uint8(2))
+	f.Add(uint16(2000), uint16(5000), uint16(0), uint16(0), uint32(4000), uint8(3))
+	f.Add(uint16(250), uint16(500), uint16(0), uint16(0), uint32(251), uint8(4))
+	f.Add(uint16(1), uint16(1), uint16(1), uint16(1), uint32(1), uint8(0))
+
+	f.Fuzz(func(t *testing.T, a, b, c, d uint16, items uint32, strategyIndex uint8) {
+		// Bound the sizes and the request to keep the optimal strategies fast
+		storage := NewPackStorage()
+		for _, amount := range []uint16{a, b, c, d} {
+			if amount = amount % 2000; amount > 0 {
+				_ = storage.AddPack(t.Context(), int(amount))
+			}
+		}
+		packs := storage.GetPacks(t.Context())
+		requested := int(items%1_000_000) + 1
+		strategy := packing.Strategies[int(strategyIndex)%len(packing.Strategies)]
+
+		order, err := storage.CalculateOrder(t.Context(), requested, packing.WithStrategy(strategy))
+		switch {
+		case len(packs) == 0:
+			if !errors.Is(err, ErrNoPacksAvailable) {
+				t.Fatalf("no packs: got %v, want ErrNoPacksAvailable", err)
+			}
+			return
+		case errors.Is(err, packing.ErrComputationComplexity):
+			return
+		case strategy == packing.StrategyMaxUnder && requested < packs[len(packs)-1].Amount:
+			if !errors.Is(err, ErrUnsatisfiable) {
+				t.Fatalf("max-under below the smallest pack: got %v, want ErrUnsatisfiable", err)
+			}
+			return
+		case err != nil:
+			t.Fatalf("packs %v, %d items, %s: %v", packAmounts(packs), requested, strategy, err)
+		}
+
+		total := 0
+		for _, orderPack := range order.Packs {
+			if orderPack.Quantity <= 0 {
+				t.Fatalf("pack %d has quantity %d", orderPack.Pack.Amount, orderPack.Quantity)
+			}
+			if orderPack.Subtotal != orderPack.Quantity*orderPack.Pack.Amount {
+				t.Fatalf("pack %d: subtotal %d of %d packs", orderPack.Pack.Amount, orderPack.Subtotal, orderPack.Quantity)
+			}
+			total += orderPack.Subtotal
+		}
+		if total != order.TotalItems {
+			t.Fatalf("packs hold %d items, total is %d", total, order.TotalItems)
+		}
+		if order.RequestedItems != requested {
+			t.Fatalf("requested items %d, want %d", order.RequestedItems, requested)
+		}
+		if order.OverpackedItems < 0 || order.UnfulfilledItems < 0 {
+			t.Fatalf("negative overpack %d or shortfall %d", order.OverpackedItems, order.UnfulfilledItems)
+		}
+		if order.TotalItems != requested+order.OverpackedItems-order.UnfulfilledItems {
+			t.Fatalf("total %d of %d items with overpack %d and shortfall %d",
+				order.TotalItems, requested, order.OverpackedItems, order.UnfulfilledItems)
+		}
+
+		// Every strategy tops up with at most one smallest pack. The shortfall of max-under is below the smallest pack
+		// as well, one more would still fit.
+		smallest := packs[len(packs)-1].Amount
+		if strategy == packing.StrategyMaxUnder {
+			if order.OverpackedItems != 0 || order.UnfulfilledItems >= smallest {
+				t.Fatalf("packs %v, %d items, max-under: packed %d items", packAmounts(packs), requested, order.TotalItems)
+			}
+			return
+		}
+		if order.UnfulfilledItems != 0 || order.OverpackedItems >= smallest {
+			t.Fatalf("packs %v, %d items, %s: overpack %d not below the smallest pack",
+				packAmounts(packs), requested, strategy, order.OverpackedItems)
+		}
+	})
+}
@@ -0,0 +1,23 @@
+package storage
+
+import "strings"
+
+// String identifiers like the pack labels and the order references follow a single normalization policy:
+// the surrounding whitespace is dropped and inner runs of whitespace become a single space when they are stored,
+// and they are compared case-insensitively. Every entry point goes through these helpers.
+
+// normalizeIdentifier returns the identifier as it's stored
+func normalizeIdentifier(identifier string) string {
+	return strings.Join(strings.Fields(identifier), " ")
+}
+
+// identifierKey returns the key under which the identifier is unique, the same for identifiers differing only in
+// case and whitespace
+func identifierKey(identifier string) string {
+	return strings.ToLower(normalizeIdentifier(identifier))
+}
+
+// sameIdentifier tells whether both identifiers are the same after normalization, ignoring case
+func sameIdentifier(a, b string) bool {
+	return identifierKey(a) == identifierKey(b)
+}
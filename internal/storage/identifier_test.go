@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeIdentifier(t *testing.T) {
+	tests := []struct {
+		identifier string
+		want       string
+	}{
+		{"box", "box"},
+		{"  Small box ", "Small box"},
+		{"small \t\n box", "small box"},
+		{"   ", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, normalizeIdentifier(tt.identifier), "identifier %q", tt.identifier)
+	}
+}
+
+func TestSameIdentifier(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"box", "box", true},
+		{"Box", "BOX", true},
+		{" small  box", "Small Box ", true},
+		{"small\tbox", "small box", true},
+		{"smallbox", "small box", false},
+		{"box", "boxes", false},
+		{"", "  ", true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, sameIdentifier(tt.a, tt.b), "identifiers %q and %q", tt.a, tt.b)
+	}
+}
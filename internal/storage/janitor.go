@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// janitor periodically prunes expired entries from the storage in the background
+type janitor struct {
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// StartJanitor starts a background goroutine pruning expired orders every interval until the context is done or the
+// storage is closed. It does nothing if the janitor is already running or the interval is not positive.
+func (s *PackStorage) StartJanitor(ctx context.Context, interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.janitor != nil || interval <= 0 {
+		return
+	}
+
+	j := &janitor{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	s.janitor = j
+
+	go func() {
+		defer close(j.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.prune()
+			case <-j.stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the background janitor and waits for it to exit, or until the context is done. It also ends all order
+// subscriptions. It is safe to call multiple times.
+func (s *PackStorage) Close(ctx context.Context) error {
+	s.mu.Lock()
+	j := s.janitor
+	s.janitor = nil
+	s.closed = true
+	s.closeSubscriptions()
+	s.mu.Unlock()
+
+	if j == nil {
+		return nil
+	}
+
+	j.once.Do(func() { close(j.stop) })
+	select {
+	case <-j.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// prune drops all expired entries from the storage
+func (s *PackStorage) prune() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneExpiredOrders(s.clock.Now())
+}
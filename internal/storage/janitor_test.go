@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJanitorPrunesExpiredOrders(t *testing.T) {
+	clock := newFakeClock()
+	storage := NewPackStorage(WithClock(clock), WithOrderRetention(time.Hour))
+	_ = storage.AddPack(t.Context(), 100)
+
+	_, _ = storage.CalculateOrder(t.Context(), 100)
+	assert.Len(t, storage.GetOrders(t.Context()), 1)
+
+	storage.StartJanitor(t.Context(), time.Millisecond)
+	defer func() { _ = storage.Close(t.Context()) }()
+
+	clock.Advance(2 * time.Hour)
+
+	assert.Eventually(t, func() bool {
+		return len(storage.GetOrders(t.Context())) == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestJanitorClose(t *testing.T) {
+	storage := NewPackStorage()
+
+	// Close without a running janitor is a no-op
+	assert.NoError(t, storage.Close(t.Context()))
+
+	storage.StartJanitor(t.Context(), time.Millisecond)
+	j := storage.janitor
+	assert.NotNil(t, j)
+
+	assert.NoError(t, storage.Close(t.Context()))
+	assert.NoError(t, storage.Close(t.Context()))
+
+	// The goroutine has exited
+	select {
+	case <-j.done:
+	default:
+		t.Fatal("janitor goroutine is still running")
+	}
+}
+
+func TestJanitorStopsWithContext(t *testing.T) {
+	storage := NewPackStorage()
+	ctx, cancel := context.WithCancel(t.Context())
+
+	storage.StartJanitor(ctx, time.Millisecond)
+	j := storage.janitor
+	cancel()
+
+	select {
+	case <-j.done:
+	case <-time.After(time.Second):
+		t.Fatal("janitor goroutine is still running")
+	}
+	assert.NoError(t, storage.Close(t.Context()))
+}
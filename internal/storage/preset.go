@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+)
+
+// ErrPresetNotFound means there is no preset with the requested name
+var ErrPresetNotFound = errors.New("pack preset not found")
+
+// DefaultPreset is the name of the preset holding the packs the service starts with
+const DefaultPreset = "default"
+
+// presets are the built-in pack sets, by name. The amounts must be positive and unique.
+var presets = map[string][]int{
+	DefaultPreset: {250, 500, 1000, 2000, 5000},
+	// metric follows the 1-2-5 series
+	"metric": {100, 200, 500, 1000, 2000, 5000},
+	// dozens packs by the dozen up to a gross
+	"dozens": {6, 12, 24, 48, 144},
+}
+
+// PresetNames returns the names of the built-in pack presets, sorted
+func PresetNames() []string {
+	return slices.Sorted(maps.Keys(presets))
+}
+
+// PresetAmounts returns the pack amounts of the preset with the specified name, largest first
+func PresetAmounts(name string) ([]int, error) {
+	amounts, ok := presets[name]
+	if !ok {
+		return nil, ErrPresetNotFound
+	}
+	amounts = slices.Clone(amounts)
+	slices.SortFunc(amounts, func(a, b int) int { return b - a })
+	return amounts, nil
+}
+
+// ApplyPreset replaces the packs with the packs of the preset with the specified name and returns them.
+// The packs with amounts of the preset are kept with their IDs, labels and pins, the other packs are deleted and the
+// missing amounts are added. All amounts are checked first, nothing changes if any of them is rejected, the preset
+// exceeds the pack limit or a pinned pack would be deleted. The changes are applied as a single version of the pack set.
+func (s *PackStorage) ApplyPreset(_ context.Context, name string) ([]*models.Pack, error) {
+	amounts, err := PresetAmounts(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if limit := s.packLimit(); len(amounts) > limit {
+		return nil, &LimitError{Count: len(amounts), Limit: limit}
+	}
+	for _, amount := range amounts {
+		if err := s.checkPackAmount(amount); err != nil {
+			return nil, err
+		}
+	}
+	var changes []models.PackChange
+	kept := make([]*models.Pack, 0, len(amounts))
+	for _, pack := range s.packs {
+		if slices.Contains(amounts, pack.Amount) {
+			kept = append(kept, pack)
+			continue
+		}
+		if pack.Pinned {
+			return nil, ErrPackPinned
+		}
+		changes = append(changes, models.PackChange{Action: models.PackDeleted, PackID: pack.ID, OldAmount: pack.Amount})
+	}
+
+	// The preset is applied as a single version of the pack set, the intermediate sets are never visible
+	s.packs = kept
+	for _, change := range changes {
+		delete(s.byID, change.PackID)
+	}
+	for _, amount := range amounts {
+		if findPack(s.packs, amount) != nil {
+			continue
+		}
+		pack := &models.Pack{ID: s.nextID, Amount: amount}
+		s.nextID++
+		s.packs = append(s.packs, pack)
+		s.byID[pack.ID] = pack
+		changes = append(changes, models.PackChange{Action: models.PackAdded, PackID: pack.ID, NewAmount: amount})
+	}
+	if len(changes) == 0 {
+		return s.getPacks(), nil
+	}
+	s.resortPacks()
+	s.packSetChanged()
+	for _, change := range changes {
+		s.appendHistory(change)
+	}
+
+	return s.getPacks(), nil
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresets(t *testing.T) {
+	assert.Equal(t, []string{"default", "dozens", "metric"}, PresetNames())
+	for _, name := range PresetNames() {
+		amounts, err := PresetAmounts(name)
+		assert.NoError(t, err)
+		assert.Empty(t, NewPackStorage().packViolations(amounts, SoftLimit), name)
+	}
+
+	amounts, err := PresetAmounts(DefaultPreset)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{5000, 2000, 1000, 500, 250}, amounts)
+
+	_, err = PresetAmounts("imperial")
+	assert.ErrorIs(t, err, ErrPresetNotFound)
+}
+
+func TestApplyPreset(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 300)
+	pack, _ := storage.AddLabeledPack(t.Context(), 1000, "box")
+
+	packs, err := storage.ApplyPreset(t.Context(), "metric")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{5000, 2000, 1000, 500, 200, 100}, packAmounts(packs))
+	assert.Equal(t, packs, storage.GetPacks(t.Context()))
+
+	// The pack in both sets is kept as it is
+	kept, err := storage.GetPack(t.Context(), 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, pack, kept)
+
+	_, err = storage.ApplyPreset(t.Context(), "imperial")
+	assert.ErrorIs(t, err, ErrPresetNotFound)
+}
+
+func TestApplyPresetSingleVersion(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 300)
+	version := storage.PackSetVersion(t.Context())
+
+	_, err := storage.ApplyPreset(t.Context(), "metric")
+	assert.NoError(t, err)
+	assert.Equal(t, version+1, storage.PackSetVersion(t.Context()))
+
+	// The previous version is the pack set before the preset
+	packs, err := storage.PacksAtVersion(t.Context(), version)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{300}, packAmounts(packs))
+
+	// The history lists every change with the version of the preset
+	history := storage.GetPackHistory(t.Context())
+	assert.Len(t, history, 8)
+	for _, change := range history[1:] {
+		assert.Equal(t, version+1, change.Version)
+	}
+	assert.Equal(t, models.PackDeleted, history[1].Action)
+	assert.Equal(t, 300, history[1].OldAmount)
+
+	// Applying the same preset again changes nothing
+	_, err = storage.ApplyPreset(t.Context(), "metric")
+	assert.NoError(t, err)
+	assert.Equal(t, version+1, storage.PackSetVersion(t.Context()))
+	assert.Len(t, storage.GetPackHistory(t.Context()), 8)
+}
+
+func TestApplyPresetIsAtomic(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage *PackStorage
+		check   func(error) bool
+	}{
+		{"limit", NewPackStorage(WithMaxPacks(5)), func(err error) bool {
+			var limitErr *LimitError
+			return errors.As(err, &limitErr) && limitErr.Count == 6 && limitErr.Limit == 5
+		}},
+		{"multiple", NewPackStorage(WithPackMultiple(250)), func(err error) bool {
+			return errors.Is(err, ErrInvalidPackMultiple)
+		}},
+		{"min amount", NewPackStorage(WithMinPackAmount(150)), func(err error) bool {
+			return errors.Is(err, ErrAmountTooSmall)
+		}},
+		{"pinned", NewPackStorage(), func(err error) bool {
+			return errors.Is(err, ErrPackPinned)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = tt.storage.AddPack(t.Context(), 250)
+			_ = tt.storage.AddPack(t.Context(), 500)
+			_, _ = tt.storage.SetPackPinned(t.Context(), 250, true)
+			before := tt.storage.GetPacks(t.Context())
+			version := tt.storage.PackSetVersion(t.Context())
+
+			_, err := tt.storage.ApplyPreset(t.Context(), "metric")
+			assert.True(t, tt.check(err), err)
+			assert.Equal(t, before, tt.storage.GetPacks(t.Context()))
+			assert.Equal(t, version, tt.storage.PackSetVersion(t.Context()))
+		})
+	}
+}
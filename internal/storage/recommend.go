@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/packing"
+)
+
+const (
+	// MaxRecommendations is the max number of pack sizes RecommendPacks suggests
+	MaxRecommendations = 2
+	// maxRecommendCandidates bounds the number of simulated pack sizes, each one re-packs the whole order history
+	maxRecommendCandidates = 100
+)
+
+// RecommendPacks suggests up to count new pack sizes which would most reduce the cumulative overpack
+// of the recorded orders. Each suggestion is simulated together with the ones before it.
+// It fails with ErrComputationTimeout if the context is done before all candidates are simulated.
+func (s *PackStorage) RecommendPacks(ctx context.Context, count int) (models.PackRecommendation, error) {
+	s.mu.RLock()
+	packs := make([]int, len(s.packs))
+	for i, p := range s.packs {
+		packs[i] = p.Amount
+	}
+	requests := make([]int, len(s.orders))
+	for i, o := range s.orders {
+		requests[i] = o.RequestedItems
+	}
+	s.mu.RUnlock()
+
+	if len(packs) == 0 {
+		return models.PackRecommendation{}, ErrNoPacksAvailable
+	}
+	count = max(0, min(count, MaxRecommendations))
+
+	overpack, err := totalOverpack(packs, requests)
+	if err != nil {
+		return models.PackRecommendation{}, err
+	}
+
+	recommendation := models.PackRecommendation{
+		Orders:          len(requests),
+		CurrentOverpack: overpack,
+		Suggestions:     make([]models.PackSuggestion, 0, count),
+	}
+
+	candidates := recommendCandidates(packs, requests)
+	for len(recommendation.Suggestions) < count {
+		best := models.PackSuggestion{Overpack: overpack}
+		for _, candidate := range candidates {
+			if containsAmount(packs, candidate) {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return models.PackRecommendation{}, fmt.Errorf("%w: %w", ErrComputationTimeout, err)
+			}
+			candidateOverpack, err := totalOverpack(append(packs, candidate), requests)
+			if err != nil {
+				return models.PackRecommendation{}, err
+			}
+			// Candidates are sorted, so ties are resolved in favour of the smaller pack
+			if candidateOverpack < best.Overpack {
+				best = models.PackSuggestion{Amount: candidate, Overpack: candidateOverpack}
+			}
+		}
+
+		// Nothing reduces the overpack any further
+		if best.Amount == 0 {
+			break
+		}
+
+		best.Reduction = overpack - best.Overpack
+		recommendation.Suggestions = append(recommendation.Suggestions, best)
+		packs = append(packs, best.Amount)
+		overpack = best.Overpack
+	}
+
+	return recommendation, nil
+}
+
+// recommendCandidates returns the pack sizes worth simulating: the requested amounts themselves and the
+// remainders left after filling them with each existing pack size, most frequent first.
+func recommendCandidates(packs, requests []int) []int {
+	frequency := make(map[int]int)
+	for _, requested := range requests {
+		frequency[requested]++
+		for _, pack := range packs {
+			if remainder := requested % pack; remainder > 0 {
+				frequency[remainder]++
+			}
+		}
+	}
+
+	candidates := make([]int, 0, len(frequency))
+	for candidate := range frequency {
+		candidates = append(candidates, candidate)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if frequency[candidates[i]] != frequency[candidates[j]] {
+			return frequency[candidates[i]] > frequency[candidates[j]]
+		}
+		return candidates[i] < candidates[j]
+	})
+	if len(candidates) > maxRecommendCandidates {
+		candidates = candidates[:maxRecommendCandidates]
+	}
+
+	// Simulate in ascending order so ties favour smaller packs
+	sort.Ints(candidates)
+
+	return candidates
+}
+
+// totalOverpack returns the sum of the overpacked items of all requests packed with the given packs
+func totalOverpack(packs, requests []int) (int, error) {
+	total := 0
+	for _, requested := range requests {
+		order, err := packing.Pack(packs, requested)
+		if err != nil {
+			return 0, err
+		}
+		total += order.OverpackedItems
+	}
+	return total, nil
+}
+
+func containsAmount(amounts []int, amount int) bool {
+	for _, a := range amounts {
+		if a == amount {
+			return true
+		}
+	}
+	return false
+}
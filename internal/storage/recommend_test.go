@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecommendPacks(t *testing.T) {
+	storage := NewPackStorage()
+
+	_, err := storage.RecommendPacks(t.Context(), 1)
+	assert.Equal(t, ErrNoPacksAvailable, err)
+
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 1000)
+
+	// No orders - nothing to recommend
+	recommendation, err := storage.RecommendPacks(t.Context(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, recommendation.Orders)
+	assert.Empty(t, recommendation.Suggestions)
+
+	// Each order is 100 items, overpacked by 150
+	for i := 0; i < 3; i++ {
+		_, _ = storage.CalculateOrder(t.Context(), 100)
+	}
+	// 1100 items, overpacked by 150
+	_, _ = storage.CalculateOrder(t.Context(), 1100)
+
+	recommendation, err = storage.RecommendPacks(t.Context(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, recommendation.Orders)
+	assert.Equal(t, 600, recommendation.CurrentOverpack)
+
+	// A pack of 100 removes the overpack entirely
+	assert.Len(t, recommendation.Suggestions, 1)
+	assert.Equal(t, 100, recommendation.Suggestions[0].Amount)
+	assert.Equal(t, 0, recommendation.Suggestions[0].Overpack)
+	assert.Equal(t, 600, recommendation.Suggestions[0].Reduction)
+
+	// Recommendations don't change the packs or record orders
+	assert.Len(t, storage.GetPacks(t.Context()), 2)
+	assert.Len(t, storage.GetOrders(t.Context()), 4)
+}
+
+func TestRecommendPacksCancelled(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+	_, _ = storage.CalculateOrder(t.Context(), 100)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	_, err := storage.RecommendPacks(ctx, 1)
+	assert.ErrorIs(t, err, ErrComputationTimeout)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRecommendPacksTwoSuggestions(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 1000)
+
+	_, _ = storage.CalculateOrder(t.Context(), 300)
+	_, _ = storage.CalculateOrder(t.Context(), 300)
+	_, _ = storage.CalculateOrder(t.Context(), 70)
+
+	recommendation, err := storage.RecommendPacks(t.Context(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2330, recommendation.CurrentOverpack)
+	assert.Len(t, recommendation.Suggestions, 2)
+
+	// 70 alone brings 300 down to 5x70 (overpack 50 each)
+	assert.Equal(t, 70, recommendation.Suggestions[0].Amount)
+	assert.Equal(t, 100, recommendation.Suggestions[0].Overpack)
+	assert.Equal(t, 2230, recommendation.Suggestions[0].Reduction)
+
+	// 300 on top of 70 removes the rest
+	assert.Equal(t, 300, recommendation.Suggestions[1].Amount)
+	assert.Equal(t, 0, recommendation.Suggestions[1].Overpack)
+	assert.Equal(t, 100, recommendation.Suggestions[1].Reduction)
+
+	// Count is capped
+	recommendation, err = storage.RecommendPacks(t.Context(), 10)
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, len(recommendation.Suggestions), MaxRecommendations)
+}
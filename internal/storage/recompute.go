@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+)
+
+// ErrPackSetChanged is returned by RecomputeOrders when the packs change while the orders are recomputed
+var ErrPackSetChanged = errors.New("pack set changed during the recompute")
+
+// RecomputeOrders packs the requested items of every recorded order again with the current packs and the default
+// strategy, and replaces the packing of the orders in place. The IDs, timestamps and tags of the orders are kept, and
+// so are the cartons of the orders listing them. Orders that can't be packed anymore are left unchanged and counted as
+// failed. It fails with ErrNoPacksAvailable if there are no packs, with ErrComputationTimeout if the context is done
+// and with ErrPackSetChanged if the packs change in the meantime, without changing any order.
+func (s *PackStorage) RecomputeOrders(ctx context.Context) (models.RecomputeSummary, error) {
+	s.mu.RLock()
+	orders := s.getOrders()
+	noPacks := len(s.packs) == 0
+	version := s.packSetState.Version
+	s.mu.RUnlock()
+
+	if noPacks {
+		return models.RecomputeSummary{}, ErrNoPacksAvailable
+	}
+
+	summary := models.RecomputeSummary{Orders: len(orders)}
+	recomputed := make(map[int]models.Order, len(orders))
+	for _, order := range orders {
+		if err := ctx.Err(); err != nil {
+			return models.RecomputeSummary{}, fmt.Errorf("%w: %w", ErrComputationTimeout, err)
+		}
+		updated, err := s.computeOrder(ctx, order.RequestedItems, nil)
+		if errors.Is(err, ErrComputationTimeout) || errors.Is(err, ErrNoPacksAvailable) {
+			return models.RecomputeSummary{}, err
+		}
+		if err != nil {
+			summary.Failed++
+			continue
+		}
+		if len(order.Cartons) > 0 {
+			updated = updated.WithCartons()
+		}
+		recomputed[order.ID] = updated
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The orders were computed without the lock, so they must not replace the orders if the packs changed since
+	if s.packSetState.Version != version {
+		return models.RecomputeSummary{}, ErrPackSetChanged
+	}
+
+	// The orders recorded or pruned in the meantime are skipped
+	for i, order := range s.orders {
+		updated, ok := recomputed[order.ID]
+		if !ok {
+			continue
+		}
+		if !samePacking(order, updated) {
+			summary.Changed++
+			summary.OverpackDelta += updated.OverpackedItems - order.OverpackedItems
+		}
+		updated.ID = order.ID
+		updated.CreatedAt = order.CreatedAt
+		updated.Reference = order.Reference
+		updated.Metadata = order.Metadata
+		s.orders[i] = updated
+	}
+
+	return summary, nil
+}
+
+// samePacking tells whether both orders use the same quantities of the same pack amounts
+func samePacking(a, b models.Order) bool {
+	if len(a.Packs) != len(b.Packs) {
+		return false
+	}
+	for i := range a.Packs {
+		if a.Packs[i].Pack.Amount != b.Packs[i].Pack.Amount || a.Packs[i].Quantity != b.Packs[i].Quantity {
+			return false
+		}
+	}
+	return true
+}
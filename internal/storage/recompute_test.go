@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecomputeOrders(t *testing.T) {
+	clock := newFakeClock()
+	storage := NewPackStorage(WithClock(clock))
+
+	_, err := storage.RecomputeOrders(t.Context())
+	assert.ErrorIs(t, err, ErrNoPacksAvailable)
+
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 500)
+	_, _ = storage.CalculateTaggedOrder(t.Context(), 100, models.OrderTags{Reference: "A", Metadata: map[string]string{"k": "v"}})
+	clock.Advance(time.Minute)
+	_, _ = storage.CalculateOrder(t.Context(), 500)
+	before := storage.GetOrders(t.Context())
+
+	// A pack of 100 packs the first order exactly, the second one stays the same
+	_ = storage.AddPack(t.Context(), 100)
+	summary, err := storage.RecomputeOrders(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, models.RecomputeSummary{Orders: 2, Changed: 1, OverpackDelta: -150}, summary)
+
+	after := storage.GetOrders(t.Context())
+	assert.Len(t, after, 2)
+	assert.Equal(t, 100, after[0].TotalItems)
+	assert.Equal(t, 100, after[0].Packs[0].Pack.Amount)
+	for i := range after {
+		assert.Equal(t, before[i].CreatedAt, after[i].CreatedAt)
+		assert.Equal(t, before[i].Reference, after[i].Reference)
+		assert.Equal(t, before[i].Metadata, after[i].Metadata)
+	}
+
+	// Recomputing again changes nothing
+	summary, err = storage.RecomputeOrders(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, models.RecomputeSummary{Orders: 2}, summary)
+}
+
+func TestRecomputeOrdersFailures(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+	_, _ = storage.CalculateOrder(t.Context(), 1000)
+
+	// The order can't be packed within the limit anymore and stays as it was
+	storage.maxOrderPacks = 2
+	summary, err := storage.RecomputeOrders(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, models.RecomputeSummary{Orders: 1, Failed: 1}, summary)
+	assert.Equal(t, 4, storage.GetOrders(t.Context())[0].PackCount())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	_, err = storage.RecomputeOrders(ctx)
+	assert.ErrorIs(t, err, ErrComputationTimeout)
+}
+
+func TestRecomputeOrdersKeepsCartons(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+	order, _ := storage.CalculateOrder(t.Context(), 300)
+	snapshot := storage.Snapshot(t.Context())
+	snapshot.Orders[0] = order.WithCartons()
+	assert.NoError(t, storage.Restore(t.Context(), snapshot))
+
+	_ = storage.AddPack(t.Context(), 500)
+	summary, err := storage.RecomputeOrders(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, models.RecomputeSummary{Orders: 1, Changed: 1}, summary)
+	assert.Equal(t, []models.Carton{{Number: 1, PackID: 2, Amount: 500}}, storage.GetOrders(t.Context())[0].Cartons)
+}
+
+// changingContext adds a pack the first time the recompute checks it, after the orders were read
+type changingContext struct {
+	context.Context
+	storage *PackStorage
+	once    sync.Once
+}
+
+func (c *changingContext) Err() error {
+	c.once.Do(func() { _ = c.storage.AddPack(context.Background(), 100) })
+	return c.Context.Err()
+}
+
+func TestRecomputeOrdersPackSetChanged(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+	_, _ = storage.CalculateOrder(t.Context(), 300)
+
+	_, err := storage.RecomputeOrders(&changingContext{Context: t.Context(), storage: storage})
+	assert.ErrorIs(t, err, ErrPackSetChanged)
+	assert.Equal(t, 500, storage.GetOrders(t.Context())[0].TotalItems)
+}
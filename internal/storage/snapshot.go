@@ -0,0 +1,250 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/packing"
+)
+
+var (
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+	// ErrInvalidConfig is returned by ImportConfig for configurations failing the checks of Restore
+	ErrInvalidConfig = errors.New("invalid config")
+)
+
+// Snapshot returns a copy of the full storage state (packs, orders and config)
+func (s *PackStorage) Snapshot(_ context.Context) models.Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return models.Snapshot{
+		Packs:  s.getPacks(),
+		Orders: s.getOrders(),
+		Config: models.SnapshotConfig{SoftLimit: s.packLimit()},
+	}
+}
+
+// Restore replaces the full storage state with the given snapshot.
+// The snapshot is validated first, the current state is left untouched if it is invalid.
+func (s *PackStorage) Restore(_ context.Context, snapshot models.Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	limit := snapshot.Config.SoftLimit
+	if limit == 0 {
+		limit = s.packLimit()
+	}
+
+	if err := s.validateSnapshot(snapshot, limit, ErrInvalidSnapshot); err != nil {
+		return err
+	}
+
+	// Keep the IDs from the snapshot and assign new ones to packs without an ID
+	nextID := 1
+	for _, pack := range snapshot.Packs {
+		nextID = max(nextID, pack.ID+1)
+	}
+	packs := make([]*models.Pack, len(snapshot.Packs))
+	byID := make(map[int]*models.Pack, len(snapshot.Packs))
+	for i, pack := range snapshot.Packs {
+		packs[i] = copyPack(pack)
+		packs[i].Label = normalizeIdentifier(pack.Label)
+		if packs[i].ID == 0 {
+			packs[i].ID = nextID
+			nextID++
+		}
+		byID[packs[i].ID] = packs[i]
+	}
+	// The orders keep their IDs as well, the orders of older snapshots get new ones
+	nextOrderID := 1
+	for _, order := range snapshot.Orders {
+		nextOrderID = max(nextOrderID, order.ID+1)
+	}
+	orders := make([]models.Order, len(snapshot.Orders))
+	copy(orders, snapshot.Orders)
+	for i := range orders {
+		if orders[i].ID == 0 {
+			orders[i].ID = nextOrderID
+			nextOrderID++
+		}
+	}
+	// The recorded orders are kept sorted by CreatedAt, the snapshot order breaks ties
+	slices.SortStableFunc(orders, func(a, b models.Order) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	s.maxPacks = limit
+	s.packs = packs
+	s.byID = byID
+	s.nextID = nextID
+	// Without the order history the orders of the snapshot are dropped too
+	if !s.orderHistoryDisabled {
+		s.orders = orders
+	}
+	s.nextOrderID = nextOrderID
+	s.resortPacks()
+	s.packSetChanged()
+
+	return nil
+}
+
+// ExportConfig returns the packs with the pack limit and the default strategy
+func (s *PackStorage) ExportConfig(_ context.Context) models.PackConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	config := models.PackConfig{
+		SoftLimit:       s.packLimit(),
+		DefaultStrategy: string(s.defaultStrategy),
+		Packs:           make([]models.ConfigPack, len(s.packs)),
+	}
+	for i, pack := range s.packs {
+		config.Packs[i] = models.ConfigPack{Amount: pack.Amount, Label: pack.Label, Pinned: pack.Pinned}
+	}
+
+	return config
+}
+
+// DefaultStrategy returns the strategy used for the orders that don't select one
+func (s *PackStorage) DefaultStrategy(_ context.Context) packing.Strategy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.defaultStrategy
+}
+
+// ImportConfig replaces the packs, the pack limit and the default strategy with the configuration, keeping the recorded
+// orders. A zero soft limit or an empty strategy keeps the current one. The packs with amounts already in use keep
+// their IDs. The configuration is checked like a snapshot restored with the recorded orders, the current state is
+// left untouched if it is invalid.
+func (s *PackStorage) ImportConfig(_ context.Context, config models.PackConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	limit := config.SoftLimit
+	if limit == 0 {
+		limit = s.packLimit()
+	}
+	strategy := s.defaultStrategy
+	if config.DefaultStrategy != "" {
+		parsed, err := packing.ParseStrategy(config.DefaultStrategy)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
+		strategy = parsed
+	}
+
+	nextID := s.nextID
+	packs := make([]*models.Pack, len(config.Packs))
+	for i, configPack := range config.Packs {
+		packs[i] = &models.Pack{Amount: configPack.Amount, Label: normalizeIdentifier(configPack.Label), Pinned: configPack.Pinned}
+		if existing := findPack(s.packs, configPack.Amount); existing != nil {
+			packs[i].ID = existing.ID
+		}
+	}
+	snapshot := models.Snapshot{Packs: packs, Orders: s.orders}
+	if err := s.validateSnapshot(snapshot, limit, ErrInvalidConfig); err != nil {
+		return err
+	}
+
+	byID := make(map[int]*models.Pack, len(packs))
+	for _, pack := range packs {
+		if pack.ID == 0 {
+			pack.ID = nextID
+			nextID++
+		}
+		byID[pack.ID] = pack
+	}
+
+	s.maxPacks = limit
+	s.defaultStrategy = strategy
+	s.packs = packs
+	s.byID = byID
+	s.nextID = nextID
+	s.resortPacks()
+	s.packSetChanged()
+
+	return nil
+}
+
+// validateSnapshot checks the snapshot against the pack limit, the order limit and the pack amount rules of the
+// storage, the errors wrap the invalid error. The caller must hold the lock.
+func (s *PackStorage) validateSnapshot(snapshot models.Snapshot, limit int, invalid error) error {
+	if limit < 0 {
+		return fmt.Errorf("%w: soft limit must not be negative", invalid)
+	}
+	if orderLimit := s.orderLimit(); len(snapshot.Orders) > orderLimit {
+		return fmt.Errorf("%w: %d orders exceed the limit of %d", invalid, len(snapshot.Orders), orderLimit)
+	}
+
+	amounts := make([]int, len(snapshot.Packs))
+	for i, pack := range snapshot.Packs {
+		if pack != nil {
+			amounts[i] = pack.Amount
+		}
+	}
+	// The violations include the amounts AddPack rejects, so they can't get in through a snapshot either
+	if violations := s.packViolations(amounts, limit); len(violations) > 0 {
+		return fmt.Errorf("%w: %s", invalid, violations[0].Message)
+	}
+
+	seenIDs := make(map[int]struct{}, len(snapshot.Packs))
+	for _, pack := range snapshot.Packs {
+		if pack.ID < 0 {
+			return fmt.Errorf("%w: pack IDs must not be negative", invalid)
+		}
+		if pack.ID == 0 {
+			continue
+		}
+		if _, ok := seenIDs[pack.ID]; ok {
+			return fmt.Errorf("%w: duplicate pack ID %d", invalid, pack.ID)
+		}
+		seenIDs[pack.ID] = struct{}{}
+	}
+
+	seenLabels := make(map[string]struct{}, len(snapshot.Packs))
+	for _, pack := range snapshot.Packs {
+		key := identifierKey(pack.Label)
+		if key == "" {
+			continue
+		}
+		if _, ok := seenLabels[key]; ok {
+			return fmt.Errorf("%w: duplicate pack label %q", invalid, normalizeIdentifier(pack.Label))
+		}
+		seenLabels[key] = struct{}{}
+	}
+
+	seenOrderIDs := make(map[int]struct{}, len(snapshot.Orders))
+	for _, order := range snapshot.Orders {
+		if order.ID < 0 {
+			return fmt.Errorf("%w: order IDs must not be negative", invalid)
+		}
+		if order.ID == 0 {
+			continue
+		}
+		if _, ok := seenOrderIDs[order.ID]; ok {
+			return fmt.Errorf("%w: duplicate order ID %d", invalid, order.ID)
+		}
+		seenOrderIDs[order.ID] = struct{}{}
+	}
+
+	// The recorded orders are read without further checks, so their packs must be complete
+	for _, order := range snapshot.Orders {
+		total := 0
+		for _, orderPack := range order.Packs {
+			if orderPack.Pack == nil || orderPack.Pack.Amount <= 0 || orderPack.Quantity <= 0 {
+				return fmt.Errorf("%w: order %d has a pack without a positive amount and quantity", invalid, order.ID)
+			}
+			total += orderPack.Subtotal
+		}
+		if total != order.TotalItems {
+			return fmt.Errorf("%w: order %d packs hold %d items, not its %d total items", invalid, order.ID, total, order.TotalItems)
+		}
+	}
+
+	return nil
+}
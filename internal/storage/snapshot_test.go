@@ -0,0 +1,223 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 500)
+	_, err := storage.CalculateOrder(t.Context(), 600)
+	assert.NoError(t, err)
+
+	snapshot := storage.Snapshot(t.Context())
+	assert.Len(t, snapshot.Packs, 2)
+	assert.Len(t, snapshot.Orders, 1)
+	assert.Equal(t, SoftLimit, snapshot.Config.SoftLimit)
+
+	// Restore into a fresh storage
+	restored := NewPackStorage()
+	err = restored.Restore(t.Context(), snapshot)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.GetPacks(t.Context()), restored.GetPacks(t.Context()))
+	assert.Equal(t, storage.GetOrders(t.Context()), restored.GetOrders(t.Context()))
+
+	// Packs should be sorted after restore
+	err = restored.Restore(t.Context(), models.Snapshot{Packs: []*models.Pack{{Amount: 100}, {Amount: 300}}})
+	assert.NoError(t, err)
+	packs := restored.GetPacks(t.Context())
+	assert.Equal(t, 300, packs[0].Amount)
+	assert.Equal(t, 100, packs[1].Amount)
+	assert.Empty(t, restored.GetOrders(t.Context()))
+}
+
+func TestRestoreOrderIDs(t *testing.T) {
+	storage := NewPackStorage()
+
+	// Orders of older snapshots without IDs get new ones after the highest ID
+	err := storage.Restore(t.Context(), models.Snapshot{
+		Packs:  []*models.Pack{{Amount: 250}},
+		Orders: []models.Order{{ID: 5, RequestedItems: 1}, {RequestedItems: 2}},
+	})
+	assert.NoError(t, err)
+	order, err := storage.CalculateOrder(t.Context(), 100)
+	assert.NoError(t, err)
+
+	var ids []int
+	for _, order := range storage.GetOrders(t.Context()) {
+		ids = append(ids, order.ID)
+	}
+	assert.Equal(t, []int{5, 6, 7}, ids)
+	assert.Equal(t, 7, order.ID)
+
+	err = storage.Restore(t.Context(), models.Snapshot{Orders: []models.Order{{ID: 1}, {ID: 1}}})
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+	err = storage.Restore(t.Context(), models.Snapshot{Orders: []models.Order{{ID: -1}}})
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+}
+
+func TestRestoreInvalidOrders(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+	_, err := storage.CalculateOrder(t.Context(), 600)
+	assert.NoError(t, err)
+	orders := storage.GetOrders(t.Context())
+
+	pack := &models.Pack{ID: 1, Amount: 250}
+	for name, order := range map[string]models.Order{
+		"no pack":           {ID: 1, TotalItems: 250, Packs: []models.OrderPack{{Quantity: 1, Subtotal: 250}}},
+		"zero amount":       {ID: 1, Packs: []models.OrderPack{{Quantity: 1, Pack: &models.Pack{ID: 1}}}},
+		"negative quantity": {ID: 1, TotalItems: -250, Packs: []models.OrderPack{{Quantity: -1, Pack: pack, Subtotal: -250}}},
+		"wrong total":       {ID: 1, TotalItems: 500, Packs: []models.OrderPack{{Quantity: 1, Pack: pack, Subtotal: 250}}},
+	} {
+		err := storage.Restore(t.Context(), models.Snapshot{Packs: []*models.Pack{pack}, Orders: []models.Order{order}})
+		assert.ErrorIs(t, err, ErrInvalidSnapshot, name)
+	}
+	assert.Equal(t, orders, storage.GetOrders(t.Context()))
+
+	// The packs of the recorded orders are safe to read
+	assert.ErrorIs(t, storage.DeleteUnusedPack(t.Context(), 250), ErrPackInUse)
+}
+
+func TestRestoreInvalidSnapshot(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+
+	// Duplicate packs
+	err := storage.Restore(t.Context(), models.Snapshot{Packs: []*models.Pack{{Amount: 100}, {Amount: 100}}})
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+
+	// Labels differing only in case and whitespace
+	err = storage.Restore(t.Context(), models.Snapshot{Packs: []*models.Pack{
+		{Amount: 100, Label: "Small box"},
+		{Amount: 200, Label: " small  BOX"},
+	}})
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+
+	// Non-positive packs
+	err = storage.Restore(t.Context(), models.Snapshot{Packs: []*models.Pack{{Amount: 0}}})
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+
+	// Limit exceeded
+	err = storage.Restore(t.Context(), models.Snapshot{
+		Packs:  []*models.Pack{{Amount: 100}, {Amount: 200}},
+		Config: models.SnapshotConfig{SoftLimit: 1},
+	})
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+
+	// The original state should be untouched
+	packs := storage.GetPacks(t.Context())
+	assert.Len(t, packs, 1)
+	assert.Equal(t, 250, packs[0].Amount)
+}
+
+func TestImportConfig(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 500)
+	_, err := storage.CalculateOrder(t.Context(), 600)
+	assert.NoError(t, err)
+	orders := storage.GetOrders(t.Context())
+
+	err = storage.ImportConfig(t.Context(), models.PackConfig{
+		SoftLimit:       3,
+		DefaultStrategy: "min-overpack",
+		Packs:           []models.ConfigPack{{Amount: 100, Label: " Small "}, {Amount: 500, Pinned: true}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.Pack{{ID: 2, Amount: 500, Pinned: true}, {ID: 3, Amount: 100, Label: "Small"}}, storage.GetPacks(t.Context()))
+	assert.Equal(t, orders, storage.GetOrders(t.Context()))
+	assert.Equal(t, models.PackConfig{
+		SoftLimit:       3,
+		DefaultStrategy: "min-overpack",
+		Packs:           []models.ConfigPack{{Amount: 500, Pinned: true}, {Amount: 100, Label: "Small"}},
+	}, storage.ExportConfig(t.Context()))
+
+	// Nothing changes for invalid configurations
+	version := storage.PackSetVersion(t.Context())
+	for _, config := range []models.PackConfig{
+		{Packs: []models.ConfigPack{{Amount: 100}, {Amount: 200}, {Amount: 300}, {Amount: 400}}},
+		{Packs: []models.ConfigPack{{Amount: 0}}},
+		{DefaultStrategy: "best"},
+		{SoftLimit: -1},
+	} {
+		assert.ErrorIs(t, storage.ImportConfig(t.Context(), config), ErrInvalidConfig)
+	}
+	assert.Equal(t, version, storage.PackSetVersion(t.Context()))
+	assert.Equal(t, 3, storage.ExportConfig(t.Context()).SoftLimit)
+}
+
+func TestImportConfigPackRules(t *testing.T) {
+	storage := NewPackStorage(WithPackMultiple(250), WithMinPackAmount(250))
+	_ = storage.AddPack(t.Context(), 500)
+	version := storage.PackSetVersion(t.Context())
+
+	// The amounts AddPack rejects are rejected before anything changes
+	err := storage.ImportConfig(t.Context(), models.PackConfig{Packs: []models.ConfigPack{{Amount: 250}, {Amount: 7}}})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	assert.ErrorContains(t, err, ErrAmountTooSmall.Error())
+	assert.Equal(t, []int{500}, packAmounts(storage.GetPacks(t.Context())))
+	assert.Equal(t, version, storage.PackSetVersion(t.Context()))
+
+	err = storage.ImportConfig(t.Context(), models.PackConfig{Packs: []models.ConfigPack{{Amount: 250}, {Amount: 750}}})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{750, 250}, packAmounts(storage.GetPacks(t.Context())))
+}
+
+func TestRestorePackMultiple(t *testing.T) {
+	storage := NewPackStorage(WithPackMultiple(250))
+	_ = storage.AddPack(t.Context(), 500)
+
+	err := storage.Restore(t.Context(), models.Snapshot{Packs: []*models.Pack{{Amount: 250}, {Amount: 300}}})
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+	assert.ErrorContains(t, err, ErrInvalidPackMultiple.Error())
+	assert.Equal(t, []int{500}, packAmounts(storage.GetPacks(t.Context())))
+
+	err = storage.Restore(t.Context(), models.Snapshot{Packs: []*models.Pack{{Amount: 250}, {Amount: 750}}})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{750, 250}, packAmounts(storage.GetPacks(t.Context())))
+}
+
+func TestRestoreMinPackAmount(t *testing.T) {
+	storage := NewPackStorage(WithMinPackAmount(250))
+	_ = storage.AddPack(t.Context(), 500)
+	version := storage.PackSetVersion(t.Context())
+
+	err := storage.Restore(t.Context(), models.Snapshot{Packs: []*models.Pack{{Amount: 250}, {Amount: 100}}})
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+	assert.ErrorContains(t, err, ErrAmountTooSmall.Error())
+	err = storage.ImportConfig(t.Context(), models.PackConfig{Packs: []models.ConfigPack{{Amount: 249}}})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	assert.ErrorContains(t, err, ErrAmountTooSmall.Error())
+	assert.Equal(t, []int{500}, packAmounts(storage.GetPacks(t.Context())))
+	assert.Equal(t, version, storage.PackSetVersion(t.Context()))
+}
+
+func TestRestoreKeepsOrderLimit(t *testing.T) {
+	storage := NewPackStorage(WithMaxOrders(1000))
+
+	err := storage.Restore(t.Context(), models.Snapshot{
+		Packs:  []*models.Pack{{Amount: 250}},
+		Config: models.SnapshotConfig{SoftLimit: 5},
+	})
+	assert.NoError(t, err)
+	err = storage.ImportConfig(t.Context(), models.PackConfig{SoftLimit: 3, Packs: []models.ConfigPack{{Amount: 250}}})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, storage.ExportConfig(t.Context()).SoftLimit)
+
+	// Only the pack limit changed, the orders are still limited to 1000
+	for range 10 {
+		_, err := storage.CalculateOrder(t.Context(), 100)
+		assert.NoError(t, err)
+	}
+	assert.Len(t, storage.GetOrders(t.Context()), 10)
+
+	// The orders of a snapshot are checked against the order limit
+	snapshot := storage.Snapshot(t.Context())
+	assert.NoError(t, storage.Restore(t.Context(), snapshot))
+	assert.Len(t, storage.GetOrders(t.Context()), 10)
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"context"
+	"unsafe"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+)
+
+// OrderSummary returns the totals over the recorded orders
+func (s *PackStorage) OrderSummary(_ context.Context) models.OrderSummary {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	summary := models.OrderSummary{Orders: len(s.orders)}
+	var percentSum float64
+	for _, order := range s.orders {
+		summary.RequestedItems += order.RequestedItems
+		summary.ShippedItems += order.TotalItems
+		summary.OverpackedItems += order.OverpackedItems
+		percentSum += order.OverpackPercent
+	}
+	if summary.Orders > 0 {
+		summary.AverageOverpackPercent = s.rounding.Round(percentSum / float64(summary.Orders))
+	}
+
+	return summary
+}
+
+// Stats returns the current usage and limits of the storage and its cache, for ad-hoc debugging
+func (s *PackStorage) Stats(_ context.Context) models.StorageStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := models.StorageStats{
+		Packs:         len(s.packs),
+		Orders:        len(s.orders),
+		MaxPacks:      s.packLimit(),
+		MaxOrders:     s.orderLimit(),
+		MaxOrderPacks: s.maxOrderPacks,
+		OrderHistory:  !s.orderHistoryDisabled,
+		Cache:         s.cache.stats(s.rounding),
+	}
+
+	// The packs are held twice, once more by the solver
+	for _, pack := range s.packs {
+		stats.MemoryBytes += 2 * packMemory(pack)
+	}
+	for _, order := range s.orders {
+		stats.MemoryBytes += orderMemory(order)
+	}
+	stats.MemoryBytes += len(s.history) * int(unsafe.Sizeof(models.PackChange{}))
+	stats.MemoryBytes += s.cache.memory()
+
+	return stats
+}
+
+// packMemory estimates the bytes held by the pack, ignoring the allocator overhead
+func packMemory(pack *models.Pack) int {
+	return int(unsafe.Sizeof(*pack)) + len(pack.Label)
+}
+
+// orderMemory estimates the bytes held by the order, ignoring the allocator and map overhead.
+// The packs of the order are shared with the storage and aren't counted.
+func orderMemory(order models.Order) int {
+	bytes := int(unsafe.Sizeof(order)) + len(order.Reference)
+	bytes += len(order.Packs) * int(unsafe.Sizeof(models.OrderPack{}))
+	bytes += len(order.Cartons) * int(unsafe.Sizeof(models.Carton{}))
+	for key, value := range order.Metadata {
+		bytes += len(key) + len(value)
+	}
+	return bytes
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderSummary(t *testing.T) {
+	storage := NewPackStorage()
+	assert.Equal(t, models.OrderSummary{}, storage.OrderSummary(t.Context()))
+
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 500)
+	_, _ = storage.CalculateOrder(t.Context(), 250) // exact
+	_, _ = storage.CalculateOrder(t.Context(), 200) // 50 overpacked, 25%
+	_, _ = storage.CalculateOrder(t.Context(), 400) // 100 overpacked, 25%
+
+	assert.Equal(t, models.OrderSummary{
+		Orders:                 3,
+		RequestedItems:         850,
+		ShippedItems:           1000,
+		OverpackedItems:        150,
+		AverageOverpackPercent: 16.67,
+	}, storage.OrderSummary(t.Context()))
+}
+
+func TestStats(t *testing.T) {
+	storage := NewPackStorage(WithMaxPacks(5), WithMaxOrders(10))
+	stats := storage.Stats(t.Context())
+	assert.Equal(t, models.StorageStats{
+		MaxPacks:     5,
+		MaxOrders:    10,
+		OrderHistory: true,
+		Cache:        models.CacheStats{Capacity: orderCacheSize},
+	}, stats)
+
+	_, _ = storage.AddLabeledPack(t.Context(), 250, "box")
+	_, _ = storage.CalculateOrder(t.Context(), 100)
+	_, _ = storage.CalculateOrder(t.Context(), 100)
+	_, _ = storage.CalculateOrder(t.Context(), 100)
+	_, _ = storage.QuoteOrder(t.Context(), 300)
+
+	stats = storage.Stats(t.Context())
+	assert.Equal(t, 1, stats.Packs)
+	assert.Equal(t, 3, stats.Orders)
+	// The second and third orders hit the cache
+	assert.Equal(t, models.CacheStats{Entries: 2, Capacity: orderCacheSize, Hits: 2, Misses: 2, HitRatePercent: 50}, stats.Cache)
+	assert.Greater(t, stats.MemoryBytes, 3*orderMemory(storage.GetOrders(t.Context())[0]))
+
+	// Reading the stats changes nothing
+	assert.Equal(t, stats, storage.Stats(t.Context()))
+}
@@ -1,38 +1,201 @@
 package storage
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"maps"
+	"slices"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/packing"
 )
 
 var (
-	ErrPackNotFound     = errors.New("pack not found")
-	ErrNoPacksAvailable = errors.New("no packs available")
+	ErrPackNotFound = errors.New("pack not found")
+	// ErrOrderNotFound is returned when no recorded order has the requested ID
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrNoPacksAvailable means there are no packs configured at all
+	ErrNoPacksAvailable = packing.ErrNoPacksAvailable
+	// ErrUnsatisfiable means there are packs, but the order can't be packed within its constraints
+	ErrUnsatisfiable    = packing.ErrUnsatisfiable
 	ErrPackExists       = errors.New("pack with this amount already exists")
 	ErrSoftLimitReached = errors.New("soft limit reached, cannot add more packs")
-	SoftLimit           = 20 // Soft limit for arrays. Just for demonstration purposes
+	ErrPackPinned       = errors.New("pack is pinned")
+	// ErrPackInUse is returned by DeleteUnusedPack when a recorded order uses the pack amount
+	ErrPackInUse            = errors.New("pack is used by recorded orders")
+	ErrLabelExists          = errors.New("pack with this label already exists")
+	ErrFillerNotFound       = packing.ErrFillerNotFound
+	ErrRequiredPackNotFound = packing.ErrRequiredPackNotFound
+	ErrInvalidAmount        = packing.ErrInvalidAmount
+	// ErrInvalidPackMultiple means the pack amount isn't a multiple of the configured pack multiple
+	ErrInvalidPackMultiple = errors.New("pack amount is not a multiple of the pack multiple")
+	// ErrAmountTooSmall means the pack amount is below the configured minimum pack amount
+	ErrAmountTooSmall = errors.New("pack amount is below the minimum pack amount")
+	// ErrTooManyOrderPacks is returned when an order needs more packs than the configured maximum
+	ErrTooManyOrderPacks = errors.New("order needs too many packs")
+	// ErrComputationTimeout means the context was done or the compute timeout passed before the order was computed
+	ErrComputationTimeout = packing.ErrComputationTimeout
+	// ErrItemsOutOfRange means the totals of the order could overflow with the requested items and the packs
+	ErrItemsOutOfRange = packing.ErrItemsOutOfRange
+	SoftLimit          = 20 // Soft limit for arrays. Just for demonstration purposes
 )
 
+// LimitError reports the number of packs and the limit when the limit is reached.
+// It matches ErrSoftLimitReached with errors.Is.
+type LimitError struct {
+	Count int
+	Limit int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s (%d/%d)", ErrSoftLimitReached, e.Count, e.Limit)
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrSoftLimitReached
+}
+
+// MultipleError reports the pack amount and the pack multiple it isn't a multiple of.
+// It matches ErrInvalidPackMultiple with errors.Is.
+type MultipleError struct {
+	Amount   int
+	Multiple int
+}
+
+func (e *MultipleError) Error() string {
+	return fmt.Sprintf("%s (%d is not a multiple of %d)", ErrInvalidPackMultiple, e.Amount, e.Multiple)
+}
+
+func (e *MultipleError) Unwrap() error {
+	return ErrInvalidPackMultiple
+}
+
+// MinAmountError reports the pack amount and the minimum pack amount it's below.
+// It matches ErrAmountTooSmall with errors.Is.
+type MinAmountError struct {
+	Amount int
+	Min    int
+}
+
+func (e *MinAmountError) Error() string {
+	return fmt.Sprintf("%s (%d is below %d)", ErrAmountTooSmall, e.Amount, e.Min)
+}
+
+func (e *MinAmountError) Unwrap() error {
+	return ErrAmountTooSmall
+}
+
+// OrderPacksError reports the number of packs an order needs and the max number of packs of an order.
+// It matches ErrTooManyOrderPacks with errors.Is.
+type OrderPacksError struct {
+	Count int
+	Limit int
+}
+
+func (e *OrderPacksError) Error() string {
+	return fmt.Sprintf("%s (%d/%d)", ErrTooManyOrderPacks, e.Count, e.Limit)
+}
+
+func (e *OrderPacksError) Unwrap() error {
+	return ErrTooManyOrderPacks
+}
+
 // PackStorage provides an in-memory storage for packs
 type PackStorage struct {
-	packs  []*models.Pack
-	orders []models.Order
-	mu     sync.RWMutex
+	packs []*models.Pack
+	// solverPacks is an immutable copy of the packs for the solver, replaced on every change of the packs.
+	// Orders keep referencing it, so it must never be modified.
+	solverPacks []*models.Pack
+	byID        map[int]*models.Pack
+	nextID      int
+	orders      []models.Order
+	nextOrderID int
+	// history is an append-only log of pack configuration changes
+	history      []models.PackChange
+	packSetState PackSetState
+	// versions holds the most recent pack sets, oldest first, to compute orders against past versions
+	versions []packSetVersion
+	// maxPacks and maxOrders limit the packs and the recorded orders, 0 means SoftLimit
+	maxPacks  int
+	maxOrders int
+	// retention is the max age of the recorded orders, 0 means orders are only limited by count
+	retention time.Duration
+	clock     Clock
+	// defaultStrategy is used for the orders that don't select a strategy
+	defaultStrategy packing.Strategy
+	// rounding rounds the overpack percent of the orders and the percentages of the stats
+	rounding packing.Rounding
+	// computeTimeout bounds the computation of a single order, 0 means no limit besides the context
+	computeTimeout time.Duration
+	// packMultiple restricts the pack amounts to its multiples, 0 means no restriction
+	packMultiple int
+	// minPackAmount rejects smaller pack amounts, 0 means no restriction
+	minPackAmount int
+	// maxOrderPacks is the max number of packs of a single order, 0 means no limit
+	maxOrderPacks int
+	// orderHistoryDisabled computes the orders without recording them
+	orderHistoryDisabled bool
+	// warnOverpackPercent and warnOverpackItems add warnings to the orders overpacking more, 0 means no warning
+	warnOverpackPercent float64
+	warnOverpackItems   int
+	// solve computes the orders, it's replaced in tests to count the computations
+	solve   func(ctx context.Context, packs []*models.Pack, requestedItems int, opts ...packing.Option) (models.Order, error)
+	cache   *orderCache
+	janitor *janitor
+	// subscribers receive the new orders until they unsubscribe or the storage is closed
+	subscribers      map[int]chan models.Order
+	nextSubscriberID int
+	closed           bool
+	mu               sync.RWMutex
 }
 
-// NewPackStorage creates a new instance of PackStorage
-func NewPackStorage() *PackStorage {
+// NewPackStorage creates a new instance of PackStorage configured with the options
+func NewPackStorage(opts ...Option) *PackStorage {
+	config := newConfig(opts)
+	solverPacks := make([]*models.Pack, 0)
 	return &PackStorage{
-		packs:  make([]*models.Pack, 0),
-		orders: make([]models.Order, 0),
+		packs:                make([]*models.Pack, 0),
+		solverPacks:          solverPacks,
+		byID:                 make(map[int]*models.Pack),
+		nextID:               1,
+		nextOrderID:          1,
+		orders:               make([]models.Order, 0),
+		packSetState:         PackSetState{Hash: hashPacks(nil), ModifiedAt: config.Clock.Now()},
+		versions:             []packSetVersion{{packs: solverPacks}},
+		maxPacks:             config.MaxPacks,
+		maxOrders:            config.MaxOrders,
+		retention:            config.Retention,
+		clock:                config.Clock,
+		defaultStrategy:      config.DefaultStrategy,
+		rounding:             config.PercentRounding,
+		computeTimeout:       config.ComputeTimeout,
+		packMultiple:         config.PackMultiple,
+		minPackAmount:        config.MinPackAmount,
+		maxOrderPacks:        config.MaxOrderPacks,
+		orderHistoryDisabled: config.DisableOrderHistory,
+		warnOverpackPercent:  config.WarnOverpackPercent,
+		warnOverpackItems:    config.WarnOverpackItems,
+		solve:                packing.SolveContext,
+		cache:                newOrderCache(),
+		subscribers:          make(map[int]chan models.Order),
 	}
 }
 
+// SetOrderRetention sets the max age of the recorded orders. Older orders are pruned when a new order is recorded.
+// Zero disables time-based retention.
+func (s *PackStorage) SetOrderRetention(_ context.Context, retention time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.retention = retention
+}
+
 // GetPacks returns all available packs
-func (s *PackStorage) GetPacks() []*models.Pack {
+func (s *PackStorage) GetPacks(_ context.Context) []*models.Pack {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -40,256 +203,670 @@ func (s *PackStorage) GetPacks() []*models.Pack {
 }
 
 // AddPack adds a new pack with the specified amount
-func (s *PackStorage) AddPack(amount int) error {
+func (s *PackStorage) AddPack(ctx context.Context, amount int) error {
+	_, err := s.AddLabeledPack(ctx, amount, "")
+	return err
+}
+
+// AddLabeledPack adds a new pack with the specified amount and label. An existing pack keeps its label.
+// The label is normalized and must not be used by another pack, ignoring case.
+// It returns a copy of the added pack, or of the existing pack with the amount.
+func (s *PackStorage) AddLabeledPack(_ context.Context, amount int, label string) (*models.Pack, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	label = normalizeIdentifier(label)
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if err := s.checkPackAmount(amount); err != nil {
+		return nil, err
+	}
+
 	// If amount already exists - do nothing
-	for _, p := range s.packs {
-		if p.Amount == amount {
-			return nil
-		}
+	if existing := findPack(s.packs, amount); existing != nil {
+		return copyPack(existing), nil
 	}
 
-	if len(s.packs) >= SoftLimit {
-		return ErrSoftLimitReached
+	if limit := s.packLimit(); len(s.packs) >= limit {
+		return nil, &LimitError{Count: len(s.packs), Limit: limit}
 	}
+	if s.labelUsed(label, 0) {
+		return nil, ErrLabelExists
+	}
+
+	return copyPack(s.insertPack(amount, label)), nil
+}
 
-	s.packs = append(s.packs, &models.Pack{Amount: amount})
+// insertPack adds a new pack with a fresh ID and records the change, the amount and the label must be checked
+func (s *PackStorage) insertPack(amount int, label string) *models.Pack {
+	pack := &models.Pack{ID: s.nextID, Amount: amount, Label: label}
+	s.nextID++
+	s.packs = append(s.packs, pack)
+	s.byID[pack.ID] = pack
 
 	s.resortPacks()
+	s.recordChange(models.PackChange{Action: models.PackAdded, PackID: pack.ID, NewAmount: amount})
 
-	return nil
+	return pack
 }
 
-// UpdatePack updates a pack's amount
-func (s *PackStorage) UpdatePack(oldAmount, newAmount int) error {
+// AddPacks adds the packs with the specified amounts and reports the outcome for every amount in the same order.
+// Unlike AddPack it doesn't stop at the first failure, the amounts that can be added are added.
+func (s *PackStorage) AddPacks(_ context.Context, amounts []int) []models.PackImportResult {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	results := make([]models.PackImportResult, len(amounts))
+	seen := make(map[int]bool, len(amounts))
+	for i, amount := range amounts {
+		results[i].Amount = amount
+		switch {
+		case amount <= 0 || s.checkPackAmount(amount) != nil:
+			results[i].Status = models.ImportRejectedInvalid
+		case seen[amount]:
+			results[i].Status = models.ImportDuplicateInRequest
+		case findPack(s.packs, amount) != nil:
+			results[i].Status = models.ImportAlreadyExists
+			results[i].ID = findPack(s.packs, amount).ID
+		case len(s.packs) >= s.packLimit():
+			results[i].Status = models.ImportRejectedLimit
+		default:
+			results[i].Status = models.ImportAdded
+			results[i].ID = s.insertPack(amount, "").ID
+		}
+		seen[amount] = true
+	}
+
+	return results
+}
+
+// UpdatePack updates a pack's amount and returns a copy of the updated pack.
+// Updating a pack to its current amount is a successful no-op as long as the pack exists.
+func (s *PackStorage) UpdatePack(_ context.Context, oldAmount, newAmount int) (*models.Pack, error) {
+	if newAmount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkPackAmount(newAmount); err != nil {
+		return nil, err
+	}
+	pack := findPack(s.packs, oldAmount)
+	if pack == nil {
+		return nil, ErrPackNotFound
+	}
+	if oldAmount == newAmount {
+		return copyPack(pack), nil
+	}
+
 	// Check if new amount already exists
-	for _, p := range s.packs {
-		if p.Amount == newAmount {
-			return ErrPackExists
+	if findPack(s.packs, newAmount) != nil {
+		return nil, ErrPackExists
+	}
+
+	pack.Amount = newAmount
+	s.resortPacks()
+	s.recordChange(models.PackChange{Action: models.PackUpdated, PackID: pack.ID, OldAmount: oldAmount, NewAmount: newAmount})
+
+	return copyPack(pack), nil
+}
+
+// UpsertPack updates the pack with the old amount like UpdatePack, or adds a pack with the new amount if there is no
+// pack with the old amount. If neither exists the pack is created within the pack limit, if only the pack with the new
+// amount exists it is returned unchanged, so the call can be repeated. It reports whether the pack was created.
+func (s *PackStorage) UpsertPack(_ context.Context, oldAmount, newAmount int) (*models.Pack, bool, error) {
+	if newAmount <= 0 {
+		return nil, false, ErrInvalidAmount
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkPackAmount(newAmount); err != nil {
+		return nil, false, err
+	}
+	existing := findPack(s.packs, newAmount)
+	pack := findPack(s.packs, oldAmount)
+	switch {
+	case pack == nil && existing != nil:
+		return copyPack(existing), false, nil
+	case pack == nil:
+		if limit := s.packLimit(); len(s.packs) >= limit {
+			return nil, false, &LimitError{Count: len(s.packs), Limit: limit}
 		}
+		return copyPack(s.insertPack(newAmount, "")), true, nil
+	case oldAmount == newAmount:
+		return copyPack(pack), false, nil
+	case existing != nil:
+		return nil, false, ErrPackExists
 	}
 
-	// Find and update the pack
-	for _, p := range s.packs {
-		if p.Amount == oldAmount {
-			p.Amount = newAmount
+	pack.Amount = newAmount
+	s.resortPacks()
+	s.recordChange(models.PackChange{Action: models.PackUpdated, PackID: pack.ID, OldAmount: oldAmount, NewAmount: newAmount})
+
+	return copyPack(pack), false, nil
+}
+
+// UpdatePackByID updates the amount of the pack with the specified ID and returns a copy of the updated pack
+func (s *PackStorage) UpdatePackByID(_ context.Context, id, newAmount int) (*models.Pack, error) {
+	if newAmount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-			s.resortPacks()
+	if err := s.checkPackAmount(newAmount); err != nil {
+		return nil, err
+	}
+	pack, ok := s.byID[id]
+	if !ok {
+		return nil, ErrPackNotFound
+	}
+
+	if pack.Amount == newAmount {
+		return copyPack(pack), nil
+	}
 
-			return nil
+	// Check if new amount already exists on another pack
+	for _, p := range s.packs {
+		if p.Amount == newAmount && p.ID != id {
+			return nil, ErrPackExists
 		}
 	}
 
-	return ErrPackNotFound
+	oldAmount := pack.Amount
+	pack.Amount = newAmount
+	s.resortPacks()
+	s.recordChange(models.PackChange{Action: models.PackUpdated, PackID: id, OldAmount: oldAmount, NewAmount: newAmount})
+
+	return copyPack(pack), nil
 }
 
 // DeletePack removes a pack with the specified amount
-func (s *PackStorage) DeletePack(amount int) error {
+func (s *PackStorage) DeletePack(_ context.Context, amount int) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for i, p := range s.packs {
-		if p.Amount == amount {
-			// Remove the pack
-			s.packs = append(s.packs[:i], s.packs[i+1:]...)
-			return nil
+	return s.deletePack(amount, false)
+}
+
+// DeleteUnusedPack removes the pack with the specified amount like DeletePack, unless one of the recorded orders
+// uses the amount, which fails with ErrPackInUse
+func (s *PackStorage) DeleteUnusedPack(_ context.Context, amount int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.deletePack(amount, true)
+}
+
+// deletePack removes the pack with the amount, refusing to remove it if it's in use by the orders when unusedOnly is set.
+// The caller must hold the lock.
+func (s *PackStorage) deletePack(amount int, unusedOnly bool) error {
+	pack := findPack(s.packs, amount)
+	if pack == nil {
+		return ErrPackNotFound
+	}
+	if pack.Pinned {
+		return ErrPackPinned
+	}
+	if unusedOnly && s.packInUse(amount) {
+		return ErrPackInUse
+	}
+	s.removePack(pack.ID)
+
+	return nil
+}
+
+// packInUse tells whether a recorded order holds packs with the amount. The caller must hold the lock.
+func (s *PackStorage) packInUse(amount int) bool {
+	for _, order := range s.orders {
+		for _, orderPack := range order.Packs {
+			if orderPack.Pack.Amount == amount && orderPack.Quantity > 0 {
+				return true
+			}
 		}
 	}
-	return ErrPackNotFound
+	return false
+}
+
+// DeletePackByID removes the pack with the specified ID
+func (s *PackStorage) DeletePackByID(_ context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pack, ok := s.byID[id]
+	if !ok {
+		return ErrPackNotFound
+	}
+	if pack.Pinned {
+		return ErrPackPinned
+	}
+	s.removePack(id)
+
+	return nil
 }
 
-func (s *PackStorage) GetOrders() []models.Order {
+// GetPack returns the pack with the specified amount
+func (s *PackStorage) GetPack(_ context.Context, amount int) (*models.Pack, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	return s.getOrders()
+	pack := findPack(s.packs, amount)
+	if pack == nil {
+		return nil, ErrPackNotFound
+	}
+
+	return copyPack(pack), nil
 }
 
-// CalculateOrder calculates the optimal packing for the requested items
-func (s *PackStorage) CalculateOrder(requestedItems int) (models.Order, error) {
+// GetPackByID returns the pack with the specified ID
+func (s *PackStorage) GetPackByID(_ context.Context, id int) (*models.Pack, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	if len(s.packs) == 0 {
-		return models.Order{}, ErrNoPacksAvailable
+	pack, ok := s.byID[id]
+	if !ok {
+		return nil, ErrPackNotFound
 	}
 
-	s.resortPacks()
-	packs := s.getPacks()
+	return copyPack(pack), nil
+}
+
+// SetPackPinned pins or unpins the pack with the specified amount and returns it. Pinned packs can't be deleted.
+func (s *PackStorage) SetPackPinned(_ context.Context, amount int, pinned bool) (*models.Pack, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	order := &models.Order{
-		RequestedItems: requestedItems,
-		TotalItems:     0,
-		Packs:          make([]models.OrderPack, 0),
+	pack := findPack(s.packs, amount)
+	if pack == nil {
+		return nil, ErrPackNotFound
 	}
 
-	// Use a greedy algorithm to find the optimal packing
-	// First try to use the largest packs possible
-	remainingItems, order := useFullPacks(packs, order)
-	// If we still have remaining items, use the smallest pack
-	order = s.addPackForRemainingItems(remainingItems, packs, order)
+	if pack.Pinned != pinned {
+		pack.Pinned = pinned
+		action := models.PackPinned
+		if !pinned {
+			action = models.PackUnpinned
+		}
+		s.recordChange(models.PackChange{Action: action, PackID: pack.ID})
+	}
 
-	order.OverpackedItems = order.TotalItems - requestedItems
+	return copyPack(pack), nil
+}
 
-	s.mergePacks(packs, order)
+// SetPackLabel sets the label of the pack with the specified amount and returns it. An empty label removes it.
+// The label is normalized and must not be used by another pack, ignoring case.
+func (s *PackStorage) SetPackLabel(_ context.Context, amount int, label string) (*models.Pack, error) {
+	label = normalizeIdentifier(label)
 
-	// If we've reached the soft limit, keep only the most recent orders
-	if len(s.orders) >= SoftLimit {
-		// Keep only the most recent (SoftLimit - 1) orders to make room for the new one
-		s.orders = s.orders[len(s.orders)-(SoftLimit-1):]
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pack := findPack(s.packs, amount)
+	if pack == nil {
+		return nil, ErrPackNotFound
+	}
+	if s.labelUsed(label, pack.ID) {
+		return nil, ErrLabelExists
 	}
-	// Add the new order to the end of the slice
-	s.orders = append(s.orders, *order)
 
-	return *order, nil
+	if pack.Label != label {
+		pack.Label = label
+		s.recordChange(models.PackChange{Action: models.PackLabeled, PackID: pack.ID})
+	}
+
+	return copyPack(pack), nil
 }
 
-func (s *PackStorage) addPackForRemainingItems(remainingItems int, packs []*models.Pack, order *models.Order) *models.Order {
-	if remainingItems <= 0 {
-		return order
+// labelUsed tells whether a pack other than the one with the specified ID has the label, empty labels are never used
+func (s *PackStorage) labelUsed(label string, id int) bool {
+	if label == "" {
+		return false
 	}
-	smallestPack := packs[len(packs)-1]
-	order.Packs = append(order.Packs, models.OrderPack{
-		Quantity: 1,
-		Pack:     smallestPack,
-	})
-	order.TotalItems += smallestPack.Amount
-
-	return order
+	for _, p := range s.packs {
+		if p.ID != id && sameIdentifier(p.Label, label) {
+			return true
+		}
+	}
+	return false
 }
 
-func (s *PackStorage) mergePacks(packs []*models.Pack, order *models.Order) {
-	// Create ascending sorted pack sizes for merging
-	availablePacks := getSortedPackSizes(packs)
+// GetPackHistory returns the log of pack configuration changes, oldest first
+func (s *PackStorage) GetPackHistory(_ context.Context) []models.PackChange {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]models.PackChange, len(s.history))
+	copy(result, s.history)
 
-	// Try merging multiple times to handle chain merges (e.g., 250+250=500, then 500+500=1000)
-	for range availablePacks {
-		if merged := tryMergeSameSizePacks(availablePacks, order); merged {
-			continue
+	return result
+}
+
+// removePack removes the pack with the specified ID from both the slice and the index
+func (s *PackStorage) removePack(id int) {
+	for i, p := range s.packs {
+		if p.ID == id {
+			s.packs = append(s.packs[:i], s.packs[i+1:]...)
+			s.recordChange(models.PackChange{Action: models.PackDeleted, PackID: id, OldAmount: p.Amount})
+			break
 		}
-		tryMergeDifferentSizePacks(availablePacks, order)
 	}
+	delete(s.byID, id)
 }
 
-func getSortedPackSizes(packs []*models.Pack) []*models.Pack {
-	sorted := make([]*models.Pack, len(packs))
-	copy(sorted, packs)
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].Amount < sorted[j].Amount
-	})
-	return sorted
+// recordChange appends a change to the history, keeping only the most recent entries up to the pack limit.
+// Every change of the packs goes through it, so it also bumps the pack set version.
+func (s *PackStorage) recordChange(change models.PackChange) {
+	s.packSetChanged()
+	s.appendHistory(change)
 }
 
-func tryMergeSameSizePacks(availablePacks []*models.Pack, order *models.Order) bool {
-	// Group packs by size
-	sizeGroups := make(map[int]int)
-	for _, op := range order.Packs {
-		sizeGroups[op.Pack.Amount] += op.Quantity
+// appendHistory appends a change of the current pack set version to the history, keeping only the most recent entries
+// up to the pack limit. Changes applied together share the version of the pack set they resulted in.
+func (s *PackStorage) appendHistory(change models.PackChange) {
+	change.Version = s.packSetState.Version
+	change.Timestamp = s.clock.Now()
+
+	s.history = keepNewest(s.history, s.packLimit()-1)
+	s.history = append(s.history, change)
+}
+
+// keepNewest returns the last limit entries, however many there are beyond it. A non-positive limit keeps none.
+func keepNewest[T any](entries []T, limit int) []T {
+	if excess := len(entries) - max(limit, 0); excess > 0 {
+		return entries[excess:]
 	}
+	return entries
+}
 
-	// Try to merge each group into larger packs
-	for _, targetPack := range availablePacks {
-		for size, count := range sizeGroups {
-			if targetPack.Amount <= size {
-				continue
-			}
+// GetOrders returns the recorded orders oldest first by CreatedAt, orders created at the same time are in the order
+// they were recorded
+func (s *PackStorage) GetOrders(_ context.Context) []models.Order {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-			if targetPack.Amount%size == 0 && count >= targetPack.Amount/size {
-				mergePack(order, size, targetPack.Amount, targetPack.Amount/size)
-				return true
-			}
+	return s.getOrders()
+}
+
+// GetOrder returns the recorded order with the specified ID
+func (s *PackStorage) GetOrder(_ context.Context, id int) (models.Order, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// The recorded orders are limited, so they are scanned instead of indexed
+	for _, order := range s.orders {
+		if order.ID == id {
+			return order, nil
 		}
 	}
-	return false
+
+	return models.Order{}, ErrOrderNotFound
 }
 
-func tryMergeDifferentSizePacks(availablePacks []*models.Pack, order *models.Order) {
-	for _, targetPack := range availablePacks {
-		for _, orderPack := range order.Packs {
-			smallSize := orderPack.Pack.Amount
-			if targetPack.Amount <= smallSize {
-				continue
-			}
+// OrderFilter selects the recorded orders, zero fields are ignored
+type OrderFilter struct {
+	// MinItems and MaxItems are the inclusive range of the requested items
+	MinItems int
+	MaxItems int
+	// Reference is the reference the orders were tagged with, ignoring case and whitespace
+	Reference string
+}
 
-			if targetPack.Amount%smallSize == 0 && orderPack.Quantity >= targetPack.Amount/smallSize {
-				mergePack(order, smallSize, targetPack.Amount, targetPack.Amount/smallSize)
-				return
-			}
-		}
+func (f OrderFilter) matches(order models.Order) bool {
+	if f.MinItems > 0 && order.RequestedItems < f.MinItems {
+		return false
 	}
+	if f.MaxItems > 0 && order.RequestedItems > f.MaxItems {
+		return false
+	}
+	if normalizeIdentifier(f.Reference) != "" && !sameIdentifier(order.Reference, f.Reference) {
+		return false
+	}
+	return true
 }
 
-func mergePack(order *models.Order, fromSize, toSize int, quantity int) {
-	// Remove smaller packs
-	newPacks := make([]models.OrderPack, 0, len(order.Packs))
-	remainingToRemove := quantity
+// FindOrders returns the orders matching the filter, oldest first.
+// The recorded orders are limited, so they are scanned instead of indexed.
+func (s *PackStorage) FindOrders(_ context.Context, filter OrderFilter) []models.Order {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	for _, p := range order.Packs {
-		if p.Pack.Amount == fromSize {
-			if p.Quantity > remainingToRemove {
-				p.Quantity -= remainingToRemove
-				newPacks = append(newPacks, p)
-			}
-			remainingToRemove -= min(remainingToRemove, p.Quantity)
-		} else {
-			newPacks = append(newPacks, p)
+	result := make([]models.Order, 0, len(s.orders))
+	for _, order := range s.orders {
+		if filter.matches(order) {
+			result = append(result, order)
 		}
 	}
 
-	// Add or update larger pack
-	found := false
-	for i := range newPacks {
-		if newPacks[i].Pack.Amount == toSize {
-			newPacks[i].Quantity++
-			found = true
-			break
-		}
+	return result
+}
+
+// GetOrdersByRequestedItems returns the orders with the requested items within the inclusive range.
+// Zero bounds are ignored.
+func (s *PackStorage) GetOrdersByRequestedItems(ctx context.Context, minItems, maxItems int) []models.Order {
+	return s.FindOrders(ctx, OrderFilter{MinItems: minItems, MaxItems: maxItems})
+}
+
+// CalculateOrder calculates the optimal packing for the requested items and records the order, unless the order
+// history is disabled. It fails with ErrComputationTimeout if the context is done before the order is computed.
+func (s *PackStorage) CalculateOrder(ctx context.Context, requestedItems int, opts ...packing.Option) (models.Order, error) {
+	return s.CalculateTaggedOrder(ctx, requestedItems, models.OrderTags{}, opts...)
+}
+
+// CalculateTaggedOrder is CalculateOrder recording the order with the reference and the metadata of the tags
+func (s *PackStorage) CalculateTaggedOrder(ctx context.Context, requestedItems int, tags models.OrderTags, opts ...packing.Option) (models.Order, error) {
+	order, err := s.computeOrder(ctx, requestedItems, opts)
+	if err != nil {
+		return models.Order{}, err
+	}
+	order.Reference = normalizeIdentifier(tags.Reference)
+	// The metadata is copied so the caller can't modify the recorded order
+	order.Metadata = maps.Clone(tags.Metadata)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.orderHistoryDisabled {
+		// Orders that aren't recorded have no ID, like the quotes
+		order.CreatedAt = s.clock.Now()
+		s.publishOrder(order)
+		return order, nil
+	}
+
+	order.ID = s.nextOrderID
+	s.nextOrderID++
+	order.CreatedAt = s.clock.Now()
+	s.pruneExpiredOrders(order.CreatedAt)
+
+	// Keep only the most recent (limit - 1) orders to make room for the new one,
+	// even if the limit was lowered below the number of recorded orders
+	s.orders = keepNewest(s.orders, s.orderLimit()-1)
+	s.insertOrder(order)
+	s.publishOrder(order)
+
+	return order, nil
+}
+
+// insertOrder adds the order after the recorded orders created before or at the same time, keeping the orders sorted
+// by CreatedAt even if the clock goes back
+func (s *PackStorage) insertOrder(order models.Order) {
+	// Usually the order is the newest one and goes to the end
+	i := sort.Search(len(s.orders), func(i int) bool {
+		return s.orders[i].CreatedAt.After(order.CreatedAt)
+	})
+	s.orders = slices.Insert(s.orders, i, order)
+}
+
+// pruneExpiredOrders drops the orders older than the retention period
+func (s *PackStorage) pruneExpiredOrders(now time.Time) {
+	if s.retention <= 0 {
+		return
 	}
 
-	if !found {
-		newPacks = append(newPacks, models.OrderPack{
-			Quantity: 1,
-			Pack:     &models.Pack{Amount: toSize},
-		})
+	// Orders are sorted by CreatedAt, so the expired ones are at the beginning
+	cutoff := now.Add(-s.retention)
+	i := 0
+	for i < len(s.orders) && s.orders[i].CreatedAt.Before(cutoff) {
+		i++
 	}
+	s.orders = s.orders[i:]
+}
 
-	order.Packs = newPacks
+// QuoteOrder calculates the packing totals for the requested items without recording an order
+func (s *PackStorage) QuoteOrder(ctx context.Context, requestedItems int, opts ...packing.Option) (models.Quote, error) {
+	order, err := s.computeOrder(ctx, requestedItems, opts)
+	if err != nil {
+		return models.Quote{}, err
+	}
+
+	return models.Quote{
+		RequestedItems:   order.RequestedItems,
+		OverpackedItems:  order.OverpackedItems,
+		TotalItems:       order.TotalItems,
+		UnfulfilledItems: order.UnfulfilledItems,
+		PackCount:        order.PackCount(),
+	}, nil
 }
 
-// min was added for readability, don't want to deal with math.Min for ints w/o a generics version
-func min(a, b int) int {
-	if a < b {
-		return a
+// ReturnItems returns the packs to disassemble to take the returned items out of packed stock, chosen like the packs
+// of an order of the returned items with the options, without recording an order. The max-under strategy can't
+// cover the items and fails with ErrUnsatisfiable unless it packs them exactly.
+func (s *PackStorage) ReturnItems(ctx context.Context, returnedItems int, opts ...packing.Option) (models.Return, error) {
+	order, err := s.computeOrder(ctx, returnedItems, opts)
+	if err != nil {
+		return models.Return{}, err
+	}
+	if order.UnfulfilledItems > 0 {
+		return models.Return{}, ErrUnsatisfiable
 	}
-	return b
+
+	return models.Return{
+		ReturnedItems:     returnedItems,
+		DisassembledItems: order.TotalItems,
+		LooseItems:        order.OverpackedItems,
+		Packs:             order.Packs,
+	}, nil
 }
 
-// useFullPacks tries to use full packs for the requested items, but can leave some items unfulfilled if no pack fits exactly
-func useFullPacks(packs []*models.Pack, order *models.Order) (int, *models.Order) {
-	remainingItems := order.RequestedItems
+// MinPackCount returns the fewest packs holding the requested items with the least overpack, the pack count of
+// the min-overpack strategy, without recording an order
+func (s *PackStorage) MinPackCount(ctx context.Context, requestedItems int) (int, error) {
+	order, err := s.computeOrder(ctx, requestedItems, []packing.Option{packing.WithStrategy(packing.StrategyMinOverpack)})
+	if err != nil {
+		return 0, err
+	}
 
-	for _, pack := range packs {
-		if pack.Amount <= remainingItems {
-			quantity := remainingItems / pack.Amount
-			if quantity > 0 {
-				order.Packs = append(order.Packs, models.OrderPack{
-					Quantity: quantity,
-					Pack:     pack,
-				})
-				order.TotalItems += quantity * pack.Amount
-				remainingItems -= quantity * pack.Amount
+	return order.PackCount(), nil
+}
+
+// QuoteOrderAtVersion calculates the packing totals for the requested items against the packs as they were
+// at the specified version of the pack set. It fails with ErrVersionNotFound if the version isn't kept.
+func (s *PackStorage) QuoteOrderAtVersion(ctx context.Context, version, requestedItems int, opts ...packing.Option) (models.Quote, error) {
+	if version < 0 {
+		return models.Quote{}, ErrVersionNotFound
+	}
+	order, err := s.computeOrderAt(ctx, version, requestedItems, opts)
+	if err != nil {
+		return models.Quote{}, err
+	}
+
+	return models.Quote{
+		RequestedItems:   order.RequestedItems,
+		OverpackedItems:  order.OverpackedItems,
+		TotalItems:       order.TotalItems,
+		UnfulfilledItems: order.UnfulfilledItems,
+		PackCount:        order.PackCount(),
+	}, nil
+}
+
+// currentVersion selects the current pack set in computeOrderAt
+const currentVersion = -1
+
+// computeOrder calculates the optimal packing for the requested items against the current packs.
+// Results are cached until the pack set changes.
+func (s *PackStorage) computeOrder(ctx context.Context, requestedItems int, opts []packing.Option) (models.Order, error) {
+	return s.computeOrderAt(ctx, currentVersion, requestedItems, opts)
+}
+
+// computeOrderAt calculates the optimal packing for the requested items against the packs of the specified version,
+// or the current packs for currentVersion
+func (s *PackStorage) computeOrderAt(ctx context.Context, version, requestedItems int, opts []packing.Option) (models.Order, error) {
+	opts = s.orderOptions(opts)
+
+	s.mu.RLock()
+	// The solver doesn't modify the packs, so there's no need to copy them
+	packs, ok := s.solverPacks, true
+	if version == currentVersion {
+		version = s.packSetState.Version
+	} else {
+		packs, ok = s.packsAt(version)
+	}
+	if !ok {
+		s.mu.RUnlock()
+		return models.Order{}, ErrVersionNotFound
+	}
+	key := orderCacheKey{version: version, requestedItems: requestedItems, options: packing.OptionsKey(opts...)}
+	cacheable := key.options.Cacheable()
+	if cacheable {
+		if order, ok := s.cache.get(key); ok {
+			s.mu.RUnlock()
+			if err := s.checkOrderPacks(order); err != nil {
+				return models.Order{}, err
 			}
+			return s.addWarnings(order), nil
 		}
 	}
-	return remainingItems, order
+	s.mu.RUnlock()
+
+	if s.computeTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.computeTimeout)
+		defer cancel()
+	}
+
+	order, err := s.solve(ctx, packs, requestedItems, opts...)
+	if err != nil {
+		return models.Order{}, err
+	}
+	order.AvailablePacks = packAmounts(packs)
+	if cacheable {
+		s.cache.put(key, order)
+	}
+	if err := s.checkOrderPacks(order); err != nil {
+		return models.Order{}, err
+	}
+
+	// Warnings are added after caching, so the cached orders come without them
+	return s.addWarnings(order), nil
+}
+
+// packAmounts returns the amounts of the packs in the same order
+func packAmounts(packs []*models.Pack) []int {
+	amounts := make([]int, len(packs))
+	for i, pack := range packs {
+		amounts[i] = pack.Amount
+	}
+	return amounts
+}
+
+// findPack returns the pack with the specified amount or nil if there is none
+func findPack(packs []*models.Pack, amount int) *models.Pack {
+	for _, p := range packs {
+		if p.Amount == amount {
+			return p
+		}
+	}
+	return nil
 }
 
-// resortPacks sorts the packs in descending order by amount
+// resortPacks sorts the packs in descending order by amount.
+// It mutates s.packs in place, so it must only be called under the write lock.
 func (s *PackStorage) resortPacks() {
 	sort.Slice(s.packs, func(i, j int) bool {
 		return s.packs[i].Amount > s.packs[j].Amount
@@ -300,13 +877,18 @@ func (s *PackStorage) getPacks() []*models.Pack {
 	// Return a deep copy to prevent external modifications. Delete copying if moved to external db
 	result := make([]*models.Pack, len(s.packs))
 	for i, pack := range s.packs {
-		// Create a new Pack with the same amount
-		result[i] = &models.Pack{Amount: pack.Amount}
+		result[i] = copyPack(pack)
 	}
 
 	return result
 }
 
+// copyPack returns a copy of the pack with all its fields, so new fields can't be dropped by a copy listing them
+func copyPack(pack *models.Pack) *models.Pack {
+	c := *pack
+	return &c
+}
+
 func (s *PackStorage) getOrders() []models.Order {
 	// Return a copy to prevent external modifications. Delete copying if moved to external db
 	result := make([]models.Order, len(s.orders))
@@ -2,8 +2,10 @@ package storage
 
 import (
 	"testing"
+	"time"
 
 	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/packing"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,30 +20,43 @@ func TestGetPacks(t *testing.T) {
 	storage := NewPackStorage()
 
 	// Test with empty storage
-	packs := storage.GetPacks()
+	packs := storage.GetPacks(t.Context())
 	assert.Empty(t, packs)
 
 	// Add some packs and test again
-	_ = storage.AddPack(100)
-	_ = storage.AddPack(200)
+	_ = storage.AddPack(t.Context(), 100)
+	_ = storage.AddPack(t.Context(), 200)
 
-	packs = storage.GetPacks()
+	packs = storage.GetPacks(t.Context())
 	assert.Len(t, packs, 2)
 	assert.Equal(t, 200, packs[0].Amount) // Packs should be sorted in descending order
 	assert.Equal(t, 100, packs[1].Amount)
 }
 
+func TestGetPacksKeepsAllFields(t *testing.T) {
+	storage := NewPackStorage()
+	_, _ = storage.AddLabeledPack(t.Context(), 5000, "bulk pallet")
+	_, _ = storage.SetPackPinned(t.Context(), 5000, true)
+
+	packs := storage.GetPacks(t.Context())
+	assert.Equal(t, []*models.Pack{{ID: 1, Amount: 5000, Pinned: true, Label: "bulk pallet"}}, packs)
+
+	// The copies don't share memory with the stored packs
+	packs[0].Label = "changed"
+	assert.Equal(t, "bulk pallet", storage.GetPacks(t.Context())[0].Label)
+}
+
 func TestAddPack(t *testing.T) {
 	storage := NewPackStorage()
 
 	// Test normal case
-	err := storage.AddPack(100)
+	err := storage.AddPack(t.Context(), 100)
 	assert.NoError(t, err)
 	assert.Len(t, storage.packs, 1)
 	assert.Equal(t, 100, storage.packs[0].Amount)
 
 	// Test adding duplicate pack
-	err = storage.AddPack(100)
+	err = storage.AddPack(t.Context(), 100)
 	assert.NoError(t, err)
 	assert.Len(t, storage.packs, 1) // Should still have only one pack
 
@@ -51,13 +66,16 @@ func TestAddPack(t *testing.T) {
 	SoftLimit = 2
 	defer func() { SoftLimit = originalLimit }() // Restore original limit after test
 
-	err = storage.AddPack(200)
+	err = storage.AddPack(t.Context(), 200)
 	assert.NoError(t, err)
 	assert.Len(t, storage.packs, 2)
 
 	// Adding one more should hit the soft limit
-	err = storage.AddPack(300)
-	assert.Equal(t, ErrSoftLimitReached, err)
+	err = storage.AddPack(t.Context(), 300)
+	assert.ErrorIs(t, err, ErrSoftLimitReached)
+	var limitErr *LimitError
+	assert.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, LimitError{Count: 2, Limit: 2}, *limitErr)
 	assert.Len(t, storage.packs, 2) // Should still have only two packs
 }
 
@@ -65,71 +83,197 @@ func TestUpdatePack(t *testing.T) {
 	storage := NewPackStorage()
 
 	// Add a pack
-	_ = storage.AddPack(100)
+	_ = storage.AddPack(t.Context(), 100)
 
 	// Test normal case
-	err := storage.UpdatePack(100, 150)
+	pack, err := storage.UpdatePack(t.Context(), 100, 150)
 	assert.NoError(t, err)
+	assert.Equal(t, &models.Pack{ID: 1, Amount: 150}, pack)
 	assert.Len(t, storage.packs, 1)
 	assert.Equal(t, 150, storage.packs[0].Amount)
 
+	// The returned pack is a copy
+	pack.Amount = 175
+	assert.Equal(t, 150, storage.packs[0].Amount)
+
 	// Test updating non-existent pack
-	err = storage.UpdatePack(200, 250)
+	_, err = storage.UpdatePack(t.Context(), 200, 250)
 	assert.Equal(t, ErrPackNotFound, err)
 
 	// Test updating to an amount that already exists
-	_ = storage.AddPack(200)
-	err = storage.UpdatePack(150, 200)
+	_ = storage.AddPack(t.Context(), 200)
+	_, err = storage.UpdatePack(t.Context(), 150, 200)
+	assert.Equal(t, ErrPackExists, err)
+}
+
+func TestUpsertPack(t *testing.T) {
+	defer func(limit int) { SoftLimit = limit }(SoftLimit)
+	SoftLimit = 2
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 100)
+
+	// An existing pack is updated
+	pack, created, err := storage.UpsertPack(t.Context(), 100, 150)
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, &models.Pack{ID: 1, Amount: 150}, pack)
+
+	// Repeating the call returns the updated pack unchanged
+	version := storage.PackSetVersion(t.Context())
+	pack, created, err = storage.UpsertPack(t.Context(), 100, 150)
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, &models.Pack{ID: 1, Amount: 150}, pack)
+	assert.Equal(t, version, storage.PackSetVersion(t.Context()))
+
+	// A missing pack is created
+	pack, created, err = storage.UpsertPack(t.Context(), 200, 250)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, &models.Pack{ID: 2, Amount: 250}, pack)
+	history := storage.GetPackHistory(t.Context())
+	assert.Equal(t, models.PackAdded, history[len(history)-1].Action)
+
+	// Within the pack limit
+	_, _, err = storage.UpsertPack(t.Context(), 300, 350)
+	var limitErr *LimitError
+	assert.ErrorAs(t, err, &limitErr)
+	assert.Len(t, storage.GetPacks(t.Context()), 2)
+
+	// Updating to the amount of another pack still conflicts
+	_, _, err = storage.UpsertPack(t.Context(), 150, 250)
 	assert.Equal(t, ErrPackExists, err)
+	_, _, err = storage.UpsertPack(t.Context(), 150, -1)
+	assert.Equal(t, ErrInvalidAmount, err)
 }
 
 func TestDeletePack(t *testing.T) {
 	storage := NewPackStorage()
 
-	_ = storage.AddPack(100)
-	_ = storage.AddPack(200)
+	_ = storage.AddPack(t.Context(), 100)
+	_ = storage.AddPack(t.Context(), 200)
 
-	err := storage.DeletePack(100)
+	err := storage.DeletePack(t.Context(), 100)
 	assert.NoError(t, err)
 	assert.Len(t, storage.packs, 1)
 	assert.Equal(t, 200, storage.packs[0].Amount)
 }
 
+func TestDeleteUnusedPack(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 500)
+	_ = storage.AddPack(t.Context(), 1000)
+	_, err := storage.CalculateOrder(t.Context(), 750)
+	assert.NoError(t, err)
+
+	// The order holds 500+250, the pack of 1000 is only listed as available
+	assert.ErrorIs(t, storage.DeleteUnusedPack(t.Context(), 500), ErrPackInUse)
+	assert.ErrorIs(t, storage.DeleteUnusedPack(t.Context(), 250), ErrPackInUse)
+	assert.NoError(t, storage.DeleteUnusedPack(t.Context(), 1000))
+	assert.ErrorIs(t, storage.DeleteUnusedPack(t.Context(), 1000), ErrPackNotFound)
+	assert.Equal(t, []int{500, 250}, packAmounts(storage.packs))
+
+	// Deleting without the check still works
+	assert.NoError(t, storage.DeletePack(t.Context(), 500))
+}
+
 func TestGetOrders(t *testing.T) {
 	storage := NewPackStorage()
 
 	// Test with empty storage
-	orders := storage.GetOrders()
+	orders := storage.GetOrders(t.Context())
 	assert.Empty(t, orders)
 
 	// Add a pack and create an order
-	_ = storage.AddPack(100)
-	_, err := storage.CalculateOrder(100)
+	_ = storage.AddPack(t.Context(), 100)
+	_, err := storage.CalculateOrder(t.Context(), 100)
 	assert.NoError(t, err)
 
 	// Test getting orders
-	orders = storage.GetOrders()
+	orders = storage.GetOrders(t.Context())
 	assert.Len(t, orders, 1)
 	assert.Equal(t, 100, orders[0].RequestedItems)
 }
 
+func TestGetOrdersSorted(t *testing.T) {
+	clock := newFakeClock()
+	storage := NewPackStorage(WithClock(clock))
+	_ = storage.AddPack(t.Context(), 250)
+
+	// Orders created at the same time keep the order they were recorded in
+	for _, items := range []int{300, 100, 200} {
+		_, err := storage.CalculateOrder(t.Context(), items)
+		assert.NoError(t, err)
+	}
+	// A clock going back still sorts the order by its time
+	clock.Advance(-time.Minute)
+	_, err := storage.CalculateOrder(t.Context(), 400)
+	assert.NoError(t, err)
+	clock.Advance(2 * time.Minute)
+	_, err = storage.CalculateOrder(t.Context(), 500)
+	assert.NoError(t, err)
+
+	var items, ids []int
+	for _, order := range storage.GetOrders(t.Context()) {
+		items = append(items, order.RequestedItems)
+		ids = append(ids, order.ID)
+	}
+	assert.Equal(t, []int{400, 300, 100, 200, 500}, items)
+	assert.Equal(t, []int{4, 1, 2, 3, 5}, ids)
+
+	// The same holds for restored orders
+	now := clock.Now()
+	err = storage.Restore(t.Context(), models.Snapshot{
+		Packs: []*models.Pack{{Amount: 250}},
+		Orders: []models.Order{
+			{ID: 1, CreatedAt: now},
+			{ID: 2, CreatedAt: now.Add(-time.Hour)},
+			{ID: 3, CreatedAt: now},
+		},
+	})
+	assert.NoError(t, err)
+	ids = nil
+	for _, order := range storage.GetOrders(t.Context()) {
+		ids = append(ids, order.ID)
+	}
+	assert.Equal(t, []int{2, 1, 3}, ids)
+}
+
+func TestGetOrder(t *testing.T) {
+	storage := NewPackStorage(WithMaxOrders(2))
+	_ = storage.AddPack(t.Context(), 250)
+
+	for _, items := range []int{100, 200, 300} {
+		_, _ = storage.CalculateOrder(t.Context(), items)
+	}
+
+	// IDs keep increasing when the oldest orders are dropped
+	order, err := storage.GetOrder(t.Context(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 300, order.RequestedItems)
+
+	_, err = storage.GetOrder(t.Context(), 1)
+	assert.Equal(t, ErrOrderNotFound, err)
+}
+
 func TestCalculateOrder(t *testing.T) {
 	storage := NewPackStorage()
 
 	// Test with no packs available
-	_, err := storage.CalculateOrder(100)
+	_, err := storage.CalculateOrder(t.Context(), 100)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "no packs available")
 
 	// Add some packs
-	_ = storage.AddPack(250)
-	_ = storage.AddPack(500)
-	_ = storage.AddPack(1000)
-	_ = storage.AddPack(2000)
-	_ = storage.AddPack(5000)
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 500)
+	_ = storage.AddPack(t.Context(), 1000)
+	_ = storage.AddPack(t.Context(), 2000)
+	_ = storage.AddPack(t.Context(), 5000)
 
 	// Test exact match
-	order, err := storage.CalculateOrder(500)
+	order, err := storage.CalculateOrder(t.Context(), 500)
 	assert.NoError(t, err)
 	assert.Equal(t, 500, order.RequestedItems)
 	assert.Equal(t, 500, order.TotalItems)
@@ -139,14 +283,14 @@ func TestCalculateOrder(t *testing.T) {
 	assert.Equal(t, 1, order.Packs[0].Quantity)
 
 	// Test using multiple packs
-	order, err = storage.CalculateOrder(1750)
+	order, err = storage.CalculateOrder(t.Context(), 1750)
 	assert.NoError(t, err)
 	assert.Equal(t, 1750, order.RequestedItems)
 	assert.Equal(t, 1750, order.TotalItems)
 	assert.Equal(t, 0, order.OverpackedItems)
 
 	// Test with overpacking
-	order, err = storage.CalculateOrder(1001)
+	order, err = storage.CalculateOrder(t.Context(), 1001)
 	assert.NoError(t, err)
 	assert.Equal(t, 1001, order.RequestedItems)
 	assert.Equal(t, 1250, order.TotalItems)
@@ -158,108 +302,111 @@ func TestCalculateOrder(t *testing.T) {
 	defer func() { SoftLimit = originalLimit }() // Restore original limit after test
 
 	// Create more orders to hit the soft limit
-	_, _ = storage.CalculateOrder(100)
-	_, _ = storage.CalculateOrder(200)
-	_, _ = storage.CalculateOrder(300)
+	_, _ = storage.CalculateOrder(t.Context(), 100)
+	_, _ = storage.CalculateOrder(t.Context(), 200)
+	_, _ = storage.CalculateOrder(t.Context(), 300)
 
 	// Should only keep the latest orders
-	orders := storage.GetOrders()
+	orders := storage.GetOrders(t.Context())
 	assert.Len(t, orders, 2)
 	assert.Equal(t, 200, orders[0].RequestedItems)
 	assert.Equal(t, 300, orders[1].RequestedItems)
 }
 
-func TestAddPackForRemainingItems(t *testing.T) {
+func TestOrderAvailablePacks(t *testing.T) {
 	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 500)
 
-	// Add some packs
-	_ = storage.AddPack(250)
-	_ = storage.AddPack(500)
-
-	packs := storage.GetPacks()
-	order := &models.Order{
-		RequestedItems: 600,
-		TotalItems:     500,
-		Packs: []models.OrderPack{
-			{
-				Quantity: 1,
-				Pack:     &models.Pack{Amount: 500},
-			},
-		},
-	}
-
-	// Test adding a pack for remaining items
-	result := storage.addPackForRemainingItems(100, packs, order)
-	assert.Equal(t, 750, result.TotalItems)
-	assert.Len(t, result.Packs, 2)
-	assert.Equal(t, 250, result.Packs[1].Pack.Amount)
-	assert.Equal(t, 1, result.Packs[1].Quantity)
-
-	// Test with no remaining items
-	order = &models.Order{
-		RequestedItems: 500,
-		TotalItems:     500,
-		Packs: []models.OrderPack{
-			{
-				Quantity: 1,
-				Pack:     &models.Pack{Amount: 500},
-			},
-		},
-	}
+	order, err := storage.CalculateOrder(t.Context(), 251)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{500, 250}, order.AvailablePacks)
 
-	result = storage.addPackForRemainingItems(0, packs, order)
-	assert.Equal(t, 500, result.TotalItems)
-	assert.Len(t, result.Packs, 1)
+	// The recorded orders keep the packs they were packed from after the packs change
+	_ = storage.AddPack(t.Context(), 300)
+	_ = storage.DeletePack(t.Context(), 250)
+	order, err = storage.CalculateOrder(t.Context(), 251)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{500, 300}, order.AvailablePacks)
+	orders := storage.GetOrders(t.Context())
+	assert.Equal(t, []int{500, 250}, orders[0].AvailablePacks)
+	assert.Equal(t, []int{500, 300}, orders[1].AvailablePacks)
 }
 
-func TestUseFullPacks(t *testing.T) {
-	packs := []*models.Pack{
-		{Amount: 5000},
-		{Amount: 2000},
-		{Amount: 1000},
-		{Amount: 500},
-		{Amount: 250},
+func TestOrderLimitLowered(t *testing.T) {
+	defer func(limit int) { SoftLimit = limit }(SoftLimit)
+	SoftLimit = 50
+
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 1)
+	for items := 1; items <= 30; items++ {
+		_, _ = storage.CalculateOrder(t.Context(), items)
+		_ = storage.AddPack(t.Context(), 100+items)
 	}
+	assert.Len(t, storage.GetOrders(t.Context()), 30)
+	assert.Len(t, storage.GetPackHistory(t.Context()), 31)
 
-	order := &models.Order{
-		RequestedItems: 7750,
+	// The next order trims well over the lowered limit at once, down to exactly the limit
+	SoftLimit = 5
+	_, err := storage.CalculateOrder(t.Context(), 31)
+	assert.NoError(t, err)
+	var items []int
+	for _, order := range storage.GetOrders(t.Context()) {
+		items = append(items, order.RequestedItems)
 	}
+	assert.Equal(t, []int{27, 28, 29, 30, 31}, items)
+
+	// The pack history too
+	assert.NoError(t, storage.DeletePack(t.Context(), 130))
+	history := storage.GetPackHistory(t.Context())
+	assert.Len(t, history, 5)
+	assert.Equal(t, 130, history[4].OldAmount)
+
+	// A limit of one only keeps the new order
+	SoftLimit = 1
+	_, _ = storage.CalculateOrder(t.Context(), 32)
+	assert.Len(t, storage.GetOrders(t.Context()), 1)
+}
 
-	// Test using full packs
-	remaining, result := useFullPacks(packs, order)
-	assert.Equal(t, 0, remaining)
-	assert.Equal(t, 7750, result.TotalItems)
-	assert.Len(t, result.Packs, 4)
+func TestKeepNewest(t *testing.T) {
+	entries := []int{1, 2, 3, 4, 5}
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, keepNewest(entries, 10))
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, keepNewest(entries, 5))
+	assert.Equal(t, []int{4, 5}, keepNewest(entries, 2))
+	assert.Empty(t, keepNewest(entries, 0))
+	assert.Empty(t, keepNewest(entries, -1))
+	assert.Empty(t, keepNewest([]int(nil), 3))
+}
 
-	// Verify the packs used
-	packCounts := make(map[int]int)
-	for _, p := range result.Packs {
-		packCounts[p.Pack.Amount] = p.Quantity
-	}
+func TestCalculateTaggedOrder(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
 
-	assert.Equal(t, 1, packCounts[5000])
-	assert.Equal(t, 1, packCounts[2000])
-	assert.Equal(t, 0, packCounts[1000]) // Not used
-	assert.Equal(t, 1, packCounts[500])
-	assert.Equal(t, 1, packCounts[250])
+	metadata := map[string]string{"customer": "ACME"}
+	order, err := storage.CalculateTaggedOrder(t.Context(), 100, models.OrderTags{Reference: "ABC123", Metadata: metadata})
+	assert.NoError(t, err)
+	assert.Equal(t, "ABC123", order.Reference)
+	assert.Equal(t, metadata, order.Metadata)
 
-	// Test with remaining items
-	order = &models.Order{
-		RequestedItems: 7760,
-	}
+	// The recorded order doesn't share the metadata with the caller
+	metadata["customer"] = "Other"
+	orders := storage.GetOrders(t.Context())
+	assert.Equal(t, "ACME", orders[0].Metadata["customer"])
 
-	remaining, result = useFullPacks(packs, order)
-	assert.Equal(t, 10, remaining)
-	assert.Equal(t, 7750, result.TotalItems)
+	// Cached computations don't carry the tags of other orders
+	order, err = storage.CalculateOrder(t.Context(), 100)
+	assert.NoError(t, err)
+	assert.Empty(t, order.Reference)
+	assert.Nil(t, order.Metadata)
 }
 
 func TestResortPacks(t *testing.T) {
 	storage := NewPackStorage()
 
 	// Add packs in random order
-	_ = storage.AddPack(250)
-	_ = storage.AddPack(1000)
-	_ = storage.AddPack(500)
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 1000)
+	_ = storage.AddPack(t.Context(), 500)
 
 	// Verify they are sorted in descending order
 	assert.Equal(t, 1000, storage.packs[0].Amount)
@@ -267,123 +414,724 @@ func TestResortPacks(t *testing.T) {
 	assert.Equal(t, 250, storage.packs[2].Amount)
 
 	// Add another pack and verify sorting is maintained
-	_ = storage.AddPack(2000)
+	_ = storage.AddPack(t.Context(), 2000)
 	assert.Equal(t, 2000, storage.packs[0].Amount)
 	assert.Equal(t, 1000, storage.packs[1].Amount)
 	assert.Equal(t, 500, storage.packs[2].Amount)
 	assert.Equal(t, 250, storage.packs[3].Amount)
 }
 
-func TestMergePacks(t *testing.T) {
+func TestGetPacksReturnsCopy(t *testing.T) {
 	storage := NewPackStorage()
 
-	// Add packs of different sizes
-	_ = storage.AddPack(250)
-	_ = storage.AddPack(500)
-	_ = storage.AddPack(1000)
+	// Add some packs
+	_ = storage.AddPack(t.Context(), 100)
+	_ = storage.AddPack(t.Context(), 200)
 
-	packs := storage.GetPacks()
+	// Get packs and modify the returned slice
+	packs := storage.GetPacks(t.Context())
+	packs[0].Amount = 300
 
-	// Create an order with multiple small packs
-	order := &models.Order{
-		RequestedItems: 1000,
-		TotalItems:     1000,
-		Packs: []models.OrderPack{
-			{
-				Quantity: 2,
-				Pack:     &models.Pack{Amount: 250},
-			},
-			{
-				Quantity: 1,
-				Pack:     &models.Pack{Amount: 500},
-			},
-		},
+	// Get packs again and verify the original values are unchanged
+	packsAgain := storage.GetPacks(t.Context())
+	assert.Equal(t, 200, packsAgain[0].Amount)
+	assert.Equal(t, 100, packsAgain[1].Amount)
+}
+
+func TestGetOrdersReturnsCopy(t *testing.T) {
+	storage := NewPackStorage()
+
+	// Add a pack and create an order
+	_ = storage.AddPack(t.Context(), 100)
+	_, err := storage.CalculateOrder(t.Context(), 100)
+	assert.NoError(t, err)
+
+	// Get orders and modify the returned slice
+	orders := storage.GetOrders(t.Context())
+	orders[0].RequestedItems = 999
+
+	// Get orders again and verify the original values are unchanged
+	ordersAgain := storage.GetOrders(t.Context())
+	assert.Equal(t, 100, ordersAgain[0].RequestedItems)
+}
+
+func TestPackIDs(t *testing.T) {
+	storage := NewPackStorage()
+
+	_ = storage.AddPack(t.Context(), 100)
+	_ = storage.AddPack(t.Context(), 200)
+
+	// IDs are assigned sequentially and survive resorting
+	packs := storage.GetPacks(t.Context())
+	assert.Equal(t, 2, packs[0].ID)
+	assert.Equal(t, 200, packs[0].Amount)
+	assert.Equal(t, 1, packs[1].ID)
+	assert.Equal(t, 100, packs[1].Amount)
+
+	// IDs are stable across amount updates
+	_, err := storage.UpdatePack(t.Context(), 100, 300)
+	assert.NoError(t, err)
+	pack, err := storage.GetPackByID(t.Context(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 300, pack.Amount)
+
+	// IDs are not reused after deletion
+	err = storage.DeletePack(t.Context(), 200)
+	assert.NoError(t, err)
+	_ = storage.AddPack(t.Context(), 400)
+	pack, err = storage.GetPackByID(t.Context(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, pack.Amount)
+}
+
+func TestUpdatePackByID(t *testing.T) {
+	storage := NewPackStorage()
+
+	_ = storage.AddPack(t.Context(), 100)
+	_ = storage.AddPack(t.Context(), 200)
+
+	// Test normal case
+	_, err := storage.UpdatePackByID(t.Context(), 1, 150)
+	assert.NoError(t, err)
+	pack, _ := storage.GetPackByID(t.Context(), 1)
+	assert.Equal(t, 150, pack.Amount)
+
+	// Test updating non-existent pack
+	_, err = storage.UpdatePackByID(t.Context(), 42, 250)
+	assert.Equal(t, ErrPackNotFound, err)
+
+	// Test updating to an amount that already exists
+	_, err = storage.UpdatePackByID(t.Context(), 1, 200)
+	assert.Equal(t, ErrPackExists, err)
+}
+
+func TestDeletePackByID(t *testing.T) {
+	storage := NewPackStorage()
+
+	_ = storage.AddPack(t.Context(), 100)
+	_ = storage.AddPack(t.Context(), 200)
+
+	err := storage.DeletePackByID(t.Context(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, storage.packs, 1)
+	assert.Equal(t, 200, storage.packs[0].Amount)
+
+	_, err = storage.GetPackByID(t.Context(), 1)
+	assert.Equal(t, ErrPackNotFound, err)
+
+	err = storage.DeletePackByID(t.Context(), 1)
+	assert.Equal(t, ErrPackNotFound, err)
+}
+
+func TestSetPackPinned(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 100)
+	version := storage.PackSetVersion(t.Context())
+
+	pack, err := storage.SetPackPinned(t.Context(), 100, true)
+	assert.NoError(t, err)
+	assert.True(t, pack.Pinned)
+	assert.True(t, storage.GetPacks(t.Context())[0].Pinned)
+	assert.Equal(t, version+1, storage.PackSetVersion(t.Context()))
+
+	// Pinned packs can't be deleted by amount or ID
+	assert.Equal(t, ErrPackPinned, storage.DeletePack(t.Context(), 100))
+	assert.Equal(t, ErrPackPinned, storage.DeletePackByID(t.Context(), pack.ID))
+	assert.Len(t, storage.GetPacks(t.Context()), 1)
+
+	// Pinning twice is a no-op
+	_, err = storage.SetPackPinned(t.Context(), 100, true)
+	assert.NoError(t, err)
+	assert.Equal(t, version+1, storage.PackSetVersion(t.Context()))
+
+	pack, err = storage.SetPackPinned(t.Context(), 100, false)
+	assert.NoError(t, err)
+	assert.False(t, pack.Pinned)
+	assert.NoError(t, storage.DeletePack(t.Context(), 100))
+
+	history := storage.GetPackHistory(t.Context())
+	assert.Equal(t, models.PackPinned, history[1].Action)
+	assert.Equal(t, models.PackUnpinned, history[2].Action)
+
+	_, err = storage.SetPackPinned(t.Context(), 100, true)
+	assert.Equal(t, ErrPackNotFound, err)
+}
+
+func TestPackLabels(t *testing.T) {
+	storage := NewPackStorage()
+	pack, err := storage.AddLabeledPack(t.Context(), 5000, "bulk pallet")
+	assert.NoError(t, err)
+	assert.Equal(t, "bulk pallet", pack.Label)
+	assert.Equal(t, 5000, pack.Amount)
+	_ = storage.AddPack(t.Context(), 250)
+	version := storage.PackSetVersion(t.Context())
+
+	// Orders and updated packs keep the labels
+	order, err := storage.CalculateOrder(t.Context(), 5000)
+	assert.NoError(t, err)
+	assert.Equal(t, "bulk pallet", order.Packs[0].Pack.Label)
+	_, err = storage.UpdatePack(t.Context(), 5000, 4000)
+	assert.NoError(t, err)
+	pack, err = storage.GetPack(t.Context(), 4000)
+	assert.NoError(t, err)
+	assert.Equal(t, "bulk pallet", pack.Label)
+
+	pack, err = storage.SetPackLabel(t.Context(), 250, "box")
+	assert.NoError(t, err)
+	assert.Equal(t, "box", pack.Label)
+	assert.Equal(t, version+2, storage.PackSetVersion(t.Context()))
+	assert.Equal(t, models.PackLabeled, storage.GetPackHistory(t.Context())[3].Action)
+
+	// Setting the same label is a no-op, an empty label removes it
+	_, err = storage.SetPackLabel(t.Context(), 250, "box")
+	assert.NoError(t, err)
+	assert.Equal(t, version+2, storage.PackSetVersion(t.Context()))
+	pack, err = storage.SetPackLabel(t.Context(), 250, "")
+	assert.NoError(t, err)
+	assert.Empty(t, pack.Label)
+
+	// Labels don't change the packing
+	order, err = storage.CalculateOrder(t.Context(), 251)
+	assert.NoError(t, err)
+	assert.Equal(t, 500, order.TotalItems)
+
+	_, err = storage.SetPackLabel(t.Context(), 100, "box")
+	assert.Equal(t, ErrPackNotFound, err)
+}
+
+func TestPackLabelsUnique(t *testing.T) {
+	storage := NewPackStorage()
+
+	// Labels are stored normalized
+	pack, err := storage.AddLabeledPack(t.Context(), 250, "  Small   box ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Small box", pack.Label)
+
+	// Labels differing only in case and whitespace are the same label
+	_, err = storage.AddLabeledPack(t.Context(), 500, "SMALL BOX")
+	assert.ErrorIs(t, err, ErrLabelExists)
+	assert.Len(t, storage.GetPacks(t.Context()), 1)
+	_, err = storage.AddLabeledPack(t.Context(), 500, "large box")
+	assert.NoError(t, err)
+	_, err = storage.SetPackLabel(t.Context(), 500, "small\tBox")
+	assert.ErrorIs(t, err, ErrLabelExists)
+
+	// A pack can change the case of its own label, empty labels are never duplicates
+	pack, err = storage.SetPackLabel(t.Context(), 250, "small box")
+	assert.NoError(t, err)
+	assert.Equal(t, "small box", pack.Label)
+	_, err = storage.SetPackLabel(t.Context(), 250, " ")
+	assert.NoError(t, err)
+	_, err = storage.AddLabeledPack(t.Context(), 1000, "")
+	assert.NoError(t, err)
+
+	// Adding an existing amount returns the existing pack with its label
+	pack, err = storage.AddLabeledPack(t.Context(), 500, "other box")
+	assert.NoError(t, err)
+	assert.Equal(t, "large box", pack.Label)
+}
+
+func TestGetPackHistory(t *testing.T) {
+	storage := NewPackStorage()
+
+	// Test with empty storage
+	assert.Empty(t, storage.GetPackHistory(t.Context()))
+
+	_ = storage.AddPack(t.Context(), 100)
+	_ = storage.AddPack(t.Context(), 100) // Duplicate is a no-op and isn't recorded
+	_, _ = storage.UpdatePack(t.Context(), 100, 150)
+	_ = storage.AddPack(t.Context(), 200)
+	_ = storage.DeletePack(t.Context(), 200)
+	_, _ = storage.UpdatePack(t.Context(), 300, 400) // Failed update isn't recorded
+
+	history := storage.GetPackHistory(t.Context())
+	assert.Len(t, history, 4)
+
+	assert.Equal(t, models.PackAdded, history[0].Action)
+	assert.Equal(t, 100, history[0].NewAmount)
+
+	assert.Equal(t, models.PackUpdated, history[1].Action)
+	assert.Equal(t, 1, history[1].PackID)
+	assert.Equal(t, 100, history[1].OldAmount)
+	assert.Equal(t, 150, history[1].NewAmount)
+
+	assert.Equal(t, models.PackAdded, history[2].Action)
+	assert.Equal(t, models.PackDeleted, history[3].Action)
+	assert.Equal(t, 200, history[3].OldAmount)
+
+	for i, change := range history {
+		assert.False(t, change.Timestamp.IsZero())
+		assert.Equal(t, i+1, change.Version)
 	}
 
-	// Test merging packs
-	storage.mergePacks(packs, order)
+	// Test soft limit for history
+	originalLimit := SoftLimit
+	SoftLimit = 2
+	defer func() { SoftLimit = originalLimit }() // Restore original limit after test
 
-	// Verify that 2x250 packs were merged into 1x500 pack
-	// and 1x500 + 1x500 were merged into 1x1000 pack
-	assert.Len(t, order.Packs, 1)
-	assert.Equal(t, 1000, order.Packs[0].Pack.Amount)
-	assert.Equal(t, 1, order.Packs[0].Quantity)
+	_, _ = storage.UpdatePack(t.Context(), 150, 175)
+	history = storage.GetPackHistory(t.Context())
+	assert.Len(t, history, 2)
+	assert.Equal(t, models.PackDeleted, history[0].Action)
+	assert.Equal(t, 175, history[1].NewAmount)
+}
 
-	// Test with packs that can't be merged
-	order = &models.Order{
-		RequestedItems: 750,
-		TotalItems:     750,
-		Packs: []models.OrderPack{
-			{
-				Quantity: 1,
-				Pack:     &models.Pack{Amount: 500},
-			},
-			{
-				Quantity: 1,
-				Pack:     &models.Pack{Amount: 250},
-			},
-		},
+func TestPackHistoryFollowsPackLimit(t *testing.T) {
+	storage := NewPackStorage(WithMaxPacks(3))
+	for _, amount := range []int{100, 200, 300} {
+		_ = storage.AddPack(t.Context(), amount)
 	}
+	_ = storage.DeletePack(t.Context(), 100)
+	_, _ = storage.UpdatePack(t.Context(), 200, 250)
 
-	storage.mergePacks(packs, order)
-
-	// Verify that packs remain unchanged (can't merge 500+250 into any available pack)
-	assert.Len(t, order.Packs, 2)
-
-	// Test with single quantity packs (should not be merged)
-	order = &models.Order{
-		RequestedItems: 500,
-		TotalItems:     500,
-		Packs: []models.OrderPack{
-			{
-				Quantity: 1,
-				Pack:     &models.Pack{Amount: 250},
-			},
-			{
-				Quantity: 1,
-				Pack:     &models.Pack{Amount: 250},
-			},
-		},
+	history := storage.GetPackHistory(t.Context())
+	assert.Len(t, history, 3)
+	assert.Equal(t, 3, history[0].Version)
+
+	// A restored snapshot changes the limit the history follows
+	err := storage.Restore(t.Context(), models.Snapshot{Config: models.SnapshotConfig{SoftLimit: 5}})
+	assert.NoError(t, err)
+	for _, amount := range []int{100, 200, 300, 400, 500} {
+		_ = storage.AddPack(t.Context(), amount)
 	}
+	assert.Len(t, storage.GetPackHistory(t.Context()), 5)
+}
 
-	storage.mergePacks(packs, order)
+func TestCalculateOrderNoPacksVsUnsatisfiable(t *testing.T) {
+	storage := NewPackStorage()
 
-	// Verify that packs were merged (2x250 into 1x500)
-	assert.Len(t, order.Packs, 1)
-	assert.Equal(t, 500, order.Packs[0].Pack.Amount)
-	assert.Equal(t, 1, order.Packs[0].Quantity)
+	// Nothing to pack with
+	_, err := storage.CalculateOrder(t.Context(), 500, packing.WithMaxQuantityPerSize(1))
+	assert.ErrorIs(t, err, ErrNoPacksAvailable)
+	assert.NotErrorIs(t, err, ErrUnsatisfiable)
+
+	// There are packs, but not enough of them within the cap
+	_ = storage.AddPack(t.Context(), 250)
+	_, err = storage.CalculateOrder(t.Context(), 500, packing.WithMaxQuantityPerSize(1))
+	assert.ErrorIs(t, err, ErrUnsatisfiable)
+	assert.NotErrorIs(t, err, ErrNoPacksAvailable)
+	assert.Empty(t, storage.GetOrders(t.Context()))
 }
 
-func TestGetPacksReturnsCopy(t *testing.T) {
+func TestCalculateOrderWithFillerPack(t *testing.T) {
 	storage := NewPackStorage()
 
-	// Add some packs
-	_ = storage.AddPack(100)
-	_ = storage.AddPack(200)
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 500)
+	_ = storage.AddPack(t.Context(), 1000)
 
-	// Get packs and modify the returned slice
-	packs := storage.GetPacks()
-	packs[0].Amount = 300
+	// By default the smallest pack is used to fill the remaining items
+	order, err := storage.CalculateOrder(t.Context(), 1001)
+	assert.NoError(t, err)
+	assert.Equal(t, 1250, order.TotalItems)
+	assert.Equal(t, 249, order.OverpackedItems)
 
-	// Get packs again and verify the original values are unchanged
-	packsAgain := storage.GetPacks()
-	assert.Equal(t, 200, packsAgain[0].Amount)
-	assert.Equal(t, 100, packsAgain[1].Amount)
+	// Forced filler overpacks more, but fills the remainder with the requested size
+	order, err = storage.CalculateOrder(t.Context(), 1001, packing.WithFillerPack(500))
+	assert.NoError(t, err)
+	assert.Equal(t, 1500, order.TotalItems)
+	assert.Equal(t, 499, order.OverpackedItems)
+
+	packCounts := make(map[int]int)
+	for _, p := range order.Packs {
+		packCounts[p.Pack.Amount] = p.Quantity
+	}
+	assert.Equal(t, 1, packCounts[1000])
+	assert.Equal(t, 1, packCounts[500])
+	assert.Equal(t, 0, packCounts[250])
+
+	// Filler must be an existing pack
+	_, err = storage.CalculateOrder(t.Context(), 1001, packing.WithFillerPack(300))
+	assert.Equal(t, ErrFillerNotFound, err)
 }
 
-func TestGetOrdersReturnsCopy(t *testing.T) {
+func TestOrderPackSubtotal(t *testing.T) {
 	storage := NewPackStorage()
 
-	// Add a pack and create an order
-	_ = storage.AddPack(100)
-	_, err := storage.CalculateOrder(100)
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 500)
+	_ = storage.AddPack(t.Context(), 1000)
+
+	// The subtotals are the quantity times the amount and add up to the total, also for the orders with merged packs
+	for _, amount := range []int{1, 1001, 1750, 2251} {
+		order, err := storage.CalculateOrder(t.Context(), amount)
+		assert.NoError(t, err)
+
+		total := 0
+		for _, p := range order.Packs {
+			assert.Equal(t, p.Quantity*p.Pack.Amount, p.Subtotal)
+			total += p.Subtotal
+		}
+		assert.Equal(t, order.TotalItems, total)
+	}
+}
+
+func TestGetPack(t *testing.T) {
+	storage := NewPackStorage()
+
+	_ = storage.AddPack(t.Context(), 100)
+
+	pack, err := storage.GetPack(t.Context(), 100)
 	assert.NoError(t, err)
+	assert.Equal(t, 100, pack.Amount)
+	assert.Equal(t, 1, pack.ID)
 
-	// Get orders and modify the returned slice
-	orders := storage.GetOrders()
-	orders[0].RequestedItems = 999
+	_, err = storage.GetPack(t.Context(), 200)
+	assert.Equal(t, ErrPackNotFound, err)
+}
 
-	// Get orders again and verify the original values are unchanged
-	ordersAgain := storage.GetOrders()
-	assert.Equal(t, 100, ordersAgain[0].RequestedItems)
+func TestQuoteOrder(t *testing.T) {
+	storage := NewPackStorage()
+
+	// Test with no packs available
+	_, err := storage.QuoteOrder(t.Context(), 100)
+	assert.Equal(t, ErrNoPacksAvailable, err)
+
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 500)
+	_ = storage.AddPack(t.Context(), 1000)
+
+	quote, err := storage.QuoteOrder(t.Context(), 1001)
+	assert.NoError(t, err)
+	assert.Equal(t, 1001, quote.RequestedItems)
+	assert.Equal(t, 1250, quote.TotalItems)
+	assert.Equal(t, 249, quote.OverpackedItems)
+	assert.Equal(t, 2, quote.PackCount)
+
+	// Quotes aren't recorded
+	assert.Empty(t, storage.GetOrders(t.Context()))
+}
+
+func TestMinPackCount(t *testing.T) {
+	storage := NewPackStorage()
+	_, err := storage.MinPackCount(t.Context(), 11)
+	assert.ErrorIs(t, err, ErrNoPacksAvailable)
+
+	_ = storage.AddPack(t.Context(), 4)
+	_ = storage.AddPack(t.Context(), 6)
+	_ = storage.AddPack(t.Context(), 9)
+
+	// 12 items are the least overpack for 11 items, 6+6 the fewest packs holding them
+	count, err := storage.MinPackCount(t.Context(), 11)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	count, err = storage.MinPackCount(t.Context(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	count, err = storage.MinPackCount(t.Context(), 22)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	assert.Empty(t, storage.GetOrders(t.Context()))
+}
+
+func TestReturnItems(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 500)
+	_ = storage.AddPack(t.Context(), 1000)
+
+	// A 500 pack and a 250 pack are opened for 700 items, 50 are left over
+	result, err := storage.ReturnItems(t.Context(), 700)
+	assert.NoError(t, err)
+	assert.Equal(t, 700, result.ReturnedItems)
+	assert.Equal(t, 750, result.DisassembledItems)
+	assert.Equal(t, 50, result.LooseItems)
+	assert.Len(t, result.Packs, 2)
+	assert.Equal(t, 500, result.Packs[0].Pack.Amount)
+	assert.Equal(t, 250, result.Packs[1].Pack.Amount)
+	assert.Empty(t, storage.GetOrders(t.Context()))
+
+	// Max-under can't take out more items than packed exactly
+	_, err = storage.ReturnItems(t.Context(), 700, packing.WithStrategy(packing.StrategyMaxUnder))
+	assert.ErrorIs(t, err, ErrUnsatisfiable)
+	result, err = storage.ReturnItems(t.Context(), 750, packing.WithStrategy(packing.StrategyMaxUnder))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.LooseItems)
+}
+
+func TestOrderRetention(t *testing.T) {
+	clock := newFakeClock()
+	storage := NewPackStorage(WithClock(clock))
+	_ = storage.AddPack(t.Context(), 100)
+
+	// Retention is disabled by default
+	_, _ = storage.CalculateOrder(t.Context(), 100)
+	clock.Advance(48 * time.Hour)
+	_, _ = storage.CalculateOrder(t.Context(), 200)
+	assert.Len(t, storage.GetOrders(t.Context()), 2)
+
+	storage.SetOrderRetention(t.Context(), 24*time.Hour)
+
+	// Within the retention window nothing is dropped except the order older than 24h
+	clock.Advance(time.Hour)
+	_, _ = storage.CalculateOrder(t.Context(), 300)
+	orders := storage.GetOrders(t.Context())
+	assert.Len(t, orders, 2)
+	assert.Equal(t, 200, orders[0].RequestedItems)
+	assert.Equal(t, 300, orders[1].RequestedItems)
+	assert.Equal(t, clock.Now(), orders[1].CreatedAt)
+
+	// Advance past the retention window of both orders
+	clock.Advance(25 * time.Hour)
+	_, _ = storage.CalculateOrder(t.Context(), 400)
+	orders = storage.GetOrders(t.Context())
+	assert.Len(t, orders, 1)
+	assert.Equal(t, 400, orders[0].RequestedItems)
+}
+
+func TestUpdatePackToSameAmount(t *testing.T) {
+	storage := NewPackStorage()
+
+	_ = storage.AddPack(t.Context(), 100)
+
+	// Updating to the same amount is a no-op rather than a conflict with itself
+	_, err := storage.UpdatePack(t.Context(), 100, 100)
+	assert.NoError(t, err)
+	_, err = storage.UpdatePackByID(t.Context(), 1, 100)
+	assert.NoError(t, err)
+	assert.Len(t, storage.GetPackHistory(t.Context()), 1) // Only the add is recorded
+
+	// The pack still has to exist
+	_, err = storage.UpdatePack(t.Context(), 200, 200)
+	assert.Equal(t, ErrPackNotFound, err)
+}
+
+func TestInvalidPackAmounts(t *testing.T) {
+	storage := NewPackStorage()
+
+	for _, amount := range []int{0, -1, -250} {
+		err := storage.AddPack(t.Context(), amount)
+		assert.Equal(t, ErrInvalidAmount, err)
+	}
+	assert.Empty(t, storage.GetPacks(t.Context()))
+
+	_ = storage.AddPack(t.Context(), 100)
+
+	_, err := storage.UpdatePack(t.Context(), 100, 0)
+	assert.Equal(t, ErrInvalidAmount, err)
+	_, err = storage.UpdatePack(t.Context(), 100, -100)
+	assert.Equal(t, ErrInvalidAmount, err)
+	_, err = storage.UpdatePackByID(t.Context(), 1, -1)
+	assert.Equal(t, ErrInvalidAmount, err)
+
+	packs := storage.GetPacks(t.Context())
+	assert.Len(t, packs, 1)
+	assert.Equal(t, 100, packs[0].Amount)
+}
+
+func TestGetPacksWithState(t *testing.T) {
+	clock := newFakeClock()
+	storage := NewPackStorage(WithClock(clock))
+
+	_, empty := storage.GetPacksWithState(t.Context())
+	assert.Equal(t, 0, storage.PackSetVersion(t.Context()))
+	assert.Equal(t, clock.Now(), empty.ModifiedAt)
+
+	clock.Advance(time.Minute)
+	_ = storage.AddPack(t.Context(), 100)
+	packs, state := storage.GetPacksWithState(t.Context())
+	assert.Len(t, packs, 1)
+	assert.NotEqual(t, empty.Hash, state.Hash)
+	assert.Equal(t, 1, state.Version)
+	assert.Equal(t, 1, storage.PackSetVersion(t.Context()))
+	assert.Equal(t, clock.Now(), state.ModifiedAt)
+
+	// No-op changes don't change the state
+	clock.Advance(time.Hour)
+	_ = storage.AddPack(t.Context(), 100)
+	_, _ = storage.UpdatePack(t.Context(), 100, 100)
+	_, same := storage.GetPacksWithState(t.Context())
+	assert.Equal(t, state, same)
+
+	// Orders don't change the pack set
+	_, _ = storage.CalculateOrder(t.Context(), 100)
+	_, same = storage.GetPacksWithState(t.Context())
+	assert.Equal(t, state, same)
+
+	_, _ = storage.UpdatePack(t.Context(), 100, 200)
+	_, updated := storage.GetPacksWithState(t.Context())
+	assert.NotEqual(t, state.Hash, updated.Hash)
+	assert.Equal(t, 2, updated.Version)
+	assert.Equal(t, clock.Now(), updated.ModifiedAt)
+
+	// Version keeps increasing even if the pack set is back to a previous state
+	_ = storage.DeletePack(t.Context(), 200)
+	_, deleted := storage.GetPacksWithState(t.Context())
+	assert.Equal(t, empty.Hash, deleted.Hash)
+	assert.Equal(t, 3, deleted.Version)
+
+	// Restores bump the version too
+	err := storage.Restore(t.Context(), storage.Snapshot(t.Context()))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, storage.PackSetVersion(t.Context()))
+}
+
+func TestPacksAtVersion(t *testing.T) {
+	storage := NewPackStorage()
+
+	packs, err := storage.PacksAtVersion(t.Context(), 0)
+	assert.NoError(t, err)
+	assert.Empty(t, packs)
+
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 500)
+	_, _ = storage.UpdatePack(t.Context(), 250, 300)
+	_, _ = storage.SetPackPinned(t.Context(), 500, true)
+
+	packs, err = storage.PacksAtVersion(t.Context(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.Pack{{ID: 2, Amount: 500}, {ID: 1, Amount: 250}}, packs)
+
+	packs, err = storage.PacksAtVersion(t.Context(), 4)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.GetPacks(t.Context()), packs)
+
+	// The returned packs are copies
+	packs[0].Amount = 1
+	packs, _ = storage.PacksAtVersion(t.Context(), 4)
+	assert.Equal(t, 500, packs[0].Amount)
+
+	_, err = storage.PacksAtVersion(t.Context(), 5)
+	assert.ErrorIs(t, err, ErrVersionNotFound)
+
+	// Only the most recent versions up to the pack limit are kept
+	originalLimit := SoftLimit
+	SoftLimit = 2
+	defer func() { SoftLimit = originalLimit }()
+
+	_, _ = storage.UpdatePack(t.Context(), 300, 1000)
+	_, err = storage.PacksAtVersion(t.Context(), 3)
+	assert.ErrorIs(t, err, ErrVersionNotFound)
+	_, err = storage.PacksAtVersion(t.Context(), 4)
+	assert.NoError(t, err)
+}
+
+func TestPacksAtVersionFollowsPackLimit(t *testing.T) {
+	storage := NewPackStorage(WithMaxPacks(3))
+	for _, amount := range []int{100, 200, 300} {
+		_ = storage.AddPack(t.Context(), amount)
+	}
+	_ = storage.DeletePack(t.Context(), 100)
+
+	// Versions 2 to 4 are kept
+	_, err := storage.PacksAtVersion(t.Context(), 1)
+	assert.ErrorIs(t, err, ErrVersionNotFound)
+	packs, err := storage.PacksAtVersion(t.Context(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{200, 100}, packAmounts(packs))
+}
+
+func TestQuoteOrderAtVersion(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+	_ = storage.AddPack(t.Context(), 500)
+	_ = storage.DeletePack(t.Context(), 250)
+
+	quote, err := storage.QuoteOrderAtVersion(t.Context(), 2, 251)
+	assert.NoError(t, err)
+	assert.Equal(t, 500, quote.TotalItems)
+
+	// 251 items took 2 packs of 250 before the 500 pack was added
+	quote, err = storage.QuoteOrderAtVersion(t.Context(), 1, 251)
+	assert.NoError(t, err)
+	assert.Equal(t, 500, quote.TotalItems)
+	quote, err = storage.QuoteOrderAtVersion(t.Context(), 2, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 250, quote.TotalItems)
+
+	// The current pack set doesn't have the 250 pack anymore
+	quote, err = storage.QuoteOrderAtVersion(t.Context(), 3, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 500, quote.TotalItems)
+
+	_, err = storage.QuoteOrderAtVersion(t.Context(), 0, 100)
+	assert.ErrorIs(t, err, ErrNoPacksAvailable)
+	_, err = storage.QuoteOrderAtVersion(t.Context(), 4, 100)
+	assert.ErrorIs(t, err, ErrVersionNotFound)
+	_, err = storage.QuoteOrderAtVersion(t.Context(), -1, 100)
+	assert.ErrorIs(t, err, ErrVersionNotFound)
+
+	// Quotes don't record orders
+	assert.Empty(t, storage.GetOrders(t.Context()))
+}
+
+func TestGetOrdersByRequestedItems(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+	for _, items := range []int{100, 500, 1000} {
+		_, _ = storage.CalculateOrder(t.Context(), items)
+	}
+
+	assert.Len(t, storage.GetOrdersByRequestedItems(t.Context(), 0, 0), 3)
+	assert.Len(t, storage.GetOrdersByRequestedItems(t.Context(), 500, 0), 2)
+	assert.Len(t, storage.GetOrdersByRequestedItems(t.Context(), 0, 500), 2)
+
+	orders := storage.GetOrdersByRequestedItems(t.Context(), 500, 500)
+	assert.Len(t, orders, 1)
+	assert.Equal(t, 500, orders[0].RequestedItems)
+
+	orders = storage.GetOrdersByRequestedItems(t.Context(), 600, 900)
+	assert.NotNil(t, orders)
+	assert.Empty(t, orders)
+}
+
+func TestFindOrders(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+	_, _ = storage.CalculateTaggedOrder(t.Context(), 100, models.OrderTags{Reference: "ABC123"})
+	_, _ = storage.CalculateTaggedOrder(t.Context(), 500, models.OrderTags{Reference: "XYZ"})
+	_, _ = storage.CalculateTaggedOrder(t.Context(), 1000, models.OrderTags{Reference: "ABC123"})
+	_, _ = storage.CalculateOrder(t.Context(), 1000)
+
+	assert.Len(t, storage.FindOrders(t.Context(), OrderFilter{}), 4)
+
+	orders := storage.FindOrders(t.Context(), OrderFilter{Reference: "ABC123"})
+	assert.Len(t, orders, 2)
+	assert.Equal(t, 100, orders[0].RequestedItems)
+	assert.Equal(t, 1000, orders[1].RequestedItems)
+
+	orders = storage.FindOrders(t.Context(), OrderFilter{Reference: "ABC123", MinItems: 500})
+	assert.Len(t, orders, 1)
+	assert.Equal(t, 1000, orders[0].RequestedItems)
+
+	// The reference matches ignoring case and whitespace
+	orders = storage.FindOrders(t.Context(), OrderFilter{Reference: "  abc123 "})
+	assert.Len(t, orders, 2)
+
+	orders = storage.FindOrders(t.Context(), OrderFilter{Reference: "ABC"})
+	assert.NotNil(t, orders)
+	assert.Empty(t, orders)
+
+	// References are stored normalized
+	order, err := storage.CalculateTaggedOrder(t.Context(), 100, models.OrderTags{Reference: " ABC  456 "})
+	assert.NoError(t, err)
+	assert.Equal(t, "ABC 456", order.Reference)
+}
+
+func TestAddPacks(t *testing.T) {
+	defer func(limit int) { SoftLimit = limit }(SoftLimit)
+	SoftLimit = 4
+
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+
+	results := storage.AddPacks(t.Context(), []int{1000, 250, 500, 1000, -1, 2000, 5000})
+	assert.Equal(t, []models.PackImportResult{
+		{Amount: 1000, Status: models.ImportAdded, ID: 2},
+		{Amount: 250, Status: models.ImportAlreadyExists, ID: 1},
+		{Amount: 500, Status: models.ImportAdded, ID: 3},
+		{Amount: 1000, Status: models.ImportDuplicateInRequest},
+		{Amount: -1, Status: models.ImportRejectedInvalid},
+		{Amount: 2000, Status: models.ImportAdded, ID: 4},
+		{Amount: 5000, Status: models.ImportRejectedLimit},
+	}, results)
+
+	packs := storage.GetPacks(t.Context())
+	assert.Len(t, packs, 4)
+	assert.Equal(t, 2000, packs[0].Amount)
+	assert.Equal(t, 250, packs[3].Amount)
+	assert.Len(t, storage.GetPackHistory(t.Context()), 4)
+
+	assert.Empty(t, storage.AddPacks(t.Context(), nil))
 }
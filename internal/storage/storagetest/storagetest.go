@@ -0,0 +1,62 @@
+// Package storagetest provides fixtures for tests working with a storage.PackStorage.
+// It's only meant to be imported from tests.
+package storagetest
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/storage"
+)
+
+// DefaultPacks are the pack amounts the service starts with, the amounts of the default preset smallest first
+var DefaultPacks = defaultPacks()
+
+// DefaultOrders are the requested items of the orders recorded by NewStorageWithOrders, oldest first
+var DefaultOrders = []int{1, 250, 251, 501, 12001}
+
+// NewStorage returns a storage created with the options and holding the DefaultPacks
+func NewStorage(t testing.TB, opts ...storage.Option) *storage.PackStorage {
+	t.Helper()
+	packStorage := storage.NewPackStorage(opts...)
+	AddPacks(t, packStorage, DefaultPacks...)
+	return packStorage
+}
+
+// NewStorageWithOrders returns a storage like NewStorage which also recorded the DefaultOrders
+func NewStorageWithOrders(t testing.TB, opts ...storage.Option) *storage.PackStorage {
+	t.Helper()
+	packStorage := NewStorage(t, opts...)
+	AddOrders(t, packStorage, DefaultOrders...)
+	return packStorage
+}
+
+func defaultPacks() []int {
+	amounts, err := storage.PresetAmounts(storage.DefaultPreset)
+	if err != nil {
+		panic(err)
+	}
+	// The fixtures add the smallest pack first, so it gets the first ID
+	slices.Reverse(amounts)
+	return amounts
+}
+
+// AddPacks adds the packs with the amounts to the storage, failing the test if any can't be added
+func AddPacks(t testing.TB, packStorage *storage.PackStorage, amounts ...int) {
+	t.Helper()
+	for _, amount := range amounts {
+		if err := packStorage.AddPack(t.Context(), amount); err != nil {
+			t.Fatalf("add pack %d: %v", amount, err)
+		}
+	}
+}
+
+// AddOrders records an order for every requested items in the storage, failing the test if any can't be calculated
+func AddOrders(t testing.TB, packStorage *storage.PackStorage, requestedItems ...int) {
+	t.Helper()
+	for _, items := range requestedItems {
+		if _, err := packStorage.CalculateOrder(t.Context(), items); err != nil {
+			t.Fatalf("calculate order of %d items: %v", items, err)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package storagetest
+
+import (
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStorage(t *testing.T) {
+	packStorage := NewStorage(t, storage.WithMaxOrders(2))
+
+	var amounts []int
+	for _, pack := range packStorage.GetPacks(t.Context()) {
+		amounts = append(amounts, pack.Amount)
+	}
+	assert.ElementsMatch(t, DefaultPacks, amounts)
+	assert.Empty(t, packStorage.GetOrders(t.Context()))
+
+	// The options apply to the storage
+	AddOrders(t, packStorage, 1, 2, 3)
+	assert.Len(t, packStorage.GetOrders(t.Context()), 2)
+}
+
+func TestNewStorageWithOrders(t *testing.T) {
+	packStorage := NewStorageWithOrders(t)
+
+	var items []int
+	for _, order := range packStorage.GetOrders(t.Context()) {
+		items = append(items, order.RequestedItems)
+	}
+	assert.Equal(t, DefaultOrders, items)
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/corel-frim/item-packer-inc/packing"
+)
+
+// Store is the storage the API depends on. Every method takes the context of the call first,
+// PackStorage is the in-memory implementation.
+type Store interface {
+	// Packs
+	GetPacks(ctx context.Context) []*models.Pack
+	GetPacksWithState(ctx context.Context) ([]*models.Pack, PackSetState)
+	PackSetVersion(ctx context.Context) int
+	PacksAtVersion(ctx context.Context, version int) ([]*models.Pack, error)
+	GetPack(ctx context.Context, amount int) (*models.Pack, error)
+	GetPackByID(ctx context.Context, id int) (*models.Pack, error)
+	GetPackHistory(ctx context.Context) []models.PackChange
+	AddPack(ctx context.Context, amount int) error
+	AddLabeledPack(ctx context.Context, amount int, label string) (*models.Pack, error)
+	AddPacks(ctx context.Context, amounts []int) []models.PackImportResult
+	UpdatePack(ctx context.Context, oldAmount, newAmount int) (*models.Pack, error)
+	UpsertPack(ctx context.Context, oldAmount, newAmount int) (*models.Pack, bool, error)
+	UpdatePackByID(ctx context.Context, id, newAmount int) (*models.Pack, error)
+	DeletePack(ctx context.Context, amount int) error
+	DeleteUnusedPack(ctx context.Context, amount int) error
+	DeletePackByID(ctx context.Context, id int) error
+	SetPackPinned(ctx context.Context, amount int, pinned bool) (*models.Pack, error)
+	SetPackLabel(ctx context.Context, amount int, label string) (*models.Pack, error)
+	ValidatePacks(ctx context.Context, amounts []int) models.PackValidation
+	RecommendPacks(ctx context.Context, count int) (models.PackRecommendation, error)
+	FitPacks(ctx context.Context, requestedItems int) (models.PackFit, error)
+	ApplyPreset(ctx context.Context, name string) ([]*models.Pack, error)
+
+	// Orders
+	GetOrders(ctx context.Context) []models.Order
+	GetOrder(ctx context.Context, id int) (models.Order, error)
+	FindOrders(ctx context.Context, filter OrderFilter) []models.Order
+	GetOrdersByRequestedItems(ctx context.Context, minItems, maxItems int) []models.Order
+	CalculateOrder(ctx context.Context, requestedItems int, opts ...packing.Option) (models.Order, error)
+	CalculateTaggedOrder(ctx context.Context, requestedItems int, tags models.OrderTags, opts ...packing.Option) (models.Order, error)
+	QuoteOrder(ctx context.Context, requestedItems int, opts ...packing.Option) (models.Quote, error)
+	QuoteOrderAtVersion(ctx context.Context, version, requestedItems int, opts ...packing.Option) (models.Quote, error)
+	ReturnItems(ctx context.Context, returnedItems int, opts ...packing.Option) (models.Return, error)
+	MinPackCount(ctx context.Context, requestedItems int) (int, error)
+	OrderCandidates(ctx context.Context, requestedItems, count int, opts ...packing.Option) ([]models.Order, error)
+	CompareStrategies(ctx context.Context, requestedItems int, opts ...packing.Option) (models.StrategyComparison, error)
+	RecomputeOrders(ctx context.Context) (models.RecomputeSummary, error)
+	SubscribeOrders(ctx context.Context) (<-chan models.Order, func())
+	SetOrderRetention(ctx context.Context, retention time.Duration)
+	OrderSummary(ctx context.Context) models.OrderSummary
+
+	// State
+	Snapshot(ctx context.Context) models.Snapshot
+	Restore(ctx context.Context, snapshot models.Snapshot) error
+	ExportConfig(ctx context.Context) models.PackConfig
+	ImportConfig(ctx context.Context, config models.PackConfig) error
+	DefaultStrategy(ctx context.Context) packing.Strategy
+	Stats(ctx context.Context) models.StorageStats
+	StartJanitor(ctx context.Context, interval time.Duration)
+	Close(ctx context.Context) error
+}
+
+var _ Store = (*PackStorage)(nil)
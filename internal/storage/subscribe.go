@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+)
+
+// subscriptionBuffer is the number of orders buffered per subscriber, further orders are dropped
+// until the subscriber catches up so that slow subscribers never block the order calculation
+const subscriptionBuffer = 16
+
+// SubscribeOrders returns a channel receiving every new order and a function to unsubscribe.
+// Orders are dropped for subscribers that don't keep up. The channel is closed on unsubscribe or when the storage is closed.
+// The subscription outlives the context, which only bounds the call itself.
+func (s *PackStorage) SubscribeOrders(_ context.Context) (<-chan models.Order, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch := make(chan models.Order, subscriptionBuffer)
+	if s.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	id := s.nextSubscriberID
+	s.nextSubscriberID++
+	s.subscribers[id] = ch
+
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		// The subscription may be already closed along with the storage
+		if _, ok := s.subscribers[id]; ok {
+			delete(s.subscribers, id)
+			close(ch)
+		}
+	}
+}
+
+// publishOrder sends the order to all subscribers without blocking. It must be called under the write lock.
+func (s *PackStorage) publishOrder(order models.Order) {
+	for _, ch := range s.subscribers {
+		select {
+		case ch <- order:
+		default:
+			// The subscriber is too slow, drop the order
+		}
+	}
+}
+
+// closeSubscriptions closes the channels of all subscribers. It must be called under the write lock.
+func (s *PackStorage) closeSubscriptions() {
+	for id, ch := range s.subscribers {
+		delete(s.subscribers, id)
+		close(ch)
+	}
+}
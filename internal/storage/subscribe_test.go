@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/packing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribeOrders(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+
+	orders, unsubscribe := storage.SubscribeOrders(t.Context())
+	other, unsubscribeOther := storage.SubscribeOrders(t.Context())
+	defer unsubscribeOther()
+
+	created, err := storage.CalculateOrder(t.Context(), 300)
+	assert.NoError(t, err)
+	assert.Equal(t, created, <-orders)
+	assert.Equal(t, created, <-other)
+
+	// Quotes and failed orders aren't published
+	_, _ = storage.QuoteOrder(t.Context(), 300)
+	_, err = storage.CalculateOrder(t.Context(), 300, packing.WithFillerPack(1))
+	assert.Error(t, err)
+	assert.Empty(t, orders)
+
+	unsubscribe()
+	_, ok := <-orders
+	assert.False(t, ok)
+	unsubscribe()
+
+	// The remaining subscriber still receives orders
+	_, _ = storage.CalculateOrder(t.Context(), 100)
+	assert.Len(t, other, 1)
+}
+
+func TestSubscribeOrdersSlowSubscriber(t *testing.T) {
+	storage := NewPackStorage()
+	_ = storage.AddPack(t.Context(), 250)
+
+	orders, unsubscribe := storage.SubscribeOrders(t.Context())
+	defer unsubscribe()
+
+	// Orders beyond the buffer are dropped instead of blocking
+	for i := 1; i <= subscriptionBuffer+5; i++ {
+		_, err := storage.CalculateOrder(t.Context(), i)
+		assert.NoError(t, err)
+	}
+	assert.Len(t, orders, subscriptionBuffer)
+	assert.Equal(t, 1, (<-orders).RequestedItems)
+}
+
+func TestSubscribeOrdersClose(t *testing.T) {
+	storage := NewPackStorage()
+	orders, unsubscribe := storage.SubscribeOrders(t.Context())
+
+	assert.NoError(t, storage.Close(t.Context()))
+	_, ok := <-orders
+	assert.False(t, ok)
+	// Unsubscribing after the storage is closed is fine
+	unsubscribe()
+
+	// Subscriptions after close are closed right away
+	orders, _ = storage.SubscribeOrders(t.Context())
+	_, ok = <-orders
+	assert.False(t, ok)
+}
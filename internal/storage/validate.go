@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+)
+
+// ValidatePacks checks whether the pack amounts could replace the current packs and lists all the problems.
+// It runs the same checks as Restore, including the pack amount rules of AddPack, and doesn't change anything.
+func (s *PackStorage) ValidatePacks(_ context.Context, amounts []int) models.PackValidation {
+	s.mu.RLock()
+	limit := s.packLimit()
+	s.mu.RUnlock()
+
+	violations := s.packViolations(amounts, limit)
+	return models.PackValidation{Valid: len(violations) == 0, Violations: violations}
+}
+
+// packViolations returns the problems of a pack configuration: too many packs, non-positive and duplicate amounts and
+// the amounts breaking the minimum pack amount or the pack multiple
+func (s *PackStorage) packViolations(amounts []int, limit int) []models.PackViolation {
+	violations := make([]models.PackViolation, 0)
+	if len(amounts) > limit {
+		violations = append(violations, models.PackViolation{
+			Index:   -1,
+			Reason:  models.ViolationLimitExceeded,
+			Message: fmt.Sprintf("%d packs exceed the limit of %d", len(amounts), limit),
+		})
+	}
+
+	seen := make(map[int]struct{}, len(amounts))
+	for i, amount := range amounts {
+		if amount <= 0 {
+			violations = append(violations, models.PackViolation{
+				Index:   i,
+				Amount:  amount,
+				Reason:  models.ViolationNotPositive,
+				Message: "pack amounts must be positive",
+			})
+			continue
+		}
+		if _, ok := seen[amount]; ok {
+			violations = append(violations, models.PackViolation{
+				Index:   i,
+				Amount:  amount,
+				Reason:  models.ViolationDuplicate,
+				Message: fmt.Sprintf("duplicate pack amount %d", amount),
+			})
+			continue
+		}
+		seen[amount] = struct{}{}
+		if err := s.checkPackAmount(amount); err != nil {
+			reason := models.ViolationNotMultiple
+			if errors.Is(err, ErrAmountTooSmall) {
+				reason = models.ViolationTooSmall
+			}
+			violations = append(violations, models.PackViolation{Index: i, Amount: amount, Reason: reason, Message: err.Error()})
+		}
+	}
+
+	return violations
+}
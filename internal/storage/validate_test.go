@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePacks(t *testing.T) {
+	storage := NewPackStorage(WithMaxPacks(3))
+	_ = storage.AddPack(t.Context(), 250)
+
+	validation := storage.ValidatePacks(t.Context(), []int{250, 500})
+	assert.True(t, validation.Valid)
+	assert.NotNil(t, validation.Violations)
+	assert.Empty(t, validation.Violations)
+
+	validation = storage.ValidatePacks(t.Context(), []int{250, 0, 250, -5})
+	assert.False(t, validation.Valid)
+	assert.Equal(t, []models.PackViolation{
+		{Index: -1, Reason: models.ViolationLimitExceeded, Message: "4 packs exceed the limit of 3"},
+		{Index: 1, Reason: models.ViolationNotPositive, Message: "pack amounts must be positive"},
+		{Index: 2, Amount: 250, Reason: models.ViolationDuplicate, Message: "duplicate pack amount 250"},
+		{Index: 3, Amount: -5, Reason: models.ViolationNotPositive, Message: "pack amounts must be positive"},
+	}, validation.Violations)
+
+	// Nothing is changed
+	packs := storage.GetPacks(t.Context())
+	assert.Len(t, packs, 1)
+	assert.Equal(t, 250, packs[0].Amount)
+	assert.Equal(t, 1, storage.PackSetVersion(t.Context()))
+}
+
+func TestValidatePacksAmountRules(t *testing.T) {
+	storage := NewPackStorage(WithPackMultiple(250), WithMinPackAmount(500))
+
+	// The dry run rejects the amounts AddPack rejects
+	validation := storage.ValidatePacks(t.Context(), []int{7, 250, 750, 800})
+	assert.False(t, validation.Valid)
+	assert.Equal(t, []models.PackViolation{
+		{Index: 0, Amount: 7, Reason: models.ViolationTooSmall, Message: "pack amount is below the minimum pack amount (7 is below 500)"},
+		{Index: 1, Amount: 250, Reason: models.ViolationTooSmall, Message: "pack amount is below the minimum pack amount (250 is below 500)"},
+		{Index: 3, Amount: 800, Reason: models.ViolationNotMultiple, Message: "pack amount is not a multiple of the pack multiple (800 is not a multiple of 250)"},
+	}, validation.Violations)
+	assert.ErrorIs(t, storage.AddPack(t.Context(), 7), ErrAmountTooSmall)
+	assert.ErrorIs(t, storage.AddPack(t.Context(), 800), ErrInvalidPackMultiple)
+
+	validation = storage.ValidatePacks(t.Context(), []int{500, 750})
+	assert.True(t, validation.Valid)
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash/fnv"
+	"time"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+)
+
+// ErrVersionNotFound means the pack set version doesn't exist or is too old to be kept
+var ErrVersionNotFound = errors.New("pack set version not found")
+
+// PackSetState describes the version of the pack set
+type PackSetState struct {
+	// Version is a monotonically increasing counter bumped on every change of the packs
+	Version int
+	// Hash only changes when the pack set does, so it can be used as an ETag
+	Hash       string
+	ModifiedAt time.Time
+}
+
+// GetPacksWithState returns all available packs together with the state of the pack set
+func (s *PackStorage) GetPacksWithState(_ context.Context) ([]*models.Pack, PackSetState) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.getPacks(), s.packSetState
+}
+
+// PackSetVersion returns the current version of the pack set
+func (s *PackStorage) PackSetVersion(_ context.Context) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.packSetState.Version
+}
+
+// PacksAtVersion returns the packs as they were at the specified version of the pack set.
+// Only the most recent versions up to the pack limit are kept, older ones fail with ErrVersionNotFound.
+func (s *PackStorage) PacksAtVersion(_ context.Context, version int) ([]*models.Pack, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	packs, ok := s.packsAt(version)
+	if !ok {
+		return nil, ErrVersionNotFound
+	}
+
+	result := make([]*models.Pack, len(packs))
+	for i, pack := range packs {
+		result[i] = copyPack(pack)
+	}
+
+	return result, nil
+}
+
+// packSetVersion is a past pack set, its packs are the immutable solver packs of the version
+type packSetVersion struct {
+	version int
+	packs   []*models.Pack
+}
+
+// packsAt returns the solver packs of the specified version, it must be called under the lock
+func (s *PackStorage) packsAt(version int) ([]*models.Pack, bool) {
+	for _, v := range s.versions {
+		if v.version == version {
+			return v.packs, true
+		}
+	}
+	return nil, false
+}
+
+// packSetChanged bumps the pack set version, recomputes its hash and replaces the packs used by the solver.
+// It must be called under the write lock after every change of the packs.
+func (s *PackStorage) packSetChanged() {
+	s.solverPacks = s.getPacks()
+	s.packSetState = PackSetState{
+		Version:    s.packSetState.Version + 1,
+		Hash:       hashPacks(s.packs),
+		ModifiedAt: s.clock.Now(),
+	}
+
+	s.versions = keepNewest(s.versions, s.packLimit()-1)
+	s.versions = append(s.versions, packSetVersion{version: s.packSetState.Version, packs: s.solverPacks})
+}
+
+// hashPacks returns a hex encoded FNV-1a hash of the packs IDs, amounts, pins and labels
+func hashPacks(packs []*models.Pack) string {
+	h := fnv.New64a()
+	buf := make([]byte, 25)
+	for _, p := range packs {
+		binary.BigEndian.PutUint64(buf[:8], uint64(p.ID))
+		binary.BigEndian.PutUint64(buf[8:16], uint64(p.Amount))
+		buf[16] = 0
+		if p.Pinned {
+			buf[16] = 1
+		}
+		// The length keeps the labels of adjacent packs apart
+		binary.BigEndian.PutUint64(buf[17:], uint64(len(p.Label)))
+		_, _ = h.Write(buf)
+		_, _ = h.Write([]byte(p.Label))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
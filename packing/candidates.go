@@ -0,0 +1,62 @@
+package packing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+)
+
+// MaxCandidates is the max number of packings CandidatesContext returns, every candidate is a full computation
+const MaxCandidates = 10
+
+// CandidatesContext returns up to count distinct packings of the requested items using packs sorted in descending
+// order by amount, ranked by the selected strategy and the overpack. The count is capped at MaxCandidates.
+//
+// The first candidate is the packing of SolveContext. Every following one is the packing the strategy picks among
+// those holding more items than the previous candidate, so the totals and the overpack strictly increase. There are
+// fewer candidates when no further packing satisfies the constraints or is simple enough to compute, and only one
+// with StrategyMaxUnder, which never exceeds the requested items.
+func CandidatesContext(ctx context.Context, packs []*models.Pack, requestedItems, count int, opts ...Option) ([]models.Order, error) {
+	o := newOptions(opts)
+	// The overpack limits apply to the requested items rather than the items the next candidate is searched for,
+	// and the candidates aren't traced
+	next := o
+	next.maxOverpack, next.maxOverpackPercent, next.belowSmallestPack, next.trace = nil, nil, false, nil
+
+	count = max(1, min(count, MaxCandidates))
+	candidates := make([]models.Order, 0, count)
+	items := requestedItems
+	for len(candidates) < count {
+		order, err := solve(ctx, packs, items, next)
+		if err != nil {
+			if len(candidates) > 0 && (errors.Is(err, ErrUnsatisfiable) || errors.Is(err, ErrComputationComplexity)) {
+				break
+			}
+			return nil, err
+		}
+		setFulfillment(order, requestedItems, o)
+
+		// The overpack of the following candidates is larger still
+		if overpackExceeded(order, o) {
+			if len(candidates) == 0 {
+				return nil, ErrOverpackExceeded
+			}
+			break
+		}
+		if !overpackBelowSmallestPack(packs, order, o) {
+			if len(candidates) == 0 {
+				return nil, ErrUnsatisfiable
+			}
+			break
+		}
+		candidates = append(candidates, *order)
+		// Larger packings exceed the requested items
+		if o.strategy == StrategyMaxUnder {
+			break
+		}
+		items = order.TotalItems + 1
+	}
+
+	return candidates, nil
+}
@@ -0,0 +1,80 @@
+package packing
+
+import (
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCandidates(t *testing.T) {
+	defaultPacks := packsOf(5000, 2000, 1000, 500, 250)
+	tests := []struct {
+		name     string
+		packs    []*models.Pack
+		items    int
+		count    int
+		opts     []Option
+		totals   []int
+		overpack []int
+	}{
+		{"greedy", defaultPacks, 251, 3, nil, []int{500, 750, 1000}, []int{249, 499, 749}},
+		{"min-overpack", packsOf(9, 6, 4), 11, 3, []Option{WithStrategy(StrategyMinOverpack)}, []int{12, 13, 14}, []int{1, 2, 3}},
+		{"greedy misses the best", packsOf(9, 6, 4), 11, 2, nil, []int{13, 17}, []int{2, 6}},
+		{"overpack limit", defaultPacks, 251, 3, []Option{WithMaxOverpack(500)}, []int{500, 750}, []int{249, 499}},
+		{"below the smallest pack", defaultPacks, 251, 3, []Option{WithOverpackBelowSmallestPack()}, []int{500}, []int{249}},
+		{"no more packings", packsOf(500, 250), 251, 3, []Option{WithStrategy(StrategyMinOverpack), WithMaxQuantityPerSize(1)}, []int{500, 750}, []int{249, 499}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates, err := CandidatesContext(t.Context(), tt.packs, tt.items, tt.count, tt.opts...)
+			assert.NoError(t, err)
+
+			var totals, overpack []int
+			for _, order := range candidates {
+				assert.Equal(t, tt.items, order.RequestedItems)
+				totals = append(totals, order.TotalItems)
+				overpack = append(overpack, order.OverpackedItems)
+			}
+			assert.Equal(t, tt.totals, totals)
+			assert.Equal(t, tt.overpack, overpack)
+		})
+	}
+}
+
+func TestCandidatesFirstIsTheSolution(t *testing.T) {
+	packs := packsOf(53, 31, 23)
+	for _, strategy := range Strategies {
+		order, err := SolveContext(t.Context(), packs, 500, WithStrategy(strategy))
+		assert.NoError(t, err)
+		candidates, err := CandidatesContext(t.Context(), packs, 500, 1, WithStrategy(strategy))
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Order{order}, candidates, strategy)
+	}
+}
+
+func TestCandidatesErrors(t *testing.T) {
+	_, err := CandidatesContext(t.Context(), nil, 251, 3)
+	assert.ErrorIs(t, err, ErrNoPacksAvailable)
+
+	// The first candidate must satisfy the constraints
+	_, err = CandidatesContext(t.Context(), packsOf(500, 250), 251, 3, WithMaxOverpack(100))
+	assert.ErrorIs(t, err, ErrOverpackExceeded)
+	_, err = CandidatesContext(t.Context(), packsOf(500, 250), 1, 3, WithRequiredPack(500), WithOverpackBelowSmallestPack())
+	assert.ErrorIs(t, err, ErrUnsatisfiable)
+
+	// The count is capped
+	candidates, err := CandidatesContext(t.Context(), packsOf(1), 1, 100)
+	assert.NoError(t, err)
+	assert.Len(t, candidates, MaxCandidates)
+}
+
+// packsOf returns the packs with the amounts, which must be in descending order
+func packsOf(amounts ...int) []*models.Pack {
+	packs := make([]*models.Pack, len(amounts))
+	for i, amount := range amounts {
+		packs[i] = &models.Pack{Amount: amount}
+	}
+	return packs
+}
@@ -0,0 +1,42 @@
+package packing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+)
+
+// solveDistinctPacks finds the packing with the least overpack and, among those, prefers the packings using every
+// pack size at most once, then the fewest packs. The packs must be sorted in descending order by amount.
+//
+// Finding a distinct packing is a subset sum of the pack sizes. It only replaces the least overpack packing if it
+// overpacks as little, otherwise the sizes are repeated. A positive maxQuantity caps the packs of every size.
+func solveDistinctPacks(ctx context.Context, packs []*models.Pack, requestedItems int, maxQuantity int) (*models.Order, error) {
+	var best *models.Order
+	var err error
+	if maxQuantity > 0 {
+		best, err = solveMinOverpackCapped(ctx, packs, requestedItems, maxQuantity)
+	} else {
+		best, err = solveMinOverpack(ctx, packs, requestedItems)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(best.Packs) == best.PackCount() {
+		return best, nil
+	}
+
+	distinct, err := solveMinOverpackCapped(ctx, packs, requestedItems, 1)
+	switch {
+	// Too few sizes to cover the items or too many items to solve, the sizes are repeated
+	case errors.Is(err, ErrUnsatisfiable) || errors.Is(err, ErrComputationComplexity):
+		return best, nil
+	case err != nil:
+		return nil, err
+	}
+	if distinct.TotalItems > best.TotalItems {
+		return best, nil
+	}
+	return distinct, nil
+}
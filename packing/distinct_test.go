@@ -0,0 +1,65 @@
+package packing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistinctPacks(t *testing.T) {
+	defaultPacks := []int{250, 500, 1000, 2000, 5000}
+	tests := []struct {
+		name     string
+		packs    []int
+		items    int
+		distinct map[int]int
+	}{
+		{"distinct sizes", defaultPacks, 750, map[int]int{500: 1, 250: 1}},
+		{"overpacked distinct sizes", defaultPacks, 1501, map[int]int{1000: 1, 500: 1, 250: 1}},
+		{"all sizes", defaultPacks, 8750, map[int]int{5000: 1, 2000: 1, 1000: 1, 500: 1, 250: 1}},
+		{"repeats above the sum of all sizes", defaultPacks, 8751, map[int]int{5000: 1, 2000: 2}},
+		{"repeats overpacking less than distinct sizes", defaultPacks, 4000, map[int]int{2000: 2}},
+		{"repeats for large requests", defaultPacks, 12001, map[int]int{5000: 2, 2000: 1, 250: 1}},
+		{"more packs to avoid repeats", []int{1, 4, 5}, 10, map[int]int{5: 1, 4: 1, 1: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			optimal, err := Pack(tt.packs, tt.items, WithStrategy(StrategyMinOverpack))
+			assert.NoError(t, err)
+
+			order, err := Pack(tt.packs, tt.items, WithStrategy(StrategyDistinctPacks))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.distinct, quantitiesOf(order.Packs))
+			assert.Equal(t, optimal.OverpackedItems, order.OverpackedItems)
+		})
+	}
+}
+
+func TestDistinctPacksNeverWorseThanMinOverpack(t *testing.T) {
+	packSets := [][]int{{250, 500, 1000, 2000, 5000}, {4, 6, 9}, {23, 31, 53}, {1, 4, 5}, {7, 11, 13}}
+	for _, packs := range packSets {
+		for items := 1; items <= 300; items++ {
+			optimal, err := Pack(packs, items, WithStrategy(StrategyMinOverpack))
+			assert.NoError(t, err)
+			order, err := Pack(packs, items, WithStrategy(StrategyDistinctPacks))
+			assert.NoError(t, err)
+
+			assert.Equal(t, optimal.OverpackedItems, order.OverpackedItems, "packs %v, items %d", packs, items)
+			// Distinct sizes are only left for a packing with fewer packs
+			if order.PackCount() != len(order.Packs) {
+				assert.LessOrEqual(t, order.PackCount(), optimal.PackCount(), "packs %v, items %d", packs, items)
+			}
+		}
+	}
+}
+
+func TestDistinctPacksWithMaxQuantityPerSize(t *testing.T) {
+	// 3x2000 exceeds the cap, so the distinct sizes overpack as little as the capped packings
+	order, err := Pack([]int{250, 2000, 5000}, 6000, WithStrategy(StrategyDistinctPacks), WithMaxQuantityPerSize(2))
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int{5000: 1, 2000: 1}, quantitiesOf(order.Packs))
+
+	_, err = Pack([]int{250, 500}, 2000, WithStrategy(StrategyDistinctPacks), WithMaxQuantityPerSize(2))
+	assert.ErrorIs(t, err, ErrUnsatisfiable)
+}
@@ -0,0 +1,32 @@
+package packing
+
+import (
+	"context"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+)
+
+// ExactFit reports whether some packing holds exactly the requested items, without building the packing.
+// The packs must be sorted in descending order by amount.
+//
+// Totals that aren't multiples of the greatest common divisor of the amounts never fit, the others are checked
+// with the tables of the min-overpack strategy, so it fails with ErrComputationComplexity for the same pack sets.
+func ExactFit(ctx context.Context, packs []*models.Pack, requestedItems int) (bool, error) {
+	if len(packs) == 0 {
+		return false, ErrNoPacksAvailable
+	}
+
+	divisor := 0
+	for _, pack := range packs {
+		divisor = gcd(pack.Amount, divisor)
+	}
+	if requestedItems%divisor != 0 {
+		return false, nil
+	}
+
+	order, err := solveMinOverpack(ctx, packs, requestedItems)
+	if err != nil {
+		return false, err
+	}
+	return order.TotalItems == requestedItems, nil
+}
@@ -0,0 +1,32 @@
+package packing
+
+import (
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExactFit(t *testing.T) {
+	packs := []*models.Pack{{Amount: 53}, {Amount: 31}, {Amount: 23}}
+
+	// 2x23 + 7x31 + 9429x53
+	fits, err := ExactFit(t.Context(), packs, 500000)
+	assert.NoError(t, err)
+	assert.True(t, fits)
+
+	fits, err = ExactFit(t.Context(), packs, 24)
+	assert.NoError(t, err)
+	assert.False(t, fits)
+
+	// Odd totals never fit even packs, whatever the amounts
+	fits, err = ExactFit(t.Context(), []*models.Pack{{Amount: 1_000_002}, {Amount: 999_984}}, 5_000_001)
+	assert.NoError(t, err)
+	assert.False(t, fits)
+
+	_, err = ExactFit(t.Context(), []*models.Pack{{Amount: 1_000_003}, {Amount: 999_983}}, 5_000_000)
+	assert.ErrorIs(t, err, ErrComputationComplexity)
+
+	_, err = ExactFit(t.Context(), nil, 100)
+	assert.ErrorIs(t, err, ErrNoPacksAvailable)
+}
@@ -0,0 +1,52 @@
+package packing
+
+import (
+	"context"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+)
+
+// solveMaxUnder finds the packing with the most items not exceeding the requested items and, among those,
+// the fewest packs. The packs must be sorted in descending order by amount. A positive maxQuantity caps the packs
+// of every size. The packing may hold fewer items than requested, down to no packs at all when not even the smallest
+// pack fits.
+//
+// Like solveMinOverpack it assigns the largest packs the packing must contain upfront, see fixedLargestPacks:
+// with fewer of them one more largest pack would still fit. The remainder is solved over the totals up to it.
+func solveMaxUnder(ctx context.Context, packs []*models.Pack, requestedItems int, maxQuantity int) (*models.Order, error) {
+	var quantities []int
+	if maxQuantity > 0 {
+		if requestedItems > maxOptimalItems {
+			return nil, ErrComputationComplexity
+		}
+		chunks, maxTotal := cappedChunks(packs, requestedItems, maxQuantity)
+		limit := min(requestedItems, maxTotal)
+		counts, used, err := cappedCounts(ctx, packs, chunks, limit)
+		if err != nil {
+			return nil, err
+		}
+		quantities = cappedQuantities(packs, chunks, used, bestTotalUnder(counts, limit))
+	} else {
+		fixedLargest, err := fixedLargestPacks(packs, requestedItems)
+		if err != nil {
+			return nil, err
+		}
+		target := requestedItems - fixedLargest*packs[0].Amount
+		counts, last, err := minPackCounts(ctx, packs, target)
+		if err != nil {
+			return nil, err
+		}
+		quantities = minPackQuantities(packs, last, fixedLargest, bestTotalUnder(counts, target))
+	}
+
+	return newOrder(packs, requestedItems, quantities), nil
+}
+
+// bestTotalUnder returns the largest total up to the limit some packing holds exactly, 0 if there's none
+func bestTotalUnder(counts []int32, limit int) int {
+	total := limit
+	for total > 0 && counts[total] < 0 {
+		total--
+	}
+	return total
+}
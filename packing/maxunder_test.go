@@ -0,0 +1,99 @@
+package packing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxUnder(t *testing.T) {
+	defaultPacks := []int{250, 500, 1000, 2000, 5000}
+	tests := []struct {
+		name        string
+		packs       []int
+		items       int
+		total       int
+		unfulfilled int
+		quantities  map[int]int
+	}{
+		{"exact", defaultPacks, 750, 750, 0, map[int]int{500: 1, 250: 1}},
+		{"one item short", defaultPacks, 251, 250, 1, map[int]int{250: 1}},
+		{"large request", defaultPacks, 12001, 12000, 1, map[int]int{5000: 2, 2000: 1}},
+		{"fewest packs for the total", defaultPacks, 1999, 1750, 249, map[int]int{1000: 1, 500: 1, 250: 1}},
+		{"greedy would overpack", []int{4, 6, 9}, 11, 10, 1, map[int]int{6: 1, 4: 1}},
+		{"smaller packs fill more", []int{4, 6, 9}, 8, 8, 0, map[int]int{4: 2}},
+		{"huge exact request", []int{23, 31, 53}, 500000, 500000, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := Pack(tt.packs, tt.items, WithStrategy(StrategyMaxUnder))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.items, order.RequestedItems)
+			assert.Equal(t, tt.total, order.TotalItems)
+			assert.Equal(t, tt.unfulfilled, order.UnfulfilledItems)
+			assert.Zero(t, order.OverpackedItems)
+			assert.Zero(t, order.OverpackPercent)
+			if tt.quantities != nil {
+				assert.Equal(t, tt.quantities, quantitiesOf(order.Packs))
+			}
+		})
+	}
+}
+
+func TestMaxUnderMatchesBruteForce(t *testing.T) {
+	packSets := [][]int{{4, 6, 9}, {7, 11, 13}, {23, 31, 53}, {3, 5, 250}}
+	for _, packs := range packSets {
+		for items := packs[0]; items <= 200; items++ {
+			order, err := Pack(packs, items, WithStrategy(StrategyMaxUnder))
+			assert.NoError(t, err)
+
+			// The most items up to the request and the fewest packs holding them, over all the quantities
+			bestTotal, bestCount := 0, 0
+			for a := 0; a*packs[0] <= items; a++ {
+				for b := 0; a*packs[0]+b*packs[1] <= items; b++ {
+					for c := 0; a*packs[0]+b*packs[1]+c*packs[2] <= items; c++ {
+						total, count := a*packs[0]+b*packs[1]+c*packs[2], a+b+c
+						if total > bestTotal || total == bestTotal && count < bestCount {
+							bestTotal, bestCount = total, count
+						}
+					}
+				}
+			}
+			assert.Equal(t, bestTotal, order.TotalItems, "packs %v, items %d", packs, items)
+			assert.Equal(t, bestCount, order.PackCount(), "packs %v, items %d", packs, items)
+		}
+	}
+}
+
+func TestMaxUnderWithConstraints(t *testing.T) {
+	packs := []int{250, 500}
+
+	// 2x500 exceeds the cap, 500+250 is the most under it
+	order, err := Pack(packs, 1000, WithStrategy(StrategyMaxUnder), WithMaxQuantityPerSize(1))
+	assert.NoError(t, err)
+	assert.Equal(t, 750, order.TotalItems)
+	assert.Equal(t, 250, order.UnfulfilledItems)
+
+	order, err = Pack(packs, 800, WithStrategy(StrategyMaxUnder), WithRequiredPack(250))
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int{500: 1, 250: 1}, quantitiesOf(order.Packs))
+	assert.Equal(t, 50, order.UnfulfilledItems)
+
+	// The required pack alone exceeds the request
+	_, err = Pack(packs, 400, WithStrategy(StrategyMaxUnder), WithRequiredPack(500))
+	assert.ErrorIs(t, err, ErrUnsatisfiable)
+
+	// Not even the smallest pack fits
+	_, err = Pack(packs, 249, WithStrategy(StrategyMaxUnder))
+	assert.ErrorIs(t, err, ErrUnsatisfiable)
+	_, err = Pack(packs, 249, WithStrategy(StrategyMaxUnder), WithMaxQuantityPerSize(2))
+	assert.ErrorIs(t, err, ErrUnsatisfiable)
+
+	// The candidates never exceed the request either
+	candidates, err := CandidatesContext(t.Context(), packsOf(500, 250), 999, 3, WithStrategy(StrategyMaxUnder))
+	assert.NoError(t, err)
+	assert.Len(t, candidates, 1)
+	assert.Equal(t, 750, candidates[0].TotalItems)
+	assert.Equal(t, 249, candidates[0].UnfulfilledItems)
+}
@@ -0,0 +1,239 @@
+package packing
+
+import (
+	"context"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+)
+
+// maxOptimalItems bounds the number of item totals explored by the optimal solvers, ~32MB of tables
+const maxOptimalItems = 1 << 22
+
+// cancelCheckInterval is the number of item totals explored between checks of the context, a power of two
+const cancelCheckInterval = 1 << 14
+
+// solveMinOverpack finds the packing with the least overpack and, among those, the fewest packs.
+// The packs must be sorted in descending order by amount.
+//
+// It solves the remainder with dynamic programming over the item totals after assigning as many of the largest
+// packs as any optimal packing must contain, see fixedLargestPacks, so the tables stay small even for huge requests.
+func solveMinOverpack(ctx context.Context, packs []*models.Pack, requestedItems int) (*models.Order, error) {
+	largest := packs[0]
+	smallest := packs[len(packs)-1]
+
+	fixedLargest, err := fixedLargestPacks(packs, requestedItems)
+	if err != nil {
+		return nil, err
+	}
+	target := requestedItems - fixedLargest*largest.Amount
+
+	// Any total from target up to target+smallest-1 can be the least overpack, one more smallest pack always fits
+	limit := target + smallest.Amount - 1
+	counts, last, err := minPackCounts(ctx, packs, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	total := target
+	for counts[total] < 0 {
+		total++
+	}
+
+	return newOrder(packs, requestedItems, minPackQuantities(packs, last, fixedLargest, total)), nil
+}
+
+// fixedLargestPacks returns how many of the largest packs the packings of the requested items found by the min-overpack
+// and the max-under strategies contain at least.
+//
+// Those packings have the fewest packs for their total, so they use less than largest/gcd(amount, largest) packs of
+// every other size: that many packs hold the same items as amount/gcd largest packs, which are fewer. So the other
+// packs hold at most a bounded number of items, and the rest of the items is held by the largest packs.
+func fixedLargestPacks(packs []*models.Pack, requestedItems int) (int, error) {
+	largest := packs[0]
+	otherItems := 0
+	for _, pack := range packs[1:] {
+		quantity := largest.Amount/gcd(pack.Amount, largest.Amount) - 1
+		// Compare before multiplying to not overflow with huge amounts
+		if quantity > (maxOptimalItems-otherItems)/pack.Amount {
+			return 0, ErrComputationComplexity
+		}
+		otherItems += quantity * pack.Amount
+	}
+	if requestedItems <= otherItems {
+		return 0, nil
+	}
+	return (requestedItems - otherItems) / largest.Amount, nil
+}
+
+// minPackCounts computes the tables of the fewest packs over the totals up to the limit.
+// counts[t] is the least number of packs holding exactly t items, -1 if no packing does.
+// last[t] is the index of the pack added last to reach t.
+func minPackCounts(ctx context.Context, packs []*models.Pack, limit int) ([]int32, []int32, error) {
+	if limit > maxOptimalItems {
+		return nil, nil, ErrComputationComplexity
+	}
+
+	counts := make([]int32, limit+1)
+	last := make([]int32, limit+1)
+	for t := 1; t <= limit; t++ {
+		if t&(cancelCheckInterval-1) == 0 {
+			if err := checkContext(ctx); err != nil {
+				return nil, nil, err
+			}
+		}
+		counts[t] = -1
+		for i, pack := range packs {
+			if pack.Amount > t || counts[t-pack.Amount] < 0 {
+				continue
+			}
+			if count := counts[t-pack.Amount] + 1; counts[t] < 0 || count < counts[t] {
+				counts[t] = count
+				last[t] = int32(i)
+			}
+		}
+	}
+	return counts, last, nil
+}
+
+// minPackQuantities walks the tables of minPackCounts back from the total to the quantities of the packs,
+// adding the fixed number of the largest packs
+func minPackQuantities(packs []*models.Pack, last []int32, fixedLargest, total int) []int {
+	quantities := make([]int, len(packs))
+	quantities[0] = fixedLargest
+	for t := total; t > 0; t -= packs[last[t]].Amount {
+		quantities[last[t]]++
+	}
+	return quantities
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// maxOptimalCells bounds the size of the tables of the capped solver, one byte per cell
+const maxOptimalCells = 1 << 24
+
+// solveMinOverpackCapped finds the packing with the least overpack and, among those, the fewest packs
+// using at most maxQuantity packs of every size. The packs must be sorted in descending order by amount.
+//
+// The largest packs can't be fixed upfront as in solveMinOverpack because of the cap, so it solves a bounded knapsack
+// over all the totals up to the request.
+func solveMinOverpackCapped(ctx context.Context, packs []*models.Pack, requestedItems int, maxQuantity int) (*models.Order, error) {
+	// Removing any pack of a least overpack packing makes it too small, so the overpack is less than the largest pack
+	limit := requestedItems + packs[0].Amount - 1
+	if limit > maxOptimalItems {
+		return nil, ErrComputationComplexity
+	}
+
+	chunks, maxTotal := cappedChunks(packs, limit, maxQuantity)
+	if maxTotal < requestedItems {
+		return nil, ErrUnsatisfiable
+	}
+	limit = min(limit, maxTotal)
+	counts, used, err := cappedCounts(ctx, packs, chunks, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	total := requestedItems
+	for total <= limit && counts[total] < 0 {
+		total++
+	}
+	if total > limit {
+		return nil, ErrUnsatisfiable
+	}
+
+	return newOrder(packs, requestedItems, cappedQuantities(packs, chunks, used, total)), nil
+}
+
+// chunk is a number of packs of a single size the capped solvers either use or not
+type chunk struct {
+	pack     int
+	quantity int
+}
+
+// cappedChunks splits the quantities of the packs up to maxQuantity and up to the limit of items into chunks
+// of 1, 2, 4, ... packs, so that any quantity up to the cap is a sum of distinct chunks.
+// It also returns the most items the chunks hold together.
+func cappedChunks(packs []*models.Pack, limit, maxQuantity int) ([]chunk, int) {
+	var chunks []chunk
+	maxTotal := 0
+	for i, pack := range packs {
+		quantity := min(maxQuantity, limit/pack.Amount)
+		maxTotal += quantity * pack.Amount
+		for size := 1; quantity > 0; size *= 2 {
+			size = min(size, quantity)
+			chunks = append(chunks, chunk{pack: i, quantity: size})
+			quantity -= size
+		}
+	}
+	return chunks, maxTotal
+}
+
+// cappedCounts solves the bounded knapsack of the chunks over the totals up to the limit.
+// counts[t] is the least number of packs holding exactly t items, -1 if no packing does.
+// used[c][t] tells whether chunk c is part of the best packing for t among the first c+1 chunks.
+func cappedCounts(ctx context.Context, packs []*models.Pack, chunks []chunk, limit int) ([]int32, [][]bool, error) {
+	if len(chunks)*(limit+1) > maxOptimalCells {
+		return nil, nil, ErrComputationComplexity
+	}
+
+	counts := make([]int32, limit+1)
+	for t := 1; t <= limit; t++ {
+		counts[t] = -1
+	}
+	used := make([][]bool, len(chunks))
+	for c, ch := range chunks {
+		used[c] = make([]bool, limit+1)
+		items := ch.quantity * packs[ch.pack].Amount
+		for t := limit; t >= items; t-- {
+			if t&(cancelCheckInterval-1) == 0 {
+				if err := checkContext(ctx); err != nil {
+					return nil, nil, err
+				}
+			}
+			if counts[t-items] < 0 {
+				continue
+			}
+			if count := counts[t-items] + int32(ch.quantity); counts[t] < 0 || count < counts[t] {
+				counts[t] = count
+				used[c][t] = true
+			}
+		}
+	}
+	return counts, used, nil
+}
+
+// cappedQuantities walks the tables of cappedCounts back from the total to the quantities of the packs
+func cappedQuantities(packs []*models.Pack, chunks []chunk, used [][]bool, total int) []int {
+	quantities := make([]int, len(packs))
+	for c, t := len(chunks)-1, total; c >= 0; c-- {
+		if used[c][t] {
+			quantities[chunks[c].pack] += chunks[c].quantity
+			t -= chunks[c].quantity * packs[chunks[c].pack].Amount
+		}
+	}
+	return quantities
+}
+
+// newOrder builds an order from the quantities of the packs with the same indices
+func newOrder(packs []*models.Pack, requestedItems int, quantities []int) *models.Order {
+	order := &models.Order{
+		RequestedItems: requestedItems,
+		Packs:          make([]models.OrderPack, 0, len(packs)),
+	}
+	for i, quantity := range quantities {
+		if quantity > 0 {
+			order.Packs = append(order.Packs, models.OrderPack{
+				Quantity: quantity,
+				Pack:     packs[i],
+				Subtotal: quantity * packs[i].Amount,
+			})
+			order.TotalItems += quantity * packs[i].Amount
+		}
+	}
+	return order
+}
@@ -0,0 +1,165 @@
+package packing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGreedyUnderfill(t *testing.T) {
+	// Greedy takes a 9 and tops up the remaining 2 items with a 4
+	order, err := Pack([]int{4, 6, 9}, 11)
+	assert.NoError(t, err)
+	assert.Equal(t, 13, order.TotalItems)
+	assert.Equal(t, 2, order.OverpackedItems)
+
+	// 6+6 overpacks a single item
+	order, err = Pack([]int{4, 6, 9}, 11, WithStrategy(StrategyMinOverpack))
+	assert.NoError(t, err)
+	assert.Equal(t, 12, order.TotalItems)
+	assert.Equal(t, 1, order.OverpackedItems)
+	assert.Len(t, order.Packs, 1)
+	assert.Equal(t, 6, order.Packs[0].Pack.Amount)
+	assert.Equal(t, 2, order.Packs[0].Quantity)
+	assert.Equal(t, 12, order.Packs[0].Subtotal)
+}
+
+func TestMinOverpack(t *testing.T) {
+	defaultPacks := []int{250, 500, 1000, 2000, 5000}
+	tests := []struct {
+		name     string
+		packs    []int
+		items    int
+		total    int
+		packsQty map[int]int
+	}{
+		{"zero items", defaultPacks, 0, 0, map[int]int{}},
+		{"single item", defaultPacks, 1, 250, map[int]int{250: 1}},
+		{"exact", defaultPacks, 250, 250, map[int]int{250: 1}},
+		{"fewest packs", defaultPacks, 251, 500, map[int]int{500: 1}},
+		{"fewest packs for the same overpack", defaultPacks, 501, 750, map[int]int{500: 1, 250: 1}},
+		{"large", defaultPacks, 12001, 12250, map[int]int{5000: 2, 2000: 1, 250: 1}},
+		{"huge", defaultPacks, 1_000_000_001, 1_000_000_250, map[int]int{5000: 200_000, 250: 1}},
+		{"non-divisible", []int{4, 6, 9}, 14, 14, map[int]int{6: 1, 4: 2}},
+		{"single pack", []int{7}, 20, 21, map[int]int{7: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := Pack(tt.packs, tt.items, WithStrategy(StrategyMinOverpack))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.total, order.TotalItems)
+			assert.Equal(t, tt.total-tt.items, order.OverpackedItems)
+
+			quantities := make(map[int]int)
+			for i, op := range order.Packs {
+				quantities[op.Pack.Amount] = op.Quantity
+				assert.Equal(t, op.Quantity*op.Pack.Amount, op.Subtotal)
+				if i > 0 {
+					assert.Greater(t, order.Packs[i-1].Pack.Amount, op.Pack.Amount)
+				}
+			}
+			assert.Equal(t, tt.packsQty, quantities)
+		})
+	}
+}
+
+func TestMinOverpackNeverWorseThanGreedy(t *testing.T) {
+	packSets := [][]int{{250, 500, 1000, 2000, 5000}, {4, 6, 9}, {23, 31, 53}, {3, 5}, {7, 11, 13}}
+	for _, packs := range packSets {
+		for items := 1; items <= 300; items++ {
+			greedy, err := Pack(packs, items)
+			assert.NoError(t, err)
+			optimal, err := Pack(packs, items, WithStrategy(StrategyMinOverpack))
+			assert.NoError(t, err)
+
+			assert.LessOrEqual(t, optimal.OverpackedItems, greedy.OverpackedItems, "packs %v, items %d", packs, items)
+		}
+	}
+}
+
+func TestMinOverpackTooComplex(t *testing.T) {
+	// Large coprime packs would need huge tables
+	_, err := Pack([]int{1_000_003, 999_983}, 5_000_000, WithStrategy(StrategyMinOverpack))
+	assert.ErrorIs(t, err, ErrComputationComplexity)
+}
+
+func TestMinOverpackCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := SolveContext(ctx, []*models.Pack{{Amount: 500}, {Amount: 250}}, 1000)
+	assert.ErrorIs(t, err, ErrComputationTimeout)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// The solvers check the context while filling their tables
+	packs := []*models.Pack{{Amount: 1 << 15}, {Amount: 3}}
+	_, err = solveMinOverpack(ctx, packs, 100_000)
+	assert.ErrorIs(t, err, ErrComputationTimeout)
+	_, err = solveMinOverpackCapped(ctx, packs, 50_000, 1000)
+	assert.ErrorIs(t, err, ErrComputationTimeout)
+
+	// Without cancellation the same computations succeed
+	_, err = solveMinOverpack(t.Context(), packs, 100_000)
+	assert.NoError(t, err)
+}
+
+func TestUnknownStrategy(t *testing.T) {
+	_, err := Pack([]int{250}, 100, WithStrategy("fastest"))
+	assert.ErrorIs(t, err, ErrUnknownStrategy)
+
+	strategy, err := ParseStrategy("min-overpack")
+	assert.NoError(t, err)
+	assert.Equal(t, StrategyMinOverpack, strategy)
+
+	_, err = ParseStrategy("fastest")
+	assert.ErrorIs(t, err, ErrUnknownStrategy)
+}
+
+func TestMinOverpackWellKnownCase(t *testing.T) {
+	packs := []int{23, 31, 53}
+
+	// 2x23 + 7x31 + 9429x53 = 500000
+	order, err := Pack(packs, 500_000, WithStrategy(StrategyMinOverpack))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, order.OverpackedItems)
+	assert.Equal(t, 500_000, order.TotalItems)
+
+	quantities := make(map[int]int)
+	count := 0
+	for _, op := range order.Packs {
+		quantities[op.Pack.Amount] = op.Quantity
+		count += op.Quantity
+	}
+	assert.Equal(t, map[int]int{53: 9429, 31: 7, 23: 2}, quantities)
+	assert.Equal(t, 9438, count)
+
+	// Greedy doesn't find an exact packing
+	order, err = Pack(packs, 500_000)
+	assert.NoError(t, err)
+	assert.Greater(t, order.OverpackedItems, 0)
+}
+
+func TestMinOverpackWithMaxQuantityPerSize(t *testing.T) {
+	// 6+6 isn't allowed, 4+9 is the next best
+	order, err := Pack([]int{4, 6, 9}, 11, WithStrategy(StrategyMinOverpack), WithMaxQuantityPerSize(1))
+	assert.NoError(t, err)
+	assert.Equal(t, 13, order.TotalItems)
+	assert.Len(t, order.Packs, 2)
+	assert.Equal(t, 9, order.Packs[0].Pack.Amount)
+	assert.Equal(t, 4, order.Packs[1].Pack.Amount)
+
+	// The cap applies to the largest packs as well
+	order, err = Pack([]int{250, 500, 1000}, 3000, WithStrategy(StrategyMinOverpack), WithMaxQuantityPerSize(2))
+	assert.NoError(t, err)
+	assert.Equal(t, 3000, order.TotalItems)
+	for _, op := range order.Packs {
+		assert.LessOrEqual(t, op.Quantity, 2)
+	}
+
+	// All packs together hold 19 items
+	_, err = Pack([]int{4, 6, 9}, 20, WithStrategy(StrategyMinOverpack), WithMaxQuantityPerSize(1))
+	assert.ErrorIs(t, err, ErrUnsatisfiable)
+}
@@ -0,0 +1,214 @@
+package packing
+
+import (
+	"errors"
+	"strings"
+)
+
+// Strategy selects the algorithm used to pack the items
+type Strategy string
+
+const (
+	// StrategyGreedy fills the items with the largest packs first, then tops up with the smallest pack and merges
+	// smaller packs into larger ones. It's fast but can overpack more than necessary for some pack sets.
+	StrategyGreedy Strategy = "greedy"
+	// StrategyMinOverpack finds the packing with the least overpacked items and, among those, the fewest packs
+	StrategyMinOverpack Strategy = "min-overpack"
+	// StrategyFewestSizes finds the packing with the least overpacked items and, among those, the fewest distinct
+	// pack sizes and then the fewest packs. It may use more packs than StrategyMinOverpack to use fewer sizes,
+	// e.g. 3x250 instead of 500+250, and it's the slowest strategy as it solves subsets of the sizes.
+	StrategyFewestSizes Strategy = "fewest-sizes"
+	// StrategyDistinctPacks finds the packing with the least overpacked items and, among those, prefers using every
+	// pack size at most once, e.g. 500+250 for 750 items. Sizes are only repeated when no distinct packing overpacks
+	// as little, e.g. 2x2000 for 4000 items instead of 5000.
+	StrategyDistinctPacks Strategy = "distinct-packs"
+	// StrategyMaxUnder never overpacks: it finds the packing with the most items not exceeding the requested items
+	// and, among those, the fewest packs. The order may hold fewer items than requested, it reports the shortfall
+	// in UnfulfilledItems. It fails with ErrUnsatisfiable if not even the smallest pack fits.
+	StrategyMaxUnder Strategy = "max-under"
+)
+
+// Strategies lists all supported strategies
+var Strategies = []Strategy{StrategyGreedy, StrategyMinOverpack, StrategyFewestSizes, StrategyDistinctPacks, StrategyMaxUnder}
+
+var ErrUnknownStrategy = errors.New("unknown strategy, must be one of " + strategyNames())
+
+// ParseStrategy returns the strategy with the specified name
+func ParseStrategy(name string) (Strategy, error) {
+	for _, strategy := range Strategies {
+		if string(strategy) == name {
+			return strategy, nil
+		}
+	}
+	return "", ErrUnknownStrategy
+}
+
+func strategyNames() string {
+	names := make([]string, len(Strategies))
+	for i, strategy := range Strategies {
+		names[i] = string(strategy)
+	}
+	return strings.Join(names, ", ")
+}
+
+// options holds the optional parameters of a packing calculation
+type options struct {
+	// strategy is the packing algorithm, empty means StrategyGreedy
+	strategy Strategy
+	// fillerPack is the pack amount used to fill the remaining items, 0 means the smallest pack
+	fillerPack int
+	// maxOverpack is the max number of overpacked items, nil means no limit
+	maxOverpack *int
+	// maxOverpackPercent is the max overpack relative to the requested items, nil means no limit
+	maxOverpackPercent *float64
+	// includeUnused adds zero-quantity entries for the packs that aren't used
+	includeUnused bool
+	// maxQuantity is the max number of packs of a single size, 0 means no limit
+	maxQuantity int
+	// requiredPack is the pack amount every packing must include at least once, 0 means none
+	requiredPack int
+	// belowSmallestPack rejects packings overpacking by the smallest pack amount or more
+	belowSmallestPack bool
+	// rounding rounds the overpack percent, the zero value is DefaultRounding
+	rounding Rounding
+	// trace records the computation steps, nil means no tracing
+	trace *Trace
+}
+
+// Option configures a packing calculation
+type Option func(*options)
+
+// WithStrategy selects the packing algorithm, StrategyGreedy is used by default
+func WithStrategy(strategy Strategy) Option {
+	return func(o *options) {
+		o.strategy = strategy
+	}
+}
+
+// WithFillerPack forces the remaining items to be filled with the pack of the specified amount
+// instead of the smallest one. The pack must exist. Only StrategyGreedy uses the filler.
+func WithFillerPack(amount int) Option {
+	return func(o *options) {
+		o.fillerPack = amount
+	}
+}
+
+// WithMaxOverpack rejects packings overpacking more than the specified number of items with ErrOverpackExceeded
+func WithMaxOverpack(items int) Option {
+	return func(o *options) {
+		o.maxOverpack = &items
+	}
+}
+
+// WithMaxOverpackPercent rejects packings overpacking more than the specified percentage of the requested items
+// with ErrOverpackExceeded
+func WithMaxOverpackPercent(percent float64) Option {
+	return func(o *options) {
+		o.maxOverpackPercent = &percent
+	}
+}
+
+// WithMaxQuantityPerSize caps the number of packs of every single size.
+// Packings that can't satisfy the cap fail with ErrUnsatisfiable. Zero or negative means no limit.
+func WithMaxQuantityPerSize(quantity int) Option {
+	return func(o *options) {
+		o.maxQuantity = max(quantity, 0)
+	}
+}
+
+// WithRequiredPack makes the packing include at least one pack of the specified amount, the rest of the items
+// is packed with the selected strategy. The pack must exist, otherwise the packing fails with ErrRequiredPackNotFound.
+func WithRequiredPack(amount int) Option {
+	return func(o *options) {
+		o.requiredPack = amount
+	}
+}
+
+// WithOverpackBelowSmallestPack guarantees that the packing overpacks by less than the smallest pack amount,
+// so no more than a single smallest pack is ever wasted. Packings that can't meet it fail with ErrUnsatisfiable.
+// Without constraints every strategy meets it, but e.g. WithFillerPack, WithMaxQuantityPerSize or WithRequiredPack
+// can push the overpack beyond it. The selected strategy isn't changed, so StrategyGreedy may fail where
+// StrategyMinOverpack, which looks for the least overpack, doesn't.
+func WithOverpackBelowSmallestPack() Option {
+	return func(o *options) {
+		o.belowSmallestPack = true
+	}
+}
+
+// WithRounding rounds the overpack percent of the packing with the rounding instead of DefaultRounding
+func WithRounding(rounding Rounding) Option {
+	return func(o *options) {
+		o.rounding = rounding
+	}
+}
+
+// WithTrace records the steps of the computation into the trace
+func WithTrace(trace *Trace) Option {
+	return func(o *options) {
+		o.trace = trace
+	}
+}
+
+// WithUnusedPacks includes the packs that weren't used in the order with zero quantity
+func WithUnusedPacks() Option {
+	return func(o *options) {
+		o.includeUnused = true
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Key is a comparable representation of a set of options.
+// Equal keys produce equal packings for the same packs and requested items, so it can be used as a cache key.
+type Key struct {
+	strategy              Strategy
+	fillerPack            int
+	maxOverpack           int
+	hasMaxOverpack        bool
+	maxOverpackPercent    float64
+	hasMaxOverpackPercent bool
+	includeUnused         bool
+	maxQuantity           int
+	requiredPack          int
+	belowSmallestPack     bool
+	rounding              Rounding
+	traced                bool
+}
+
+// Cacheable tells whether the result of a computation with the key can be reused.
+// Traced computations can't, the trace is only recorded when the computation runs.
+func (k Key) Cacheable() bool {
+	return !k.traced
+}
+
+// OptionsKey returns the key of the specified options
+func OptionsKey(opts ...Option) Key {
+	o := newOptions(opts)
+	key := Key{
+		strategy:          o.strategy,
+		fillerPack:        o.fillerPack,
+		includeUnused:     o.includeUnused,
+		maxQuantity:       o.maxQuantity,
+		requiredPack:      o.requiredPack,
+		belowSmallestPack: o.belowSmallestPack,
+		rounding:          o.rounding,
+		traced:            o.trace != nil,
+	}
+	// The zero rounding and DefaultRounding round the same
+	if key.rounding == (Rounding{}) {
+		key.rounding = DefaultRounding
+	}
+	if o.maxOverpack != nil {
+		key.maxOverpack, key.hasMaxOverpack = *o.maxOverpack, true
+	}
+	if o.maxOverpackPercent != nil {
+		key.maxOverpackPercent, key.hasMaxOverpackPercent = *o.maxOverpackPercent, true
+	}
+	return key
+}
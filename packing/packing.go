@@ -0,0 +1,591 @@
+// Package packing implements the algorithm for packing items into packs of standard sizes.
+// It doesn't depend on any storage and can be used on its own.
+package packing
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+)
+
+var (
+	ErrNoPacksAvailable = errors.New("no packs available")
+	ErrInvalidAmount    = errors.New("pack amount must be positive")
+	ErrFillerNotFound   = errors.New("filler pack not found")
+	// ErrRequiredPackNotFound is returned when the pack required with WithRequiredPack doesn't exist
+	ErrRequiredPackNotFound = errors.New("required pack not found")
+	ErrOverpackExceeded     = errors.New("overpack exceeds the allowed maximum")
+	// ErrComputationComplexity is returned when the packing would take an unreasonable amount of time or memory
+	ErrComputationComplexity = errors.New("order computation is too complex")
+	// ErrUnsatisfiable is returned when the items can't be packed within the constraints although there are packs
+	ErrUnsatisfiable = errors.New("items can't be packed within the constraints")
+	// ErrComputationTimeout is returned when the context is done before the packing is computed.
+	// The error also wraps the error of the context.
+	ErrComputationTimeout = errors.New("order computation timed out")
+	// ErrItemsOutOfRange is returned when the requested items plus the largest pack don't fit into an int, so the
+	// totals of the order could overflow
+	ErrItemsOutOfRange = errors.New("requested items are out of range")
+)
+
+// Pack calculates the optimal packing for the requested items using the given pack amounts.
+// Duplicate amounts are ignored.
+func Pack(packAmounts []int, requestedItems int, opts ...Option) (models.Order, error) {
+	packs := make([]*models.Pack, 0, len(packAmounts))
+	for _, amount := range packAmounts {
+		if amount <= 0 {
+			return models.Order{}, ErrInvalidAmount
+		}
+		if findPack(packs, amount) == nil {
+			packs = append(packs, &models.Pack{Amount: amount})
+		}
+	}
+	sort.Slice(packs, func(i, j int) bool {
+		return packs[i].Amount > packs[j].Amount
+	})
+
+	return Solve(packs, requestedItems, opts...)
+}
+
+// Solve calculates the optimal packing for the requested items using packs sorted in descending order by amount.
+// The packs are referenced by the returned order, so callers shouldn't pass packs they modify later.
+func Solve(packs []*models.Pack, requestedItems int, opts ...Option) (models.Order, error) {
+	return SolveContext(context.Background(), packs, requestedItems, opts...)
+}
+
+// SolveContext is Solve aborting with ErrComputationTimeout once the context is done
+func SolveContext(ctx context.Context, packs []*models.Pack, requestedItems int, opts ...Option) (models.Order, error) {
+	order, err := solve(ctx, packs, requestedItems, newOptions(opts))
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	return *order, nil
+}
+
+func solve(ctx context.Context, packs []*models.Pack, requestedItems int, o options) (*models.Order, error) {
+	if len(packs) == 0 {
+		return nil, ErrNoPacksAvailable
+	}
+	// An order overpacks by less than the largest pack, so its totals fit below this bound
+	if requestedItems > math.MaxInt-packs[0].Amount {
+		return nil, ErrItemsOutOfRange
+	}
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	var filler *models.Pack
+	if o.fillerPack != 0 {
+		filler = findPack(packs, o.fillerPack)
+		if filler == nil {
+			return nil, ErrFillerNotFound
+		}
+	}
+
+	var required *models.Pack
+	if o.requiredPack != 0 {
+		required = findPack(packs, o.requiredPack)
+		if required == nil {
+			return nil, ErrRequiredPackNotFound
+		}
+	}
+
+	// The required pack is reserved up front, the strategy packs the rest of the items
+	solverItems := requestedItems
+	if required != nil {
+		// Packings of the max-under strategy never exceed the requested items
+		if o.strategy == StrategyMaxUnder && required.Amount > requestedItems {
+			return nil, ErrUnsatisfiable
+		}
+		solverItems = max(requestedItems-required.Amount, 0)
+	}
+
+	var order *models.Order
+	var err error
+	switch {
+	case o.strategy == "" || o.strategy == StrategyGreedy:
+		order, err = solveGreedy(packs, solverItems, filler, o.maxQuantity, o.trace)
+	case o.strategy == StrategyMinOverpack && o.maxQuantity > 0:
+		order, err = solveMinOverpackCapped(ctx, packs, solverItems, o.maxQuantity)
+		traceOptimal(order, o.trace)
+	case o.strategy == StrategyMinOverpack:
+		order, err = solveMinOverpack(ctx, packs, solverItems)
+		traceOptimal(order, o.trace)
+	case o.strategy == StrategyFewestSizes:
+		order, err = solveFewestSizes(ctx, packs, solverItems, o.maxQuantity)
+		traceOptimal(order, o.trace)
+	case o.strategy == StrategyDistinctPacks:
+		order, err = solveDistinctPacks(ctx, packs, solverItems, o.maxQuantity)
+		traceOptimal(order, o.trace)
+	case o.strategy == StrategyMaxUnder:
+		order, err = solveMaxUnder(ctx, packs, solverItems, o.maxQuantity)
+		traceOptimal(order, o.trace)
+	default:
+		err = ErrUnknownStrategy
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if required != nil {
+		order = addFillerPacks(required.Amount, required, order)
+		order.RequestedItems = requestedItems
+		o.trace.add(models.TraceStep{Action: models.TraceRequired, Amount: required.Amount, Quantity: 1})
+
+		// The reserved pack counts towards the cap of its size
+		if o.maxQuantity > 0 && quantityOfSize(order, required.Amount) > o.maxQuantity {
+			return nil, ErrUnsatisfiable
+		}
+	}
+
+	// Not even the smallest pack fits under the requested items
+	if o.strategy == StrategyMaxUnder && order.TotalItems == 0 {
+		return nil, ErrUnsatisfiable
+	}
+	setFulfillment(order, requestedItems, o)
+
+	// Sort the result so that equivalent orders always serialize the same way
+	sortOrderPacks(order)
+
+	if overpackExceeded(order, o) {
+		return nil, ErrOverpackExceeded
+	}
+	if !overpackBelowSmallestPack(packs, order, o) {
+		return nil, ErrUnsatisfiable
+	}
+
+	if o.includeUnused {
+		addUnusedPacks(packs, order)
+	}
+
+	return order, nil
+}
+
+// solveGreedy packs the items with the largest packs first and merges the packs afterwards.
+// It doesn't always find the least overpack, e.g. for packs {4, 6, 9} and 11 items it packs 9+4 instead of 6+6,
+// and for packs {23, 31, 53} and 500000 items it overpacks although 2x23 + 7x31 + 9429x53 is exact.
+// Use StrategyMinOverpack for such pack sets.
+// A positive maxQuantity caps the number of packs of every size. The steps are recorded into a non-nil trace.
+func solveGreedy(packs []*models.Pack, requestedItems int, filler *models.Pack, maxQuantity int, trace *Trace) (*models.Order, error) {
+	order := &models.Order{
+		RequestedItems: requestedItems,
+		TotalItems:     0,
+		Packs:          make([]models.OrderPack, 0, len(packs)),
+	}
+
+	// First try to use the largest packs possible
+	remainingItems, order := useFullPacks(packs, order, maxQuantity)
+	var filled map[int]int
+	if trace != nil {
+		filled = make(map[int]int, len(order.Packs))
+		for _, op := range order.Packs {
+			filled[op.Pack.Amount] = op.Quantity
+			trace.add(models.TraceStep{Action: models.TraceFill, Amount: op.Pack.Amount, Quantity: op.Quantity})
+		}
+	}
+
+	// If we still have remaining items, use the filler pack if forced or the smallest pack otherwise
+	if filler != nil && maxQuantity > 0 {
+		// Use as many filler packs as the cap allows, the rest falls back to the other packs
+		quantity := min((remainingItems+filler.Amount-1)/filler.Amount, max(maxQuantity-quantityOfSize(order, filler.Amount), 0))
+		order = addFillerPacks(min(quantity*filler.Amount, remainingItems), filler, order)
+		remainingItems -= quantity * filler.Amount
+	} else if filler != nil {
+		order = addFillerPacks(remainingItems, filler, order)
+		remainingItems = 0
+	}
+
+	var err error
+	order, err = addPackForRemainingItems(remainingItems, packs, order, maxQuantity)
+	if err != nil {
+		return nil, err
+	}
+
+	if trace != nil {
+		// The top-up added the packs missing from the fill
+		for _, op := range order.Packs {
+			if added := op.Quantity - filled[op.Pack.Amount]; added > 0 {
+				trace.add(models.TraceStep{Action: models.TraceTopUp, Amount: op.Pack.Amount, Quantity: added})
+			}
+		}
+	}
+	if maxQuantity > 0 {
+		// A capped top-up may add a larger pack than the fill used, which can make the packs of the fill redundant
+		order = dropRedundantPacks(packs, order, trace)
+	}
+
+	if err := mergePacks(packs, order, maxQuantity, trace); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// traceOptimal records the packs of an order computed at once
+func traceOptimal(order *models.Order, trace *Trace) {
+	if order == nil {
+		return
+	}
+	for _, op := range order.Packs {
+		trace.add(models.TraceStep{Action: models.TraceOptimal, Amount: op.Pack.Amount, Quantity: op.Quantity})
+	}
+}
+
+// addUnusedPacks adds zero-quantity entries for the packs the order doesn't use.
+// Both the packs and the order packs must be sorted in descending order by amount, the result stays sorted.
+func addUnusedPacks(packs []*models.Pack, order *models.Order) {
+	result := make([]models.OrderPack, 0, len(packs))
+	used := 0
+	for _, pack := range packs {
+		if used < len(order.Packs) && order.Packs[used].Pack.Amount == pack.Amount {
+			result = append(result, order.Packs[used])
+			used++
+			continue
+		}
+		result = append(result, models.OrderPack{Pack: pack})
+	}
+	order.Packs = result
+}
+
+// checkContext returns ErrComputationTimeout wrapping the error of the context once it's done
+func checkContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrComputationTimeout, err)
+	}
+	return nil
+}
+
+// setFulfillment sets the overpacked items with their percent of the requested items for the packings holding more
+// items than requested, and the unfulfilled items for the packings of the max-under strategy holding fewer
+func setFulfillment(order *models.Order, requestedItems int, o options) {
+	order.RequestedItems = requestedItems
+	order.OverpackedItems = max(order.TotalItems-requestedItems, 0)
+	order.UnfulfilledItems = max(requestedItems-order.TotalItems, 0)
+	order.OverpackPercent = o.rounding.Percent(order.OverpackedItems, requestedItems)
+}
+
+// OverpackPercent returns the overpacked items relative to the requested items in percent,
+// rounded with DefaultRounding. It's 0 if no items are requested.
+func OverpackPercent(overpackedItems, requestedItems int) float64 {
+	return DefaultRounding.Percent(overpackedItems, requestedItems)
+}
+
+// overpackExceeded checks the order against the max overpack options
+func overpackExceeded(order *models.Order, o options) bool {
+	if o.maxOverpack != nil && order.OverpackedItems > *o.maxOverpack {
+		return true
+	}
+	if o.maxOverpackPercent != nil && order.RequestedItems > 0 {
+		percent := float64(order.OverpackedItems) / float64(order.RequestedItems) * 100
+		if percent > *o.maxOverpackPercent {
+			return true
+		}
+	}
+	return false
+}
+
+// overpackBelowSmallestPack checks the order against WithOverpackBelowSmallestPack.
+// The packs must be sorted in descending order by amount.
+func overpackBelowSmallestPack(packs []*models.Pack, order *models.Order, o options) bool {
+	return !o.belowSmallestPack || order.OverpackedItems < packs[len(packs)-1].Amount
+}
+
+// sortOrderPacks sorts the order packs in descending order by amount
+func sortOrderPacks(order *models.Order) {
+	slices.SortFunc(order.Packs, func(a, b models.OrderPack) int {
+		return cmp.Compare(b.Pack.Amount, a.Pack.Amount)
+	})
+}
+
+// maxGreedySteps is a safety fuse for the loops of the greedy strategy, the remainder loop and the merges fail with
+// ErrComputationComplexity once they would compare more packs than this rather than run for an unreasonable time.
+// It's only reached with thousands of pack sizes.
+const maxGreedySteps = 1 << 24
+
+// greedyFuse counts the pack comparisons of a loop of the greedy strategy
+type greedyFuse int
+
+// burn adds the comparisons of the next iteration before it runs, failing if they blow the fuse
+func (f *greedyFuse) burn(steps int) error {
+	if *f += greedyFuse(steps); *f > maxGreedySteps {
+		return ErrComputationComplexity
+	}
+	return nil
+}
+
+// addPackForRemainingItems covers the remaining items with the smallest pack. If a positive maxQuantity caps the
+// packs, it uses the smallest pack which is below the cap and covers the remaining items on its own instead.
+// If no such pack is left, it adds the largest packs below the cap and re-evaluates the rest until the items are
+// covered, or fails with ErrUnsatisfiable once every size is at the cap.
+func addPackForRemainingItems(remainingItems int, packs []*models.Pack, order *models.Order, maxQuantity int) (*models.Order, error) {
+	if remainingItems <= 0 {
+		return order, nil
+	}
+	if maxQuantity <= 0 {
+		return addFillerPacks(remainingItems, packs[len(packs)-1], order), nil
+	}
+
+	var fuse greedyFuse
+	for remainingItems > 0 {
+		if err := fuse.burn(len(packs)); err != nil {
+			return nil, err
+		}
+		var largest *models.Pack
+		covered := false
+		for i := len(packs) - 1; i >= 0; i-- {
+			pack := packs[i]
+			if quantityOfSize(order, pack.Amount) >= maxQuantity {
+				continue
+			}
+			if pack.Amount >= remainingItems {
+				order = addFillerPacks(remainingItems, pack, order)
+				covered = true
+				break
+			}
+			largest = pack
+		}
+		if covered {
+			return order, nil
+		}
+		if largest == nil {
+			return nil, ErrUnsatisfiable
+		}
+
+		// The largest pack doesn't cover the items on its own, add as many as the cap allows
+		quantity := min(maxQuantity-quantityOfSize(order, largest.Amount), remainingItems/largest.Amount)
+		order = addFillerPacks(quantity*largest.Amount, largest, order)
+		remainingItems -= quantity * largest.Amount
+	}
+
+	return order, nil
+}
+
+// dropRedundantPacks removes the packs the order holds beyond its requested items, the largest first, as long as the
+// rest still holds the requested items
+func dropRedundantPacks(packs []*models.Pack, order *models.Order, trace *Trace) *models.Order {
+	for _, pack := range packs {
+		for i := range order.Packs {
+			op := &order.Packs[i]
+			if op.Pack.Amount != pack.Amount {
+				continue
+			}
+			quantity := min(op.Quantity, (order.TotalItems-order.RequestedItems)/pack.Amount)
+			if quantity <= 0 {
+				continue
+			}
+			op.Quantity -= quantity
+			op.Subtotal = op.Quantity * pack.Amount
+			order.TotalItems -= quantity * pack.Amount
+			trace.add(models.TraceStep{Action: models.TraceDrop, Amount: pack.Amount, Quantity: quantity})
+		}
+	}
+	order.Packs = slices.DeleteFunc(order.Packs, func(op models.OrderPack) bool { return op.Quantity == 0 })
+
+	return order
+}
+
+// addFillerPacks covers the remaining items with as many filler packs as needed
+func addFillerPacks(remainingItems int, filler *models.Pack, order *models.Order) *models.Order {
+	if remainingItems <= 0 {
+		return order
+	}
+	quantity := (remainingItems + filler.Amount - 1) / filler.Amount
+	order.TotalItems += quantity * filler.Amount
+
+	// Add to the existing entry if the filler size is already used, so each size appears only once
+	for i := range order.Packs {
+		if order.Packs[i].Pack.Amount == filler.Amount {
+			order.Packs[i].Quantity += quantity
+			order.Packs[i].Subtotal = order.Packs[i].Quantity * filler.Amount
+			return order
+		}
+	}
+
+	order.Packs = append(order.Packs, models.OrderPack{
+		Quantity: quantity,
+		Pack:     filler,
+		Subtotal: quantity * filler.Amount,
+	})
+
+	return order
+}
+
+// findPack returns the pack with the specified amount or nil if there is none
+func findPack(packs []*models.Pack, amount int) *models.Pack {
+	for _, p := range packs {
+		if p.Amount == amount {
+			return p
+		}
+	}
+	return nil
+}
+
+// mergePacks replaces smaller packs with larger ones holding the same number of items until nothing can be merged.
+// Every merge replaces at least 2 packs with 1, so the loop always terminates, and as all the possible multiples
+// of a size are merged at once, chain merges (e.g., 250+250=500, then 500+500=1000) take one pass per link.
+// The packs must be sorted in descending order by amount. A positive maxQuantity caps the number of packs of every size.
+// Every merge is recorded into a non-nil trace. It fails with ErrComputationComplexity if the merges blow the fuse.
+func mergePacks(packs []*models.Pack, order *models.Order, maxQuantity int, trace *Trace) error {
+	var fuse greedyFuse
+	for {
+		// Both attempts compare every pack with every order pack at most
+		if err := fuse.burn(2 * len(packs) * len(order.Packs)); err != nil {
+			return err
+		}
+		step, merged := tryMergeSameSizePacks(packs, order, maxQuantity)
+		if !merged {
+			step, merged = tryMergeDifferentSizePacks(packs, order, maxQuantity)
+		}
+		if !merged {
+			return nil
+		}
+		trace.add(step)
+	}
+}
+
+// quantityOfSize returns the total quantity of the packs of the specified size in the order
+func quantityOfSize(order *models.Order, size int) int {
+	quantity := 0
+	for _, op := range order.Packs {
+		if op.Pack.Amount == size {
+			quantity += op.Quantity
+		}
+	}
+	return quantity
+}
+
+// tryMergeSameSizePacks merges the packs of the same size, even if they're split across several entries,
+// trying the smallest target packs first
+func tryMergeSameSizePacks(packs []*models.Pack, order *models.Order, maxQuantity int) (models.TraceStep, bool) {
+	for i := len(packs) - 1; i >= 0; i-- {
+		targetPack := packs[i]
+		for _, op := range order.Packs {
+			size := op.Pack.Amount
+			if targetPack.Amount <= size || targetPack.Amount%size != 0 {
+				continue
+			}
+
+			ratio := targetPack.Amount / size
+			if merged := mergeableQuantity(order, targetPack, quantityOfSize(order, size)/ratio, maxQuantity); merged > 0 {
+				return mergePack(order, size, targetPack, merged*ratio), true
+			}
+		}
+	}
+	return models.TraceStep{}, false
+}
+
+func tryMergeDifferentSizePacks(packs []*models.Pack, order *models.Order, maxQuantity int) (models.TraceStep, bool) {
+	for i := len(packs) - 1; i >= 0; i-- {
+		targetPack := packs[i]
+		for _, orderPack := range order.Packs {
+			smallSize := orderPack.Pack.Amount
+			if targetPack.Amount <= smallSize {
+				continue
+			}
+
+			if targetPack.Amount%smallSize != 0 {
+				continue
+			}
+			ratio := targetPack.Amount / smallSize
+			if merged := mergeableQuantity(order, targetPack, orderPack.Quantity/ratio, maxQuantity); merged > 0 {
+				return mergePack(order, smallSize, targetPack, merged*ratio), true
+			}
+		}
+	}
+	return models.TraceStep{}, false
+}
+
+// mergeableQuantity limits the number of target packs a merge can produce to keep the target below a positive maxQuantity
+func mergeableQuantity(order *models.Order, targetPack *models.Pack, quantity, maxQuantity int) int {
+	if maxQuantity > 0 {
+		quantity = min(quantity, maxQuantity-quantityOfSize(order, targetPack.Amount))
+	}
+	return quantity
+}
+
+// mergePack replaces the specified quantity of packs of fromSize with the packs of toPack holding the same items.
+// The quantity must be a multiple of the packs of fromSize fitting into toPack. It returns the merge as a trace step.
+func mergePack(order *models.Order, fromSize int, toPack *models.Pack, quantity int) models.TraceStep {
+	merged := quantity * fromSize / toPack.Amount
+
+	// Remove smaller packs, filtering in place as the packs are only ever moved towards the start
+	newPacks := order.Packs[:0]
+	remainingToRemove := quantity
+
+	for _, p := range order.Packs {
+		if p.Pack.Amount == fromSize {
+			// The packs removed from this entry must be counted before its quantity is reduced
+			removed := min(remainingToRemove, p.Quantity)
+			remainingToRemove -= removed
+			if p.Quantity > removed {
+				p.Quantity -= removed
+				p.Subtotal = p.Quantity * p.Pack.Amount
+				newPacks = append(newPacks, p)
+			}
+		} else {
+			newPacks = append(newPacks, p)
+		}
+	}
+
+	// Add or update larger pack
+	found := false
+	for i := range newPacks {
+		if newPacks[i].Pack.Amount == toPack.Amount {
+			newPacks[i].Quantity += merged
+			newPacks[i].Subtotal = newPacks[i].Quantity * toPack.Amount
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		newPacks = append(newPacks, models.OrderPack{
+			Quantity: merged,
+			Pack:     toPack,
+			Subtotal: merged * toPack.Amount,
+		})
+	}
+
+	order.Packs = newPacks
+
+	return models.TraceStep{Action: models.TraceMerge, Amount: toPack.Amount, Quantity: merged, FromAmount: fromSize, FromQuantity: quantity}
+}
+
+// min was added for readability, don't want to deal with math.Min for ints w/o a generics version
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// useFullPacks tries to use full packs for the requested items, but can leave some items unfulfilled if no pack fits exactly.
+// A positive maxQuantity caps the number of packs of every size, the rest falls through to the smaller sizes.
+func useFullPacks(packs []*models.Pack, order *models.Order, maxQuantity int) (int, *models.Order) {
+	remainingItems := order.RequestedItems
+
+	for _, pack := range packs {
+		if pack.Amount <= remainingItems {
+			quantity := remainingItems / pack.Amount
+			if maxQuantity > 0 {
+				quantity = min(quantity, maxQuantity)
+			}
+			if quantity > 0 {
+				order.Packs = append(order.Packs, models.OrderPack{
+					Quantity: quantity,
+					Pack:     pack,
+					Subtotal: quantity * pack.Amount,
+				})
+				order.TotalItems += quantity * pack.Amount
+				remainingItems -= quantity * pack.Amount
+			}
+		}
+	}
+	return remainingItems, order
+}
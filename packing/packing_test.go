@@ -0,0 +1,714 @@
+package packing
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+	"testing/quick"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPack(t *testing.T) {
+	// Duplicates and unsorted amounts are fine
+	order, err := Pack([]int{250, 1000, 500, 500}, 1001)
+	assert.NoError(t, err)
+	assert.Equal(t, 1250, order.TotalItems)
+	assert.Equal(t, 249, order.OverpackedItems)
+
+	_, err = Pack(nil, 100)
+	assert.Equal(t, ErrNoPacksAvailable, err)
+
+	_, err = Pack([]int{250, 0}, 100)
+	assert.Equal(t, ErrInvalidAmount, err)
+
+	// Filler must be one of the packs
+	order, err = Pack([]int{250, 500, 1000}, 1001, WithFillerPack(500))
+	assert.NoError(t, err)
+	assert.Equal(t, 1500, order.TotalItems)
+
+	_, err = Pack([]int{250, 500, 1000}, 1001, WithFillerPack(300))
+	assert.Equal(t, ErrFillerNotFound, err)
+}
+
+func TestAddPackForRemainingItems(t *testing.T) {
+	packs := []*models.Pack{
+		{Amount: 500},
+		{Amount: 250},
+	}
+	order := &models.Order{
+		RequestedItems: 600,
+		TotalItems:     500,
+		Packs: []models.OrderPack{
+			{
+				Quantity: 1,
+				Pack:     &models.Pack{Amount: 500},
+			},
+		},
+	}
+
+	// Test adding a pack for remaining items
+	result, err := addPackForRemainingItems(100, packs, order, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 750, result.TotalItems)
+	assert.Len(t, result.Packs, 2)
+	assert.Equal(t, 250, result.Packs[1].Pack.Amount)
+	assert.Equal(t, 1, result.Packs[1].Quantity)
+
+	// Test with no remaining items
+	order = &models.Order{
+		RequestedItems: 500,
+		TotalItems:     500,
+		Packs: []models.OrderPack{
+			{
+				Quantity: 1,
+				Pack:     &models.Pack{Amount: 500},
+			},
+		},
+	}
+
+	result, err = addPackForRemainingItems(0, packs, order, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 500, result.TotalItems)
+	assert.Len(t, result.Packs, 1)
+}
+
+func TestUseFullPacks(t *testing.T) {
+	packs := []*models.Pack{
+		{Amount: 5000},
+		{Amount: 2000},
+		{Amount: 1000},
+		{Amount: 500},
+		{Amount: 250},
+	}
+
+	order := &models.Order{
+		RequestedItems: 7750,
+	}
+
+	// Test using full packs
+	remaining, result := useFullPacks(packs, order, 0)
+	assert.Equal(t, 0, remaining)
+	assert.Equal(t, 7750, result.TotalItems)
+	assert.Len(t, result.Packs, 4)
+
+	// Verify the packs used
+	packCounts := make(map[int]int)
+	for _, p := range result.Packs {
+		packCounts[p.Pack.Amount] = p.Quantity
+	}
+
+	assert.Equal(t, 1, packCounts[5000])
+	assert.Equal(t, 1, packCounts[2000])
+	assert.Equal(t, 0, packCounts[1000]) // Not used
+	assert.Equal(t, 1, packCounts[500])
+	assert.Equal(t, 1, packCounts[250])
+
+	// Test with remaining items
+	order = &models.Order{
+		RequestedItems: 7760,
+	}
+
+	remaining, result = useFullPacks(packs, order, 0)
+	assert.Equal(t, 10, remaining)
+	assert.Equal(t, 7750, result.TotalItems)
+}
+
+func TestMergePacks(t *testing.T) {
+	// Packs of different sizes
+	packs := []*models.Pack{
+		{Amount: 1000},
+		{Amount: 500},
+		{Amount: 250},
+	}
+
+	// Create an order with multiple small packs
+	order := &models.Order{
+		RequestedItems: 1000,
+		TotalItems:     1000,
+		Packs: []models.OrderPack{
+			{
+				Quantity: 2,
+				Pack:     &models.Pack{Amount: 250},
+			},
+			{
+				Quantity: 1,
+				Pack:     &models.Pack{Amount: 500},
+			},
+		},
+	}
+
+	// Test merging packs
+	mergePacks(packs, order, 0, nil)
+
+	// Verify that 2x250 packs were merged into 1x500 pack
+	// and 1x500 + 1x500 were merged into 1x1000 pack
+	assert.Len(t, order.Packs, 1)
+	assert.Equal(t, 1000, order.Packs[0].Pack.Amount)
+	assert.Equal(t, 1, order.Packs[0].Quantity)
+
+	// Test with packs that can't be merged
+	order = &models.Order{
+		RequestedItems: 750,
+		TotalItems:     750,
+		Packs: []models.OrderPack{
+			{
+				Quantity: 1,
+				Pack:     &models.Pack{Amount: 500},
+			},
+			{
+				Quantity: 1,
+				Pack:     &models.Pack{Amount: 250},
+			},
+		},
+	}
+
+	mergePacks(packs, order, 0, nil)
+
+	// Verify that packs remain unchanged (can't merge 500+250 into any available pack)
+	assert.Len(t, order.Packs, 2)
+
+	// Test with single quantity packs (should not be merged)
+	order = &models.Order{
+		RequestedItems: 500,
+		TotalItems:     500,
+		Packs: []models.OrderPack{
+			{
+				Quantity: 1,
+				Pack:     &models.Pack{Amount: 250},
+			},
+			{
+				Quantity: 1,
+				Pack:     &models.Pack{Amount: 250},
+			},
+		},
+	}
+
+	mergePacks(packs, order, 0, nil)
+
+	// Verify that packs were merged (2x250 into 1x500)
+	assert.Len(t, order.Packs, 1)
+	assert.Equal(t, 500, order.Packs[0].Pack.Amount)
+	assert.Equal(t, 1, order.Packs[0].Quantity)
+}
+
+func TestMergePacksSubtotal(t *testing.T) {
+	packs := []*models.Pack{
+		{Amount: 1000},
+		{Amount: 500},
+		{Amount: 250},
+	}
+	order := &models.Order{
+		RequestedItems: 1000,
+		TotalItems:     1000,
+		Packs: []models.OrderPack{
+			{Quantity: 2, Pack: &models.Pack{Amount: 250}, Subtotal: 500},
+			{Quantity: 1, Pack: &models.Pack{Amount: 500}, Subtotal: 500},
+		},
+	}
+	mergePacks(packs, order, 0, nil)
+	assert.Len(t, order.Packs, 1)
+	assert.Equal(t, 1000, order.Packs[0].Subtotal)
+}
+
+func TestPackDeterministicOrdering(t *testing.T) {
+	for _, requested := range []int{1, 251, 1001, 1750, 3333, 12001} {
+		order, err := Pack([]int{250, 500, 1000, 2000, 5000}, requested)
+		assert.NoError(t, err)
+
+		// Packs are sorted in descending order by amount
+		for i := 1; i < len(order.Packs); i++ {
+			assert.Greater(t, order.Packs[i-1].Pack.Amount, order.Packs[i].Pack.Amount)
+		}
+
+		// Equivalent computations produce the same output
+		again, err := Pack([]int{5000, 250, 1000, 500, 2000, 250}, requested)
+		assert.NoError(t, err)
+		assert.Equal(t, order, again)
+	}
+
+	// The filler size is already used by the full packs, it must not show up twice
+	order, err := Pack([]int{250, 1000}, 1001, WithFillerPack(1000))
+	assert.NoError(t, err)
+	assert.Len(t, order.Packs, 1)
+	assert.Equal(t, 2, order.Packs[0].Quantity)
+
+	order, err = Pack([]int{250, 1000}, 1251)
+	assert.NoError(t, err)
+	assert.Len(t, order.Packs, 2)
+	assert.Equal(t, 1000, order.Packs[0].Pack.Amount)
+	assert.Equal(t, 250, order.Packs[1].Pack.Amount)
+	assert.Equal(t, 2, order.Packs[1].Quantity)
+}
+
+func TestPackWithMaxOverpack(t *testing.T) {
+	packs := []int{250, 500, 1000}
+
+	// 1001 items overpack by 249
+	_, err := Pack(packs, 1001, WithMaxOverpack(249))
+	assert.NoError(t, err)
+	_, err = Pack(packs, 1001, WithMaxOverpack(248))
+	assert.Equal(t, ErrOverpackExceeded, err)
+
+	// Zero means no overpack at all
+	_, err = Pack(packs, 1000, WithMaxOverpack(0))
+	assert.NoError(t, err)
+	_, err = Pack(packs, 1001, WithMaxOverpack(0))
+	assert.Equal(t, ErrOverpackExceeded, err)
+}
+
+func TestPackWithMaxOverpackPercent(t *testing.T) {
+	packs := []int{250, 500, 1000}
+
+	// 1001 items overpacked by 249 is ~24.88%
+	_, err := Pack(packs, 1001, WithMaxOverpackPercent(25))
+	assert.NoError(t, err)
+	_, err = Pack(packs, 1001, WithMaxOverpackPercent(24.8))
+	assert.Equal(t, ErrOverpackExceeded, err)
+
+	// 1 item overpacked by 249 is 24900%
+	_, err = Pack(packs, 1, WithMaxOverpackPercent(100))
+	assert.Equal(t, ErrOverpackExceeded, err)
+
+	// Both limits apply
+	_, err = Pack(packs, 1001, WithMaxOverpack(1000), WithMaxOverpackPercent(10))
+	assert.Equal(t, ErrOverpackExceeded, err)
+}
+
+// fragmentedOrder returns 20 pack sizes doubling from 1 and an order using 3 packs of every size but the largest,
+// so that every size can be merged into the next one
+func fragmentedOrder() ([]*models.Pack, *models.Order) {
+	packs := make([]*models.Pack, 20)
+	for i := range packs {
+		packs[len(packs)-1-i] = &models.Pack{Amount: 1 << i}
+	}
+
+	order := &models.Order{Packs: make([]models.OrderPack, 0, len(packs))}
+	for _, pack := range packs[1:] {
+		order.Packs = append(order.Packs, models.OrderPack{Quantity: 3, Pack: pack, Subtotal: 3 * pack.Amount})
+		order.TotalItems += 3 * pack.Amount
+	}
+	order.RequestedItems = order.TotalItems
+
+	return packs, order
+}
+
+func TestMergePacksFragmented(t *testing.T) {
+	packs, order := fragmentedOrder()
+	mergePacks(packs, order, 0, nil)
+
+	// Every size doubles the previous one, so a fully merged order has at most one pack of every size but the largest
+	total := 0
+	seen := make(map[int]bool)
+	for _, op := range order.Packs {
+		total += op.Subtotal
+		assert.Equal(t, op.Quantity*op.Pack.Amount, op.Subtotal)
+		assert.False(t, seen[op.Pack.Amount], "size %d appears twice", op.Pack.Amount)
+		seen[op.Pack.Amount] = true
+		if op.Pack.Amount != packs[0].Amount {
+			assert.Equal(t, 1, op.Quantity, "size %d", op.Pack.Amount)
+		}
+	}
+	assert.Equal(t, order.TotalItems, total)
+}
+
+func BenchmarkMergePacks(b *testing.B) {
+	b.Run("default", func(b *testing.B) {
+		packs := []*models.Pack{{Amount: 5000}, {Amount: 2000}, {Amount: 1000}, {Amount: 500}, {Amount: 250}}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			order := &models.Order{Packs: []models.OrderPack{
+				{Quantity: 1, Pack: packs[2], Subtotal: 1000},
+				{Quantity: 1, Pack: packs[3], Subtotal: 500},
+				{Quantity: 2, Pack: packs[4], Subtotal: 500},
+			}}
+			mergePacks(packs, order, 0, nil)
+		}
+	})
+	b.Run("fragmented", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			packs, order := fragmentedOrder()
+			mergePacks(packs, order, 0, nil)
+		}
+	})
+}
+
+func TestMergePacksSplitEntries(t *testing.T) {
+	packs := []*models.Pack{{Amount: 1000}, {Amount: 250}}
+	order := &models.Order{TotalItems: 3000, Packs: []models.OrderPack{
+		{Quantity: 6, Pack: packs[1], Subtotal: 1500},
+		{Quantity: 6, Pack: packs[1], Subtotal: 1500},
+	}}
+
+	// The cap allows a single merge, which takes 4 packs from the first entry and leaves the second one as it is
+	assert.NoError(t, mergePacks(packs, order, 1, nil))
+	assert.Equal(t, []models.OrderPack{
+		{Quantity: 2, Pack: packs[1], Subtotal: 500},
+		{Quantity: 6, Pack: packs[1], Subtotal: 1500},
+		{Quantity: 1, Pack: packs[0], Subtotal: 1000},
+	}, order.Packs)
+}
+
+// mergeCase is a random pack set with a random order of its packs, for the property tests of mergePacks
+type mergeCase struct {
+	packs       []*models.Pack
+	order       *models.Order
+	maxQuantity int
+}
+
+// Generate returns up to 6 pack sizes, some of them multiples of the others so that they merge, and an order holding
+// random quantities of them, sometimes split across several entries of the same size
+func (mergeCase) Generate(rand *rand.Rand, _ int) reflect.Value {
+	var amounts []int
+	for range 1 + rand.Intn(6) {
+		amount := 1 + rand.Intn(100)
+		if len(amounts) > 0 && rand.Intn(2) == 0 {
+			amount = amounts[rand.Intn(len(amounts))] * (2 + rand.Intn(4))
+		}
+		if !slices.Contains(amounts, amount) {
+			amounts = append(amounts, amount)
+		}
+	}
+	slices.SortFunc(amounts, func(a, b int) int { return b - a })
+	packs := make([]*models.Pack, len(amounts))
+	for i, amount := range amounts {
+		packs[i] = &models.Pack{Amount: amount}
+	}
+
+	order := &models.Order{}
+	for range rand.Intn(10) {
+		pack := packs[rand.Intn(len(packs))]
+		quantity := 1 + rand.Intn(50)
+		order.Packs = append(order.Packs, models.OrderPack{Quantity: quantity, Pack: pack, Subtotal: quantity * pack.Amount})
+		order.TotalItems += quantity * pack.Amount
+	}
+
+	// Half of the cases cap the packs of every size
+	maxQuantity := 0
+	if rand.Intn(2) == 0 {
+		maxQuantity = 1 + rand.Intn(20)
+	}
+	return reflect.ValueOf(mergeCase{packs: packs, order: order, maxQuantity: maxQuantity})
+}
+
+// orderItems returns the items held by the order packs, failing the property if a subtotal is inconsistent
+func orderItems(order *models.Order) (int, bool) {
+	items := 0
+	for _, op := range order.Packs {
+		if op.Quantity <= 0 || op.Subtotal != op.Quantity*op.Pack.Amount {
+			return 0, false
+		}
+		items += op.Subtotal
+	}
+	return items, true
+}
+
+func TestMergePacksProperties(t *testing.T) {
+	config := &quick.Config{MaxCount: 2000}
+
+	// The merges move the items between the sizes without changing them, with consistent subtotals
+	keepsItems := func(c mergeCase) bool {
+		items, _ := orderItems(c.order)
+		if err := mergePacks(c.packs, c.order, c.maxQuantity, nil); err != nil {
+			return false
+		}
+		merged, ok := orderItems(c.order)
+		return ok && merged == items && merged == c.order.TotalItems
+	}
+	assert.NoError(t, quick.Check(keepsItems, config))
+
+	// Every merge replaces several packs with fewer ones
+	neverAddsPacks := func(c mergeCase) bool {
+		packCount := c.order.PackCount()
+		trace := &Trace{}
+		if err := mergePacks(c.packs, c.order, c.maxQuantity, trace); err != nil {
+			return false
+		}
+		for _, step := range trace.Steps {
+			if step.Quantity >= step.FromQuantity {
+				return false
+			}
+		}
+		return c.order.PackCount() <= packCount
+	}
+	assert.NoError(t, quick.Check(neverAddsPacks, config))
+
+	// Without a cap nothing is left to merge: no size holds as many packs as fit into a larger multiple of it
+	mergesAll := func(c mergeCase) bool {
+		if err := mergePacks(c.packs, c.order, 0, nil); err != nil {
+			return false
+		}
+		for _, op := range c.order.Packs {
+			for _, target := range c.packs {
+				if target.Amount > op.Pack.Amount && target.Amount%op.Pack.Amount == 0 &&
+					quantityOfSize(c.order, op.Pack.Amount) >= target.Amount/op.Pack.Amount {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	assert.NoError(t, quick.Check(mergesAll, config))
+}
+
+func TestPackZeroItems(t *testing.T) {
+	order, err := Pack([]int{250, 500}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, order.TotalItems)
+	assert.NotNil(t, order.Packs)
+	assert.Empty(t, order.Packs)
+}
+
+func TestPackItemsOutOfRange(t *testing.T) {
+	for _, strategy := range Strategies {
+		_, err := Pack([]int{250, 500, 1000}, math.MaxInt, WithStrategy(strategy))
+		assert.ErrorIs(t, err, ErrItemsOutOfRange, strategy)
+		_, err = Pack([]int{250, 500, 1000}, math.MaxInt-999, WithStrategy(strategy))
+		assert.ErrorIs(t, err, ErrItemsOutOfRange, strategy)
+	}
+
+	// The largest request the packs allow is packed without overflowing
+	order, err := Pack([]int{250, 500, 1000}, math.MaxInt-1000)
+	assert.NoError(t, err)
+	assert.Positive(t, order.TotalItems)
+	assert.Less(t, order.OverpackedItems, 250)
+}
+
+func TestPackWithUnusedPacks(t *testing.T) {
+	order, err := Pack([]int{250, 500, 1000, 2000, 5000}, 7750, WithUnusedPacks())
+	assert.NoError(t, err)
+	assert.Len(t, order.Packs, 5)
+
+	// All packs are listed in descending order, the unused ones with zero quantity
+	expected := map[int]int{5000: 1, 2000: 1, 1000: 0, 500: 1, 250: 1}
+	for i, op := range order.Packs {
+		if i > 0 {
+			assert.Greater(t, order.Packs[i-1].Pack.Amount, op.Pack.Amount)
+		}
+		assert.Equal(t, expected[op.Pack.Amount], op.Quantity, "size %d", op.Pack.Amount)
+		assert.Equal(t, op.Quantity*op.Pack.Amount, op.Subtotal)
+	}
+	assert.Equal(t, 7750, order.TotalItems)
+
+	// Without the option only the used packs are listed
+	order, err = Pack([]int{250, 500, 1000, 2000, 5000}, 7750)
+	assert.NoError(t, err)
+	assert.Len(t, order.Packs, 4)
+}
+
+func TestPackWithMaxQuantityPerSize(t *testing.T) {
+	packs := []int{250, 500, 1000}
+
+	// The cap forces the use of smaller sizes, which can't be merged into the capped size
+	order, err := Pack(packs, 3000, WithMaxQuantityPerSize(2))
+	assert.NoError(t, err)
+	assert.Equal(t, 3000, order.TotalItems)
+	assert.Len(t, order.Packs, 2)
+	assert.Equal(t, 1000, order.Packs[0].Pack.Amount)
+	assert.Equal(t, 2, order.Packs[0].Quantity)
+	assert.Equal(t, 500, order.Packs[1].Pack.Amount)
+	assert.Equal(t, 2, order.Packs[1].Quantity)
+
+	// Without the cap the same request uses the largest size only
+	order, err = Pack(packs, 3000)
+	assert.NoError(t, err)
+	assert.Len(t, order.Packs, 1)
+	assert.Equal(t, 3, order.Packs[0].Quantity)
+
+	// The remainder is covered by a pack below the cap
+	order, err = Pack(packs, 2600, WithMaxQuantityPerSize(2))
+	assert.NoError(t, err)
+	assert.Equal(t, 2750, order.TotalItems)
+
+	// Not enough packs within the cap
+	_, err = Pack(packs, 5000, WithMaxQuantityPerSize(2))
+	assert.ErrorIs(t, err, ErrUnsatisfiable)
+
+	// The filler respects the cap too, the rest falls back to the other packs
+	order, err = Pack(packs, 1001, WithMaxQuantityPerSize(1), WithFillerPack(1000))
+	assert.NoError(t, err)
+	assert.Equal(t, 1250, order.TotalItems)
+
+	_, err = Pack([]int{250, 1000}, 1300, WithMaxQuantityPerSize(1), WithFillerPack(1000))
+	assert.ErrorIs(t, err, ErrUnsatisfiable)
+}
+
+func TestPackWithRequiredPack(t *testing.T) {
+	packs := []int{250, 500, 1000}
+
+	// Requiring a large pack increases the overpack but the order stays valid
+	order, err := Pack(packs, 250, WithRequiredPack(1000))
+	assert.NoError(t, err)
+	assert.Equal(t, 250, order.RequestedItems)
+	assert.Equal(t, 1000, order.TotalItems)
+	assert.Equal(t, 750, order.OverpackedItems)
+	assert.Equal(t, 300.0, order.OverpackPercent)
+
+	// The rest of the items is packed as usual
+	order, err = Pack(packs, 1200, WithRequiredPack(1000), WithStrategy(StrategyMinOverpack))
+	assert.NoError(t, err)
+	assert.Equal(t, 1250, order.TotalItems)
+	assert.Equal(t, 1000, order.Packs[0].Pack.Amount)
+	assert.Equal(t, 250, order.Packs[1].Pack.Amount)
+
+	// A required pack the strategy would use anyway isn't added twice
+	order, err = Pack(packs, 1000, WithRequiredPack(1000))
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, order.TotalItems)
+	assert.Len(t, order.Packs, 1)
+
+	var trace Trace
+	_, err = Pack(packs, 250, WithRequiredPack(500), WithTrace(&trace))
+	assert.NoError(t, err)
+	assert.Equal(t, models.TraceStep{Action: models.TraceRequired, Amount: 500, Quantity: 1}, trace.Steps[len(trace.Steps)-1])
+
+	// The required pack counts towards the cap of its size
+	_, err = Pack(packs, 500, WithRequiredPack(250), WithMaxQuantityPerSize(1))
+	assert.ErrorIs(t, err, ErrUnsatisfiable)
+
+	_, err = Pack(packs, 250, WithRequiredPack(300))
+	assert.ErrorIs(t, err, ErrRequiredPackNotFound)
+}
+
+func TestPackWithOverpackBelowSmallestPack(t *testing.T) {
+	// Without constraints every strategy overpacks by less than the smallest pack
+	packSets := [][]int{{250, 500, 1000, 2000, 5000}, {4, 6, 9}, {23, 31, 53}}
+	for _, packs := range packSets {
+		for _, strategy := range Strategies {
+			for items := 1; items <= 300; items++ {
+				_, err := Pack(packs, items, WithStrategy(strategy), WithOverpackBelowSmallestPack())
+				// Max-under never overpacks, but nothing fits under less items than the smallest pack
+				if strategy == StrategyMaxUnder && items < packs[0] {
+					assert.ErrorIs(t, err, ErrUnsatisfiable)
+					continue
+				}
+				assert.NoError(t, err, "packs %v, strategy %s, items %d", packs, strategy, items)
+			}
+		}
+	}
+
+	packs := []int{250, 500, 1000}
+
+	// 249 is the most the smallest pack allows
+	order, err := Pack(packs, 1001, WithOverpackBelowSmallestPack())
+	assert.NoError(t, err)
+	assert.Equal(t, 249, order.OverpackedItems)
+
+	// The constraints can push the overpack beyond it
+	_, err = Pack(packs, 1001, WithFillerPack(1000))
+	assert.NoError(t, err)
+	_, err = Pack(packs, 1001, WithFillerPack(1000), WithOverpackBelowSmallestPack())
+	assert.ErrorIs(t, err, ErrUnsatisfiable)
+	_, err = Pack(packs, 250, WithRequiredPack(1000), WithOverpackBelowSmallestPack())
+	assert.ErrorIs(t, err, ErrUnsatisfiable)
+	order, err = Pack(packs, 1200, WithRequiredPack(1000), WithOverpackBelowSmallestPack())
+	assert.NoError(t, err)
+	assert.Equal(t, 50, order.OverpackedItems)
+
+	// The mode is part of the options key
+	assert.NotEqual(t, OptionsKey(), OptionsKey(WithOverpackBelowSmallestPack()))
+}
+
+func TestPackCappedTopUpDropsRedundantPacks(t *testing.T) {
+	packs := []int{250, 500, 1000}
+
+	// The fill uses the 500 and the 250, the top-up needs the 1000 which holds the items on its own
+	var trace Trace
+	order, err := Pack(packs, 751, WithMaxQuantityPerSize(1), WithTrace(&trace))
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, order.TotalItems)
+	assert.Equal(t, 249, order.OverpackedItems)
+	assert.Len(t, order.Packs, 1)
+	assert.Equal(t, 1000, order.Packs[0].Pack.Amount)
+	assert.Equal(t, []models.TraceStep{
+		{Action: models.TraceFill, Amount: 500, Quantity: 1},
+		{Action: models.TraceFill, Amount: 250, Quantity: 1},
+		{Action: models.TraceTopUp, Amount: 1000, Quantity: 1},
+		{Action: models.TraceDrop, Amount: 500, Quantity: 1},
+		{Action: models.TraceDrop, Amount: 250, Quantity: 1},
+	}, trace.Steps)
+
+	// Only the packs beyond the requested items are dropped
+	order, err = Pack(packs, 1251, WithMaxQuantityPerSize(1))
+	assert.NoError(t, err)
+	assert.Equal(t, 1500, order.TotalItems)
+}
+
+func TestAddPackForRemainingItemsCapped(t *testing.T) {
+	packs := []*models.Pack{{Amount: 1000}, {Amount: 500}, {Amount: 250}}
+
+	// No pack below the cap covers 1400 items on its own: a 1000 first, then a 500 for the rest
+	order := &models.Order{Packs: []models.OrderPack{{Quantity: 1, Pack: packs[2], Subtotal: 250}}, TotalItems: 250}
+	order, err := addPackForRemainingItems(1400, packs, order, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1750, order.TotalItems)
+	assert.Len(t, order.Packs, 3)
+	for _, op := range order.Packs {
+		assert.Equal(t, 1, op.Quantity)
+	}
+
+	// A 1000 and a 500 aren't enough for 1600 items
+	order = &models.Order{Packs: []models.OrderPack{{Quantity: 1, Pack: packs[2], Subtotal: 250}}, TotalItems: 250}
+	_, err = addPackForRemainingItems(1600, packs, order, 1)
+	assert.ErrorIs(t, err, ErrUnsatisfiable)
+
+	// The cap allows several packs of the largest size at once
+	order = &models.Order{Packs: []models.OrderPack{}}
+	order, err = addPackForRemainingItems(2100, packs, order, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2250, order.TotalItems)
+
+	// Filler packs beyond the cap fall back to the smallest pack covering the rest, a second 1000 which holds the
+	// items without the filler packs
+	packed, err := Pack([]int{250, 1000}, 1900, WithMaxQuantityPerSize(3), WithFillerPack(250))
+	assert.NoError(t, err)
+	assert.Equal(t, 2000, packed.TotalItems)
+}
+
+func TestGreedyFuse(t *testing.T) {
+	// One pack of each of thousands of sizes leaves thousands of order packs for the merges to compare
+	packs := make([]int, 3000)
+	items := 0
+	for i := range packs {
+		packs[i] = i + 1
+		items += i + 1
+	}
+
+	_, err := Pack(packs, items, WithMaxQuantityPerSize(1))
+	assert.ErrorIs(t, err, ErrComputationComplexity)
+
+	// Fewer sizes are merged as usual
+	order, err := Pack(packs[:1000], 1000*1001/2, WithMaxQuantityPerSize(1))
+	assert.NoError(t, err)
+	assert.Equal(t, 1000*1001/2, order.TotalItems)
+}
+
+func TestOverpackPercent(t *testing.T) {
+	tests := []struct {
+		overpacked int
+		requested  int
+		percent    float64
+	}{
+		{0, 0, 0},
+		{0, 250, 0},
+		{249, 1001, 24.88}, // 24.875... rounds up
+		{1, 3, 33.33},      // 33.333...
+		{2, 3, 66.67},      // 66.666...
+		{1, 8, 12.5},       // exact
+		{249, 1, 24900},    // overpack larger than the request
+		{1, 200_000, 0},    // 0.0005 rounds down
+		{3, 40_000, 0.01},  // 0.0075 rounds up to the nearest hundredth
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.percent, OverpackPercent(tt.overpacked, tt.requested), "%d/%d", tt.overpacked, tt.requested)
+	}
+
+	order, err := Pack([]int{250, 500, 1000}, 1001)
+	assert.NoError(t, err)
+	assert.Equal(t, 24.88, order.OverpackPercent)
+}
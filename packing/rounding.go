@@ -0,0 +1,91 @@
+package packing
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RoundingMode selects the direction percentages are rounded in
+type RoundingMode string
+
+const (
+	// RoundHalfUp rounds to the nearest value, halves up towards positive infinity
+	RoundHalfUp RoundingMode = "half-up"
+	// RoundFloor rounds down towards negative infinity
+	RoundFloor RoundingMode = "floor"
+	// RoundCeil rounds up towards positive infinity
+	RoundCeil RoundingMode = "ceil"
+)
+
+// MaxRoundingDecimals is the max number of decimal places of a rounding, beyond it the float has no more precision
+const MaxRoundingDecimals = 10
+
+var ErrUnknownRoundingMode = errors.New("unknown rounding mode, must be half-up, floor or ceil")
+
+// Rounding is the policy percentages like the overpack percent are rounded with.
+// The zero Rounding is DefaultRounding.
+type Rounding struct {
+	Mode     RoundingMode
+	Decimals int
+}
+
+// DefaultRounding rounds half up to 2 decimal places
+var DefaultRounding = Rounding{Mode: RoundHalfUp, Decimals: 2}
+
+// ParseRoundingMode returns the rounding mode with the specified name
+func ParseRoundingMode(name string) (RoundingMode, error) {
+	switch mode := RoundingMode(strings.ToLower(name)); mode {
+	case RoundHalfUp, RoundFloor, RoundCeil:
+		return mode, nil
+	default:
+		return "", ErrUnknownRoundingMode
+	}
+}
+
+// Percent returns the part relative to the whole in percent, rounded. It's 0 when the whole is 0.
+// The percentage is rounded exactly, so 6667 of 20000 is 33.335% and rounds half up to 33.34.
+func (r Rounding) Percent(part, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return r.round(new(big.Rat).SetFrac(big.NewInt(int64(part)*100), big.NewInt(int64(whole))))
+}
+
+// Round rounds the value as it's written in decimal, so 33.335 rounds half up to 33.34 although the closest
+// float is slightly below it
+func (r Rounding) Round(value float64) float64 {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return value
+	}
+	exact, _ := new(big.Rat).SetString(strconv.FormatFloat(value, 'f', -1, 64))
+	return r.round(exact)
+}
+
+func (r Rounding) round(exact *big.Rat) float64 {
+	if r == (Rounding{}) {
+		r = DefaultRounding
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(r.Decimals)), nil)
+	scaled := new(big.Rat).Mul(exact, new(big.Rat).SetInt(scale))
+
+	// The denominator is positive, so the Euclidean quotient is the floor
+	remainder := new(big.Int)
+	quotient, _ := new(big.Int).DivMod(scaled.Num(), scaled.Denom(), remainder)
+	switch r.Mode {
+	case RoundFloor:
+	case RoundCeil:
+		if remainder.Sign() != 0 {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	default:
+		if remainder.Lsh(remainder, 1).Cmp(scaled.Denom()) >= 0 {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	rounded, _ := new(big.Rat).SetFrac(quotient, scale).Float64()
+	return rounded
+}
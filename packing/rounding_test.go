@@ -0,0 +1,68 @@
+package packing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundingPercent(t *testing.T) {
+	tests := []struct {
+		name     string
+		rounding Rounding
+		part     int
+		whole    int
+		percent  float64
+	}{
+		// 6667 of 20000 is exactly 33.335%
+		{"half up at the boundary", DefaultRounding, 6667, 20000, 33.34},
+		{"floor at the boundary", Rounding{Mode: RoundFloor, Decimals: 2}, 6667, 20000, 33.33},
+		{"ceil at the boundary", Rounding{Mode: RoundCeil, Decimals: 2}, 6667, 20000, 33.34},
+		{"half up below the boundary", DefaultRounding, 66669, 200000, 33.33},
+		{"zero value is the default", Rounding{}, 6667, 20000, 33.34},
+		{"ceil of a third", Rounding{Mode: RoundCeil, Decimals: 1}, 1, 3, 33.4},
+		{"floor of two thirds", Rounding{Mode: RoundFloor, Decimals: 0}, 2, 3, 66},
+		{"half up to integers", Rounding{Mode: RoundHalfUp, Decimals: 0}, 1, 200, 1},
+		{"exact", Rounding{Mode: RoundCeil, Decimals: 2}, 249, 1000, 24.9},
+		{"nothing requested", DefaultRounding, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.percent, tt.rounding.Percent(tt.part, tt.whole))
+		})
+	}
+}
+
+func TestRoundingRound(t *testing.T) {
+	// The closest float to 33.335 is slightly below it, it's still rounded as written
+	assert.Equal(t, 33.34, DefaultRounding.Round(33.335))
+	assert.Equal(t, 33.33, Rounding{Mode: RoundFloor, Decimals: 2}.Round(33.335))
+	assert.Equal(t, 1.01, DefaultRounding.Round(1.005))
+	assert.Equal(t, 2.7, Rounding{Mode: RoundCeil, Decimals: 1}.Round(2.61))
+	// Halves go up towards positive infinity
+	assert.Equal(t, -33.33, DefaultRounding.Round(-33.335))
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	mode, err := ParseRoundingMode("Floor")
+	assert.NoError(t, err)
+	assert.Equal(t, RoundFloor, mode)
+
+	_, err = ParseRoundingMode("bankers")
+	assert.ErrorIs(t, err, ErrUnknownRoundingMode)
+}
+
+func TestPackWithRounding(t *testing.T) {
+	// 5 of 3 items overpacked by 2 is 66.666...%
+	order, err := Pack([]int{5}, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 66.67, order.OverpackPercent)
+
+	order, err = Pack([]int{5}, 3, WithRounding(Rounding{Mode: RoundFloor, Decimals: 1}))
+	assert.NoError(t, err)
+	assert.Equal(t, 66.6, order.OverpackPercent)
+
+	assert.NotEqual(t, OptionsKey(), OptionsKey(WithRounding(Rounding{Mode: RoundFloor, Decimals: 1})))
+	assert.Equal(t, OptionsKey(), OptionsKey(WithRounding(DefaultRounding)))
+}
@@ -0,0 +1,87 @@
+package packing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+)
+
+// maxSizeSubsets bounds the number of subsets of the pack sizes solved by solveFewestSizes
+const maxSizeSubsets = 1 << 12
+
+// solveFewestSizes finds the packing with the least overpack and, among those, the fewest distinct pack sizes
+// and then the fewest packs. The packs must be sorted in descending order by amount.
+//
+// It solves the least overpack for every subset of the sizes, smallest subsets first, and stops at the first size
+// of subsets reaching the least overpack of all packs. A positive maxQuantity caps the packs of every size.
+func solveFewestSizes(ctx context.Context, packs []*models.Pack, requestedItems int, maxQuantity int) (*models.Order, error) {
+	solveSubset := func(subset []*models.Pack) (*models.Order, error) {
+		if maxQuantity > 0 {
+			return solveMinOverpackCapped(ctx, subset, requestedItems, maxQuantity)
+		}
+		return solveMinOverpack(ctx, subset, requestedItems)
+	}
+
+	best, err := solveSubset(packs)
+	if err != nil {
+		return nil, err
+	}
+	leastOverpack := best.TotalItems - requestedItems
+
+	solved := 0
+	for size := 1; size < len(best.Packs); size++ {
+		var found *models.Order
+		err := forEachSubset(packs, size, func(subset []*models.Pack) error {
+			if solved++; solved > maxSizeSubsets {
+				return ErrComputationComplexity
+			}
+			if err := checkContext(ctx); err != nil {
+				return err
+			}
+
+			order, err := solveSubset(subset)
+			switch {
+			// Some subsets can't satisfy the cap or are too large to solve, the others still can
+			case errors.Is(err, ErrUnsatisfiable) || errors.Is(err, ErrComputationComplexity):
+				return nil
+			case err != nil:
+				return err
+			}
+			if order.TotalItems-requestedItems == leastOverpack && (found == nil || order.PackCount() < found.PackCount()) {
+				found = order
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if found != nil {
+			return found, nil
+		}
+	}
+
+	return best, nil
+}
+
+// forEachSubset calls fn with every subset of the packs with the specified number of packs, keeping their order.
+// It stops at the first error returned by fn.
+func forEachSubset(packs []*models.Pack, size int, fn func([]*models.Pack) error) error {
+	subset := make([]*models.Pack, 0, size)
+	var visit func(start int) error
+	visit = func(start int) error {
+		if len(subset) == size {
+			return fn(subset)
+		}
+		// Leave enough packs to complete the subset
+		for i := start; i <= len(packs)-(size-len(subset)); i++ {
+			subset = append(subset, packs[i])
+			if err := visit(i + 1); err != nil {
+				return err
+			}
+			subset = subset[:len(subset)-1]
+		}
+		return nil
+	}
+	return visit(0)
+}
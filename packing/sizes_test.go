@@ -0,0 +1,92 @@
+package packing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFewestSizes(t *testing.T) {
+	defaultPacks := []int{250, 500, 1000, 2000, 5000}
+	tests := []struct {
+		name        string
+		packs       []int
+		items       int
+		minOverpack map[int]int
+		fewestSizes map[int]int
+	}{
+		{"extra pack for a single size", []int{250, 500}, 750, map[int]int{500: 1, 250: 1}, map[int]int{250: 3}},
+		{"many packs for a single size", defaultPacks, 12001, map[int]int{5000: 2, 2000: 1, 250: 1}, map[int]int{250: 49}},
+		{"fewest packs among single sizes", []int{3, 6, 7}, 12, map[int]int{6: 2}, map[int]int{6: 2}},
+		{"two sizes needed", []int{4, 6, 9}, 14, map[int]int{6: 1, 4: 2}, map[int]int{6: 1, 4: 2}},
+		{"overpack goes first", []int{5, 7}, 12, map[int]int{7: 1, 5: 1}, map[int]int{7: 1, 5: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			optimal, err := Pack(tt.packs, tt.items, WithStrategy(StrategyMinOverpack))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.minOverpack, quantitiesOf(optimal.Packs))
+
+			order, err := Pack(tt.packs, tt.items, WithStrategy(StrategyFewestSizes))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.fewestSizes, quantitiesOf(order.Packs))
+			assert.Equal(t, optimal.OverpackedItems, order.OverpackedItems)
+		})
+	}
+}
+
+func TestFewestSizesNeverWorseThanMinOverpack(t *testing.T) {
+	packSets := [][]int{{250, 500, 1000, 2000, 5000}, {4, 6, 9}, {23, 31, 53}, {3, 5}, {7, 11, 13}}
+	for _, packs := range packSets {
+		for items := 1; items <= 300; items++ {
+			optimal, err := Pack(packs, items, WithStrategy(StrategyMinOverpack))
+			assert.NoError(t, err)
+			order, err := Pack(packs, items, WithStrategy(StrategyFewestSizes))
+			assert.NoError(t, err)
+
+			assert.Equal(t, optimal.OverpackedItems, order.OverpackedItems, "packs %v, items %d", packs, items)
+			assert.LessOrEqual(t, len(order.Packs), len(optimal.Packs), "packs %v, items %d", packs, items)
+		}
+	}
+}
+
+func TestFewestSizesWithMaxQuantityPerSize(t *testing.T) {
+	// 3x250 exceeds the cap, 2x500 overpacks
+	order, err := Pack([]int{250, 500}, 750, WithStrategy(StrategyFewestSizes), WithMaxQuantityPerSize(2))
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int{500: 1, 250: 1}, quantitiesOf(order.Packs))
+
+	order, err = Pack([]int{250, 500}, 750, WithStrategy(StrategyFewestSizes), WithMaxQuantityPerSize(3))
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int{250: 3}, quantitiesOf(order.Packs))
+
+	_, err = Pack([]int{250, 500}, 2000, WithStrategy(StrategyFewestSizes), WithMaxQuantityPerSize(2))
+	assert.ErrorIs(t, err, ErrUnsatisfiable)
+}
+
+func TestFewestSizesCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	// The subsets are solved after the context is checked
+	packs := []*models.Pack{{Amount: 500}, {Amount: 250}}
+	_, err := solveFewestSizes(ctx, packs, 750, 0)
+	assert.ErrorIs(t, err, ErrComputationTimeout)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	order, err := solveFewestSizes(t.Context(), packs, 750, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int{250: 3}, quantitiesOf(order.Packs))
+}
+
+// quantitiesOf maps the pack amounts of the order packs to their quantities
+func quantitiesOf(packs []models.OrderPack) map[int]int {
+	quantities := make(map[int]int, len(packs))
+	for _, op := range packs {
+		quantities[op.Pack.Amount] = op.Quantity
+	}
+	return quantities
+}
@@ -0,0 +1,15 @@
+package packing
+
+import "github.com/corel-frim/item-packer-inc/internal/models"
+
+// Trace records how a packing was derived: the initial fill, the top-up, the dropped packs and every merge
+type Trace struct {
+	Steps []models.TraceStep
+}
+
+// add records a step, it does nothing if tracing is off
+func (t *Trace) add(step models.TraceStep) {
+	if t != nil {
+		t.Steps = append(t.Steps, step)
+	}
+}
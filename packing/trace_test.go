@@ -0,0 +1,40 @@
+package packing
+
+import (
+	"testing"
+
+	"github.com/corel-frim/item-packer-inc/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackWithTrace(t *testing.T) {
+	var trace Trace
+	order, err := Pack([]int{250, 500, 1000}, 751, WithTrace(&trace))
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, order.TotalItems)
+
+	// 500 + 250 + 250 for the remaining item, then 2x250 into 500 and 2x500 into 1000
+	assert.Equal(t, []models.TraceStep{
+		{Action: models.TraceFill, Amount: 500, Quantity: 1},
+		{Action: models.TraceFill, Amount: 250, Quantity: 1},
+		{Action: models.TraceTopUp, Amount: 250, Quantity: 1},
+		{Action: models.TraceMerge, Amount: 500, Quantity: 1, FromAmount: 250, FromQuantity: 2},
+		{Action: models.TraceMerge, Amount: 1000, Quantity: 1, FromAmount: 500, FromQuantity: 2},
+	}, trace.Steps)
+
+	// The optimal solver computes the packs at once
+	trace = Trace{}
+	_, err = Pack([]int{4, 6, 9}, 11, WithStrategy(StrategyMinOverpack), WithTrace(&trace))
+	assert.NoError(t, err)
+	assert.Equal(t, []models.TraceStep{{Action: models.TraceOptimal, Amount: 6, Quantity: 2}}, trace.Steps)
+
+	// Tracing doesn't change the result
+	untraced, err := Pack([]int{250, 500, 1000}, 751)
+	assert.NoError(t, err)
+	assert.Equal(t, untraced, order)
+}
+
+func TestOptionsKeyCacheable(t *testing.T) {
+	assert.True(t, OptionsKey(WithFillerPack(250)).Cacheable())
+	assert.False(t, OptionsKey(WithTrace(&Trace{})).Cacheable())
+}